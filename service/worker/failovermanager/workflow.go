@@ -25,6 +25,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand"
 	"strings"
 	"time"
 
@@ -100,6 +101,9 @@ type (
 		BatchFailoverSize int
 		// BatchFailoverWaitTimeInSeconds is the waiting time between batch failover
 		BatchFailoverWaitTimeInSeconds int
+		// BatchFailoverWaitJitterInSeconds randomizes the waiting time between batch failover
+		// within [BatchFailoverWaitTimeInSeconds, BatchFailoverWaitTimeInSeconds+BatchFailoverWaitJitterInSeconds]
+		BatchFailoverWaitJitterInSeconds int `json:",omitempty"`
 		// Domains candidates to be failover
 		Domains []string
 		// DrillWaitTime defines the wait time of a failover drill
@@ -275,12 +279,30 @@ func failoverDomainsByBatch(
 		}
 
 		if i != times-1 {
-			workflow.Sleep(ctx, time.Duration(params.BatchFailoverWaitTimeInSeconds)*time.Second)
+			workflow.Sleep(ctx, getBatchFailoverWaitTime(ctx, params))
 		}
 	}
 	return
 }
 
+// getBatchFailoverWaitTime adds a random jitter to the wait between batches,
+// so that the replication load of large failovers is not concentrated at batch boundaries
+func getBatchFailoverWaitTime(ctx workflow.Context, params *FailoverParams) time.Duration {
+	wait := time.Duration(params.BatchFailoverWaitTimeInSeconds) * time.Second
+	if params.BatchFailoverWaitJitterInSeconds <= 0 {
+		return wait
+	}
+	maxJitter := int64(params.BatchFailoverWaitJitterInSeconds) * int64(time.Second)
+	var jitter time.Duration
+	// the jitter is recorded as a side effect to keep the workflow deterministic on replay
+	if err := workflow.SideEffect(ctx, func(ctx workflow.Context) interface{} {
+		return time.Duration(rand.Int63n(maxJitter + 1))
+	}).Get(&jitter); err != nil {
+		return wait
+	}
+	return wait + jitter
+}
+
 func getOperator(ctx workflow.Context) string {
 	memo := workflow.GetInfo(ctx).Memo
 	if memo == nil || len(memo.Fields) == 0 {
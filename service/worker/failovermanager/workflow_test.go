@@ -187,6 +187,29 @@ func (s *failoverWorkflowTestSuite) TestWorkflow_Success_Batches() {
 	s.Equal(mockFailoverActivityResult2.FailedDomains, result.FailedDomains)
 }
 
+func (s *failoverWorkflowTestSuite) TestWorkflow_Success_BatchWaitJitter() {
+	domains := []string{"d1", "d2", "d3"}
+	s.workflowEnv.OnActivity(getDomainsActivityName, mock.Anything, mock.Anything).Return(domains, nil)
+	s.workflowEnv.OnActivity(failoverActivityName, mock.Anything, mock.Anything).Return(&FailoverActivityResult{}, nil).Times(2)
+
+	params := &FailoverParams{
+		TargetCluster:                    "t",
+		SourceCluster:                    "s",
+		BatchFailoverSize:                2,
+		BatchFailoverWaitTimeInSeconds:   10,
+		BatchFailoverWaitJitterInSeconds: 5,
+		Domains:                          domains,
+	}
+	start := s.workflowEnv.Now()
+	s.workflowEnv.ExecuteWorkflow(FailoverWorkflowTypeName, params)
+
+	s.NoError(s.workflowEnv.GetWorkflowError())
+	elapsed := s.workflowEnv.Now().Sub(start)
+	// a single wait between the two batches
+	s.GreaterOrEqual(elapsed, 10*time.Second)
+	s.LessOrEqual(elapsed, 15*time.Second)
+}
+
 func (s *failoverWorkflowTestSuite) TestWorkflow_Pause() {
 	domains := []string{"d1"}
 	mockFailoverActivityResult := &FailoverActivityResult{
@@ -94,32 +94,49 @@ func (st ScanType) ToExecutionFetcher() ExecutionFetcher {
 
 // ToInvariants returns list of invariants to be checked depending on scan type.
 func (st ScanType) ToInvariants(collections []invariant.Collection, logger *zap.Logger) []InvariantFactory {
+	if !st.IsAScanType() {
+		panic("unknown scan type")
+	}
 	var fns []InvariantFactory
+	for _, collection := range collections {
+		if _, fn := st.collectionInvariant(collection, logger); fn != nil {
+			fns = append(fns, fn)
+		}
+	}
+	return fns
+}
+
+// InvariantName returns the name of the invariant checked for the collection by the scan type, without building it.
+// It returns false when the scan type has no invariant for the collection.
+func (st ScanType) InvariantName(collection invariant.Collection) (invariant.Name, bool) {
+	name, fn := st.collectionInvariant(collection, zap.NewNop())
+	return name, fn != nil
+}
+
+// collectionInvariant returns the name and the factory of the invariant checked for the collection by the scan type,
+// or a nil factory when there is none. It will panic if scan type is unknown.
+func (st ScanType) collectionInvariant(collection invariant.Collection, logger *zap.Logger) (invariant.Name, InvariantFactory) {
 	switch st {
 	case ConcreteExecutionType:
-		for _, collection := range collections {
-			switch collection {
-			case invariant.CollectionDomain:
-				fns = append(fns, invariant.NewInactiveDomainExists)
-			case invariant.CollectionHistory:
-				fns = append(fns, invariant.NewHistoryExists)
-			case invariant.CollectionStale:
-				fns = append(fns, func(pr persistence.Retryer, dc cache.DomainCache) invariant.Invariant {
-					return invariant.NewStaleWorkflow(pr, dc, logger.Named(string(invariant.StaleWorkflow)))
-				})
-			case invariant.CollectionMutableState:
-				fns = append(fns, invariant.NewOpenCurrentExecution)
+		switch collection {
+		case invariant.CollectionDomain:
+			return invariant.InactiveDomainExists, invariant.NewInactiveDomainExists
+		case invariant.CollectionHistory:
+			return invariant.HistoryExists, invariant.NewHistoryExists
+		case invariant.CollectionStale:
+			return invariant.StaleWorkflow, func(pr persistence.Retryer, dc cache.DomainCache) invariant.Invariant {
+				return invariant.NewStaleWorkflow(pr, dc, logger.Named(string(invariant.StaleWorkflow)))
 			}
+		case invariant.CollectionMutableState:
+			return invariant.OpenCurrentExecution, invariant.NewOpenCurrentExecution
 		}
-		return fns
+		return "", nil
 	case CurrentExecutionType:
-		for _, collection := range collections {
-			switch collection {
-			case invariant.CollectionMutableState:
-				fns = append(fns, invariant.NewConcreteExecutionExists)
-			}
+		switch collection {
+		case invariant.CollectionMutableState:
+			return invariant.ConcreteExecutionExists, invariant.NewConcreteExecutionExists
 		}
-		return fns
+		return "", nil
 	default:
 		panic("unknown scan type")
 	}
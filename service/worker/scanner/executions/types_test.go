@@ -0,0 +1,50 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package executions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/reconciliation/invariant"
+)
+
+func TestInvariantName(t *testing.T) {
+	for _, scanType := range ScanTypeValues() {
+		for _, collection := range invariant.CollectionValues() {
+			name, ok := scanType.InvariantName(collection)
+			fns := scanType.ToInvariants([]invariant.Collection{collection}, zap.NewNop())
+			if !ok {
+				assert.Empty(t, fns, "%v has an invariant for %v", scanType, collection)
+				continue
+			}
+			if assert.Len(t, fns, 1, "%v invariants for %v", scanType, collection) {
+				assert.Equal(t, fns[0](nil, cache.NewNoOpDomainCache()).Name(), name, "%v invariant for %v", scanType, collection)
+			}
+		}
+	}
+	assert.Panics(t, func() { ScanType(-1).InvariantName(invariant.CollectionHistory) })
+}
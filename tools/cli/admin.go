@@ -28,6 +28,7 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/reconciliation/invariant"
 	"github.com/uber/cadence/service/worker/scanner/executions"
 )
@@ -47,6 +48,22 @@ func newAdminWorkflowCommands() []*cli.Command {
 					Name:  FlagBranchID,
 					Usage: "BranchID",
 				},
+				&cli.StringFlag{
+					Name:    FlagBranchToken,
+					Aliases: []string{"branch-token"},
+					Usage:   "base64 encoded branch token, as printed by admin workflow describe. Alternative to TreeID/BranchID",
+				},
+				&cli.StringFlag{
+					Name:    FlagWorkflowID,
+					Aliases: []string{"w", "wid"},
+					Usage: "WorkflowID, used with the domain and optional RunID to look up the current branch and shard instead of TreeID/BranchID/ShardID. " +
+						"Together with TreeID/BranchID or a branch token, warns if that branch is not the current one",
+				},
+				&cli.StringFlag{
+					Name:    FlagRunID,
+					Aliases: []string{"r", "rid"},
+					Usage:   "RunID",
+				},
 				&cli.Int64Flag{
 					Name:  FlagMinEventID,
 					Value: 1,
@@ -62,14 +79,144 @@ func newAdminWorkflowCommands() []*cli.Command {
 					Aliases: []string{"of"},
 					Usage:   "output file",
 				},
+				&cli.BoolFlag{
+					Name:    FlagOutputJSONL,
+					Aliases: []string{"output-jsonl"},
+					Usage:   "Write the output file as one JSON event per line, streamed while reading the history, instead of a single JSON array",
+				},
 				// support mysql query
 				&cli.IntFlag{
 					Name:    FlagShardID,
 					Aliases: []string{"sid"},
 					Usage:   "ShardID",
+				},
+				&cli.StringFlag{
+					Name:    FlagActivityID,
+					Aliases: []string{"aid"},
+					Usage:   "Only show events of the activity with the given ActivityID (scheduled, started, completed, failed, timed out, canceled)",
+				},
+				&cli.StringSliceFlag{
+					Name:    FlagEventType,
+					Aliases: []string{"event-type"},
+					Usage:   "Only show events of the given type, e.g. DecisionTaskFailed. Can be repeated",
+				},
+				&cli.BoolFlag{
+					Name:  FlagDecisionChain,
+					Usage: "Only show a table of decision task events with their attempts, useful to spot decision tasks failing or timing out in a loop",
+				},
+				&cli.BoolFlag{
+					Name:    FlagDecodePayloads,
+					Aliases: []string{"decode-payloads"},
+					Usage:   "Show the JSON encoded payloads of the printed events, such as activity inputs and results, as JSON instead of base64. Other payloads stay base64. Does not apply to --output_filename",
+				},
+				&cli.IntFlag{
+					Name:  FlagMaxEvents,
+					Usage: "Optional maximum number of events to show, regardless of the event ID range. 0 means no limit",
+				},
+				&cli.BoolFlag{
+					Name:  FlagColor,
+					Usage: "Tint events by category (workflow, decision, activity, timer, signal, child workflow) when printing to a terminal",
+				},
+				&cli.BoolFlag{
+					Name:    FlagFollow,
+					Aliases: []string{"f"},
+					Usage:   "After printing the history, keep printing new events as they are written until interrupted, like tail -f",
+				},
+				&cli.DurationFlag{
+					Name:  FlagInterval,
+					Value: time.Second,
+					Usage: "Interval between reads of new events with --" + FlagFollow,
 				}),
 			Action: AdminShowWorkflow,
 		},
+		{
+			Name:  "history-diff",
+			Usage: "compare two history branches of a workflow from database, printing the first diverging event and the events of both branches after it",
+			Flags: append(getDBFlags(),
+				&cli.StringFlag{
+					Name:  FlagTreeID,
+					Usage: "TreeID of the branch",
+				},
+				&cli.StringFlag{
+					Name:  FlagBranchID,
+					Usage: "BranchID of the branch",
+				},
+				&cli.StringFlag{
+					Name:    FlagBranchToken,
+					Aliases: []string{"branch-token"},
+					Usage:   "base64 encoded branch token, as printed by admin workflow describe. Alternative to TreeID/BranchID",
+				},
+				&cli.StringFlag{
+					Name:    FlagOtherTreeID,
+					Aliases: []string{"other-tree-id"},
+					Usage:   "TreeID of the other branch, defaults to the TreeID of the branch",
+				},
+				&cli.StringFlag{
+					Name:    FlagOtherBranchID,
+					Aliases: []string{"other-branch-id"},
+					Usage:   "BranchID of the other branch",
+				},
+				&cli.StringFlag{
+					Name:    FlagOtherBranchToken,
+					Aliases: []string{"other-branch-token"},
+					Usage:   "base64 encoded branch token of the other branch. Alternative to the other TreeID/BranchID",
+				},
+				&cli.IntFlag{
+					Name:     FlagShardID,
+					Aliases:  []string{"sid"},
+					Usage:    "ShardID of the workflow",
+					Required: true,
+				},
+				getFormatFlag(),
+				timeoutFlag,
+			),
+			Action: AdminHistoryDiff,
+		},
+		{
+			Name:  "count-history-events",
+			Usage: "Count the events and blob bytes of a workflow history from database without printing the events",
+			Flags: append(getDBFlags(),
+				&cli.StringFlag{
+					Name:  FlagTreeID,
+					Usage: "TreeID",
+				},
+				&cli.StringFlag{
+					Name:  FlagBranchID,
+					Usage: "BranchID",
+				},
+				&cli.StringFlag{
+					Name:    FlagBranchToken,
+					Aliases: []string{"branch-token"},
+					Usage:   "base64 encoded branch token, as printed by admin workflow describe. Alternative to TreeID/BranchID",
+				},
+				&cli.IntFlag{
+					Name:    FlagShardID,
+					Aliases: []string{"sid"},
+					Usage:   "ShardID",
+				},
+				&cli.StringFlag{
+					Name:    FlagWorkflowID,
+					Aliases: []string{"w", "wid"},
+					Usage: "WorkflowID, used with the domain and optional RunID to look up the current branch and shard instead of TreeID/BranchID/ShardID. " +
+						"Together with TreeID/BranchID or a branch token, warns if that branch is not the current one",
+				},
+				&cli.StringFlag{
+					Name:    FlagRunID,
+					Aliases: []string{"r", "rid"},
+					Usage:   "RunID",
+				},
+				&cli.Int64Flag{
+					Name:  FlagMinEventID,
+					Value: 1,
+					Usage: "MinEventID",
+				},
+				&cli.Int64Flag{
+					Name:  FlagMaxEventID,
+					Value: common.EndEventID,
+					Usage: "MaxEventID (exclusive)",
+				}),
+			Action: AdminCountHistoryEvents,
+		},
 		{
 			Name:    "describe",
 			Aliases: []string{"desc"},
@@ -85,9 +232,164 @@ func newAdminWorkflowCommands() []*cli.Command {
 					Aliases: []string{"r", "rid"},
 					Usage:   "RunID",
 				},
+				&cli.BoolFlag{
+					Name:  FlagDecodeSearchAttributes,
+					Usage: "Also print the search attributes stored in mutable state decoded to their values",
+				},
+				&cli.BoolFlag{
+					Name:    FlagShowTimers,
+					Aliases: []string{"show-timers"},
+					Usage:   "Also list the pending user timers by fire time and whether they are overdue",
+				},
+				&cli.BoolFlag{
+					Name:  FlagRaw,
+					Usage: "Only print the response, with the mutable state as stored in the database, without decoding it. Useful when the stored mutable state is corrupt. Cannot be used with --decode_search_attributes or --show_timers",
+				},
+				&cli.StringFlag{
+					Name:    FlagJSONPath,
+					Aliases: []string{"json-path"},
+					Usage:   "Only print the field of the mutable state at this dotted path, e.g. ExecutionInfo.CloseStatus or VersionHistories.Histories[0].BranchToken",
+				},
 			},
 			Action: AdminDescribeWorkflow,
 		},
+		{
+			Name:  "list-branches",
+			Usage: "List the history branches of the version histories of a workflow execution, marking the current one",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    FlagWorkflowID,
+					Aliases: []string{"w", "wid"},
+					Usage:   "WorkflowID",
+				},
+				&cli.StringFlag{
+					Name:    FlagRunID,
+					Aliases: []string{"r", "rid"},
+					Usage:   "RunID",
+				},
+				getFormatFlag(),
+			},
+			Action: AdminListWorkflowBranches,
+		},
+		{
+			Name:  "dump",
+			Usage: "Write the mutable state, the decoded history branches and the full history of a workflow execution to one file to attach to support cases",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    FlagWorkflowID,
+					Aliases: []string{"w", "wid"},
+					Usage:   "WorkflowID",
+				},
+				&cli.StringFlag{
+					Name:    FlagRunID,
+					Aliases: []string{"r", "rid"},
+					Usage:   "RunID",
+				},
+				&cli.StringFlag{
+					Name:    FlagOutputFilename,
+					Aliases: []string{"of", "output-file"},
+					Usage:   "File to write the dump to, as a zip archive with one JSON file each for the workflow, its mutable state and its history when the name ends with .zip. Written to stdout when unset",
+				},
+				timeoutFlag,
+			},
+			Action: AdminDumpWorkflow,
+		},
+		{
+			Name:  "reset-batch",
+			Usage: "Reset the workflows listed in a file of executions, such as the output of `admin db unsupported-workflow --output_jsonl`",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    FlagInputFile,
+					Aliases: []string{"if"},
+					Usage:   "Input file of executions to reset in JSON format {\"DomainID\":\"x\",\"WorkflowID\":\"x\",\"RunID\":\"x\"} separated by a newline. Reads from stdin when unset or \"-\"",
+				},
+				&cli.BoolFlag{
+					Name:    FlagInputGzip,
+					Aliases: []string{"input-gzip"},
+					Usage:   "Decompress the input with gzip. Implied when the input file name ends with .gz",
+				},
+				&cli.StringFlag{
+					Name:  FlagResetType,
+					Value: resetTypeLastDecisionCompleted,
+					Usage: "Where to reset the workflows, one of the reset types of `workflow reset` which need no other flag",
+				},
+				&cli.StringFlag{
+					Name:     FlagReason,
+					Usage:    "Reason for the reset, required for tracking purpose",
+					Required: true,
+				},
+				&cli.IntFlag{
+					Name:  FlagConcurrency,
+					Value: 10,
+					Usage: "Number of workflows reset concurrently",
+				},
+				&cli.IntFlag{
+					Name:  FlagRPS,
+					Value: 10,
+					Usage: "Maximum number of workflows reset per second",
+				},
+				&cli.BoolFlag{
+					Name:    FlagDryRun,
+					Aliases: []string{"dry-run"},
+					Usage:   "Find the reset point of each workflow without resetting it",
+				},
+				&cli.BoolFlag{
+					Name:  FlagSkipSignalReapply,
+					Usage: "Do not reapply the signals received after the reset point",
+				},
+			},
+			Action: AdminResetWorkflowsInBatch,
+		},
+		{
+			Name:  "signal-batch",
+			Usage: "Signal the workflows listed in a file",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    FlagInputFile,
+					Aliases: []string{"if"},
+					Usage:   "Input file of workflows to signal in JSON format {\"domain\":\"x\",\"workflowID\":\"x\",\"runID\":\"x\",\"signalName\":\"x\"} separated by a newline. Reads from stdin when unset or \"-\"",
+				},
+				&cli.BoolFlag{
+					Name:    FlagInputGzip,
+					Aliases: []string{"input-gzip"},
+					Usage:   "Decompress the input with gzip. Implied when the input file name ends with .gz",
+				},
+				&cli.StringFlag{
+					Name:    FlagName,
+					Aliases: []string{"n"},
+					Usage:   "Signal name of the workflows without a signalName in the input file",
+				},
+				&cli.StringFlag{
+					Name:    FlagInput,
+					Aliases: []string{"i"},
+					Usage:   "Input of the signal sent to every workflow, in JSON format",
+				},
+				&cli.IntFlag{
+					Name:  FlagConcurrency,
+					Value: 10,
+					Usage: "Number of workflows signaled concurrently",
+				},
+				&cli.IntFlag{
+					Name:  FlagRPS,
+					Value: 10,
+					Usage: "Maximum number of workflows signaled per second",
+				},
+			},
+			Action: AdminSignalWorkflowsInBatch,
+		},
+		{
+			Name:  "decode-branch-token",
+			Usage: "Decode a base64 encoded history branch token into its treeID, branchID and ancestor branch ranges",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     FlagBranchToken,
+					Aliases:  []string{"branch-token"},
+					Usage:    "base64 encoded branch token, as printed by admin workflow describe",
+					Required: true,
+				},
+			},
+			Action: AdminDecodeBranchToken,
+		},
 		{
 			Name:    "refresh-tasks",
 			Aliases: []string{"rt"},
@@ -103,6 +405,7 @@ func newAdminWorkflowCommands() []*cli.Command {
 					Aliases: []string{"r", "rid"},
 					Usage:   "RunID",
 				},
+				timeoutFlag,
 			},
 			Action: AdminRefreshWorkflowTasks,
 		},
@@ -129,7 +432,51 @@ func newAdminWorkflowCommands() []*cli.Command {
 				&cli.BoolFlag{
 					Name:  FlagRemote,
 					Usage: "Executes deletion on server side",
-				}),
+				},
+				&cli.BoolFlag{
+					Name:    FlagForceCurrent,
+					Aliases: []string{"force-current"},
+					Usage:   "Delete the current execution row even if it points to a different run than the one being deleted",
+				},
+				&cli.BoolFlag{
+					Name:    FlagHistoryOnly,
+					Aliases: []string{"history-only"},
+					Usage: "Only delete the history events of the workflow, keeping its mutable state and current rows. " +
+						"With TreeID/BranchID or a branch token and ShardID, deletes that branch without reading the mutable state",
+				},
+				&cli.BoolFlag{
+					Name:    FlagMutableStateOnly,
+					Aliases: []string{"mutable-state-only"},
+					Usage:   "Only delete the mutable state and current rows of the workflow, keeping its history events",
+				},
+				&cli.BoolFlag{
+					Name:    FlagCurrentRowOnly,
+					Aliases: []string{"current-row-only"},
+					Usage:   "Only delete the current execution row of the workflow if it points to RunID, without reading the mutable state",
+				},
+				&cli.StringFlag{
+					Name:  FlagTreeID,
+					Usage: "TreeID of the history branch to delete with --history_only",
+				},
+				&cli.StringFlag{
+					Name:  FlagBranchID,
+					Usage: "BranchID of the history branch to delete with --history_only",
+				},
+				&cli.StringFlag{
+					Name:    FlagBranchToken,
+					Aliases: []string{"branch-token"},
+					Usage:   "base64 encoded branch token of the history branch to delete with --history_only. Alternative to TreeID/BranchID",
+				},
+				&cli.IntFlag{
+					Name:    FlagShardID,
+					Aliases: []string{"sid"},
+					Usage:   "ShardID of the workflow, required with TreeID/BranchID or a branch token",
+				},
+				&cli.IntFlag{
+					Name:  FlagNumberOfShards,
+					Usage: "NumberOfShards for the cadence cluster (see config for numHistoryShards), to find the shard of the workflow with --current_row_only when ShardID is not set",
+				},
+				timeoutFlag),
 			Action: AdminDeleteWorkflow,
 		},
 		{
@@ -170,9 +517,52 @@ func newAdminShardManagementCommands() []*cli.Command {
 					Name:  FlagShardID,
 					Usage: "The Id of the shard to describe",
 				},
+				&cli.IntFlag{
+					Name:  FlagLowerShardBound,
+					Usage: "First shard of a range of shards to describe, instead of --shard_id",
+				},
+				&cli.IntFlag{
+					Name:  FlagUpperShardBound,
+					Usage: "Last shard (inclusive) of a range of shards to describe, instead of --shard_id",
+				},
+				&cli.BoolFlag{
+					Name:  FlagDecode,
+					Usage: "Also print a table of the per-cluster transfer, timer, replication and replication DLQ ack levels",
+				},
+				&cli.BoolFlag{
+					Name:  FlagHistory,
+					Usage: "Describe the shard twice, --interval apart, and print the owner and the per-second progress of the transfer and timer ack levels",
+				},
+				&cli.DurationFlag{
+					Name:  FlagInterval,
+					Value: 5 * time.Minute,
+					Usage: "Time between the two samples of --history, at least the default history.shardUpdateMinInterval of 5m as the ack levels are persisted at most that often",
+				},
 			),
 			Action: AdminDescribeShard,
 		},
+		{
+			Name:    "list-info",
+			Aliases: []string{"li"},
+			Usage:   "List the persisted shard info (rangeID, stolenSinceRenew, updatedAt, owner) of every shard",
+			Flags: append(
+				getDBFlags(),
+				&cli.IntFlag{
+					Name:     FlagNumberOfShards,
+					Aliases:  []string{"number-of-shards"},
+					Usage:    "NumberOfShards for the cadence cluster (see config for numHistoryShards)",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:    FlagFilterOwner,
+					Aliases: []string{"filter-owner"},
+					Usage:   "Only list shards owned by the given host",
+				},
+				getFormatFlag(),
+				timeoutFlag,
+			),
+			Action: AdminListShards,
+		},
 		{
 			Name:    "list",
 			Aliases: []string{"l"},
@@ -188,6 +578,12 @@ func newAdminShardManagementCommands() []*cli.Command {
 					Value: 0,
 					Usage: "Option to show results offset from pagesize * page_id",
 				},
+				&cli.IntFlag{
+					Name:    FlagImbalanceThreshold,
+					Aliases: []string{"imbalance-threshold"},
+					Usage: "Warn about the hosts owning more or fewer shards than the mean shards per host by more than this percentage. " +
+						"Only the returned shards are counted, raise --" + FlagPageSize + " to count all of them",
+				},
 				getFormatFlag(),
 			},
 			Action: AdminDescribeShardDistribution,
@@ -208,9 +604,68 @@ func newAdminShardManagementCommands() []*cli.Command {
 					Aliases: []string{"rid"},
 					Usage:   "new shard rangeID",
 				},
+				&cli.Int64Flag{
+					Name:  FlagBump,
+					Usage: "Increase the current rangeID of each shard by this amount, instead of --range_id",
+				},
+				&cli.IntFlag{
+					Name:  FlagLowerShardBound,
+					Usage: "First shard of a range of shards to reset, instead of --shard_id. Requires --yes",
+				},
+				&cli.IntFlag{
+					Name:  FlagUpperShardBound,
+					Usage: "Last shard (inclusive) of a range of shards to reset, instead of --shard_id. Requires --yes",
+				},
+				&cli.IntFlag{
+					Name:  FlagMaxShards,
+					Value: 100,
+					Usage: "Maximum number of shards that a range may contain",
+				},
+				&cli.BoolFlag{
+					Name:  FlagYes,
+					Usage: "Confirm resetting the rangeID of a range of shards",
+				},
 			),
 			Action: AdminSetShardRangeID,
 		},
+		{
+			Name:  "reset-ack-level",
+			Usage: "Set the transfer or timer ack level of a cluster in a shard, to reprocess or skip its tasks. The shard rangeID is bumped so that its owner reloads the shard",
+			Flags: append(
+				getDBFlags(),
+				&cli.IntFlag{
+					Name:     FlagShardID,
+					Aliases:  []string{"sid"},
+					Usage:    "ID of the shard",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     FlagCluster,
+					Usage:    "Cluster whose ack level to set",
+					Required: true,
+				},
+				&cli.Int64Flag{
+					Name:    FlagTransferAckLevel,
+					Aliases: []string{"transfer-ack-level"},
+					Usage:   "New transfer ack level (task ID)",
+				},
+				&cli.StringFlag{
+					Name:    FlagTimerAckLevel,
+					Aliases: []string{"timer-ack-level"},
+					Usage:   "New timer ack level, in the formats of --" + FlagEarliestTime + " of `workflow list`",
+				},
+				&cli.BoolFlag{
+					Name:    FlagCurrentCluster,
+					Aliases: []string{"current-cluster"},
+					Usage:   "--cluster is the cluster of the shard, also set the ack level used when the shard has none for a cluster",
+				},
+				&cli.BoolFlag{
+					Name:  FlagYes,
+					Usage: "Set the ack level without asking for confirmation",
+				},
+			),
+			Action: AdminResetShardAckLevel,
+		},
 		{
 			Name:    "closeShard",
 			Aliases: []string{"clsh"},
@@ -223,11 +678,34 @@ func newAdminShardManagementCommands() []*cli.Command {
 			},
 			Action: AdminCloseShard,
 		},
+		{
+			Name:  "transfer",
+			Usage: "Move a shard to a history host: set the persisted owner of the shard and close it, then wait until the target host owns it. The owner is chosen by the membership ring, drain the current owner first to move the shard",
+			Flags: append(
+				getDBFlags(),
+				&cli.IntFlag{
+					Name:  FlagShardID,
+					Usage: "ShardID for the cadence cluster to manage",
+				},
+				&cli.StringFlag{
+					Name:    FlagTargetHost,
+					Aliases: []string{"target-host"},
+					Usage:   "Address of the history host which should own the shard, as shown by admin history host describe",
+				},
+				&cli.DurationFlag{
+					Name:    FlagWaitTimeout,
+					Aliases: []string{"wait-timeout"},
+					Value:   time.Minute,
+					Usage:   "How long to wait for the target host to own the shard",
+				},
+			),
+			Action: AdminTransferShard,
+		},
 		{
 			Name:    "removeTask",
 			Aliases: []string{"rmtk"},
-			Usage:   "remove a task based on shardID, task type, taskID, and task visibility timestamp",
-			Flags: []cli.Flag{
+			Usage:   "remove a task based on shardID, task type, taskID, and task visibility timestamp. With --" + FlagShowTask + ", the task is read from the database and shown before it is removed",
+			Flags: append([]cli.Flag{
 				&cli.IntFlag{
 					Name:  FlagShardID,
 					Usage: "shardID",
@@ -246,9 +724,22 @@ func newAdminShardManagementCommands() []*cli.Command {
 				},
 				&cli.StringFlag{
 					Name:  FlagCluster,
-					Usage: "target cluster of the task (required for removing cross-cluster task)",
+					Usage: "target cluster of the task (required for removing cross-cluster task). For replication tasks, the source cluster whose DLQ is checked before removal",
 				},
-			},
+				&cli.BoolFlag{
+					Name:  FlagDLQAware,
+					Usage: "for replication tasks, refuse to remove the task if it is also present in the DLQ of --" + FlagCluster + ", so it can be merged or purged from the DLQ first",
+				},
+				&cli.BoolFlag{
+					Name:  FlagYes,
+					Usage: "remove the task without asking for confirmation",
+				},
+				&cli.BoolFlag{
+					Name:    FlagShowTask,
+					Aliases: []string{"show-task"},
+					Usage:   "read the task from the database with the DB flags and show it before removing it",
+				},
+			}, getDBFlags()...),
 			Action: AdminRemoveTask,
 		},
 		{
@@ -343,8 +834,9 @@ func newAdminHistoryHostCommands() []*cli.Command {
 				&cli.BoolFlag{
 					Name:    FlagPrintFullyDetail,
 					Aliases: []string{"pf"},
-					Usage:   "Print fully detail",
+					Usage:   "Print fully detail, including the owned shard IDs",
 				},
+				getFormatFlag(),
 			},
 			Action: AdminDescribeHistoryHost,
 		},
@@ -362,9 +854,30 @@ func newAdminHistoryHostCommands() []*cli.Command {
 					Name:  FlagNumberOfShards,
 					Usage: "NumberOfShards for the cadence cluster(see config for numHistoryShards)",
 				},
+				&cli.BoolFlag{
+					Name:    FlagWithHost,
+					Aliases: []string{"with-host"},
+					Usage:   "Also print the address of the history host owning the shard. Requires a running server",
+				},
 			},
 			Action: AdminGetShardID,
 		},
+		{
+			Name:    "list",
+			Aliases: []string{"l"},
+			Usage:   "List all history hosts with the number of shards they own, flagging the hosts which are over or under the mean",
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:    FlagImbalanceThreshold,
+					Aliases: []string{"imbalance-threshold"},
+					Value:   historyHostImbalanceThreshold,
+					Usage:   "Flag the hosts owning more or fewer shards than the mean shards per host by more than this percentage",
+				},
+				getFormatFlag(),
+				timeoutFlag,
+			},
+			Action: AdminListHistoryHosts,
+		},
 	}
 }
 
@@ -425,6 +938,26 @@ func newAdminDomainCommands() []*cli.Command {
 				})
 			},
 		},
+		{
+			Name:  "describe-replication",
+			Usage: "Describe the replication configuration of a domain: its active cluster, clusters, ongoing graceful failover, and the replication DLQ messages of each cluster (shared by all domains)",
+			Flags: []cli.Flag{
+				getFormatFlag(),
+			},
+			Action: AdminDescribeDomainReplication,
+		},
+		{
+			Name:  "list-global",
+			Usage: "List the global domains with their active cluster and clusters. Use --format '{{.Name}}' to write a --" + FlagFailoverDomainsFile + " for `admin cluster failover start`",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  FlagCluster,
+					Usage: "Only list the global domains active in this cluster",
+				},
+				getFormatFlag(),
+			},
+			Action: AdminListGlobalDomains,
+		},
 		{
 			Name:    "getdomainidorname",
 			Aliases: []string{"getdn"},
@@ -437,6 +970,20 @@ func newAdminDomainCommands() []*cli.Command {
 				&cli.StringFlag{
 					Name:  FlagDomainID,
 					Usage: "Domain ID(uuid)",
+				},
+				&cli.StringFlag{
+					Name:  FlagFormat,
+					Usage: "Output format [text|json|yaml]. json and yaml print {id, name}",
+				},
+				&cli.StringFlag{
+					Name:    FlagInputFile,
+					Aliases: []string{"if", "input-file"},
+					Usage:   "File with one domain name or domainID (uuid) per line to resolve in bulk, instead of --domain or --domain_id. Prints a TSV (or --format json) mapping",
+				},
+				&cli.IntFlag{
+					Name:  FlagConcurrency,
+					Value: 10,
+					Usage: "Number of concurrent GetDomain calls when --input_file is set",
 				}),
 			Action: AdminGetDomainIDOrName,
 		},
@@ -693,7 +1240,21 @@ func newAdminTaskListCommands() []*cli.Command {
 					Name:    FlagTaskListType,
 					Aliases: []string{"tlt"},
 					Value:   "decision",
-					Usage:   "Optional TaskList type [decision|activity]",
+					Usage:   "Optional TaskList type [decision|activity|all]",
+				},
+				&cli.StringFlag{
+					Name:  FlagPrometheusFile,
+					Usage: "Optional file to write task list backlog, RPS, ack level and poller count to in Prometheus text format (e.g. for the node_exporter textfile collector)",
+				},
+				&cli.BoolFlag{
+					Name:    FlagAllPartitions,
+					Aliases: []string{"all-partitions"},
+					Usage:   "Describe every partition of a scalable task list and show the aggregated status with a per-partition breakdown",
+				},
+				&cli.BoolFlag{
+					Name:    FlagFailOnEmpty,
+					Aliases: []string{"fail-on-empty"},
+					Usage:   fmt.Sprintf("Exit with code %d instead of 0 when the task list has no status or no pollers", exitCodeEmpty),
 				},
 			},
 			Action: AdminDescribeTaskList,
@@ -708,9 +1269,67 @@ func newAdminTaskListCommands() []*cli.Command {
 					Aliases: []string{"do"},
 					Usage:   "Required Domain name",
 				},
+				&cli.BoolFlag{
+					Name:    FlagWithStatus,
+					Aliases: []string{"with-status"},
+					Usage:   "Describe each task list to show its backlog (issues one DescribeTaskList call per task list)",
+				},
+				&cli.IntFlag{
+					Name:  FlagConcurrency,
+					Value: 10,
+					Usage: "Number of concurrent DescribeTaskList calls when --with_status is set",
+				},
+				&cli.StringFlag{
+					Name:    FlagSortBy,
+					Aliases: []string{"sort-by"},
+					Usage:   "Optional sort order, descending [backlog|pollers]. Sorting by backlog requires --with_status",
+				},
+				timeoutFlag,
 			},
 			Action: AdminListTaskList,
 		},
+		{
+			Name:    "partitions",
+			Aliases: []string{"p"},
+			Usage:   "Describe which matching host owns each partition of a tasklist",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    FlagTaskList,
+					Aliases: []string{"tl"},
+					Usage:   "TaskList Name",
+				},
+			},
+			Action: AdminDescribeTaskListPartitions,
+		},
+		{
+			Name:  "drain",
+			Usage: "Wait for the backlog of a tasklist to drain, reporting the progress. Stop the workflows adding tasks to it first, the server keeps accepting new tasks",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    FlagTaskList,
+					Aliases: []string{"tl"},
+					Usage:   "TaskList Name",
+				},
+				&cli.StringFlag{
+					Name:    FlagTaskListType,
+					Aliases: []string{"tlt"},
+					Value:   "all",
+					Usage:   "Optional TaskList type [decision|activity|all]",
+				},
+				&cli.DurationFlag{
+					Name:  FlagInterval,
+					Value: 10 * time.Second,
+					Usage: "Time between two checks of the backlog",
+				},
+				&cli.DurationFlag{
+					Name:    FlagWaitTimeout,
+					Aliases: []string{"wait-timeout"},
+					Usage:   "Fail when the backlog has not drained within this duration, e.g. 30m. Waits until interrupted when unset",
+				},
+				timeoutFlag,
+			},
+			Action: AdminDrainTaskList,
+		},
 		{
 			Name:    "update-partition",
 			Aliases: []string{"up"},
@@ -772,6 +1391,12 @@ func newAdminClusterCommands() []*cli.Command {
 			Usage:   "Describe cluster information",
 			Action:  AdminDescribeCluster,
 		},
+		{
+			Name:   "metadata",
+			Usage:  "Describe the current cluster, the clusters of the replication configuration of the global domains and the supported client versions",
+			Flags:  []cli.Flag{getFormatFlag()},
+			Action: AdminDescribeClusterMetadata,
+		},
 		{
 			Name:        "failover",
 			Aliases:     []string{"fo"},
@@ -888,12 +1513,29 @@ func newAdminQueueCommands() []*cli.Command {
 			Flags:  getQueueCommandFlags(),
 			Action: AdminResetQueue,
 		},
+		{
+			Name:  "merge",
+			Usage: "merge fragmented processing queue states for transfer or timer queue processor into a single state",
+			Flags: append(getQueueCommandFlags(),
+				&cli.BoolFlag{
+					Name:  FlagYes,
+					Usage: "Merge without asking for confirmation",
+				},
+			),
+			Action: AdminMergeQueue,
+		},
 		{
 			Name:    "describe",
 			Aliases: []string{"desc"},
 			Usage:   "describe processing queue states for transfer or timer queue processor",
-			Flags:   getQueueCommandFlags(),
-			Action:  AdminDescribeQueue,
+			Flags: append(getQueueCommandFlags(),
+				&cli.StringFlag{
+					Name:    FlagDomainID,
+					Aliases: []string{"domain-id"},
+					Usage:   "Only show the queue states which process the tasks of this domain ID, including the catch-all states of other domains",
+				},
+			),
+			Action: AdminDescribeQueue,
 		},
 	}
 }
@@ -953,6 +1595,25 @@ func newDBCommands() []*cli.Command {
 		Required: false,
 	}
 
+	pageSizeFlag := &cli.IntFlag{
+		Name:    FlagPageSize,
+		Aliases: []string{"page-size"},
+		Usage:   "Number of executions listed from the database per page, trading throughput for memory and database pressure",
+		Value:   defaultScanPageSize,
+	}
+
+	inputGzipFlag := &cli.BoolFlag{
+		Name:    FlagInputGzip,
+		Aliases: []string{"input-gzip"},
+		Usage:   "Decompress the input with gzip. Implied when the input file name ends with .gz",
+	}
+
+	outputGzipFlag := &cli.BoolFlag{
+		Name:    FlagOutputGzip,
+		Aliases: []string{"output-gzip"},
+		Usage:   "Compress the output with gzip. Implied when the output file name ends with .gz",
+	}
+
 	return []*cli.Command{
 		{
 			Name:  "scan",
@@ -965,16 +1626,83 @@ func newDBCommands() []*cli.Command {
 				},
 				scanFlag,
 				collectionsFlag,
-				&cli.StringFlag{
+				&cli.StringSliceFlag{
 					Name:    FlagInputFile,
 					Aliases: []string{"if"},
-					Usage:   "Input file of executions to scan in JSON format {\"DomainID\":\"x\",\"WorkflowID\":\"x\",\"RunID\":\"x\"} separated by a newline",
+					Usage: "Input file of executions to scan in JSON format {\"DomainID\":\"x\",\"WorkflowID\":\"x\",\"RunID\":\"x\"} separated by a newline. Reads from stdin when unset or \"-\". " +
+						"Can be repeated to scan the executions of several files in one run",
+				},
+				&cli.StringSliceFlag{
+					Name: FlagSkipInvariant,
+					Usage: "Name of an invariant to skip, to scan faster when only some corruption classes are of interest. " +
+						"Invariants only read the current branch: history_exists reads its first event, stale_workflow reads the first event for some closed workflows, " +
+						"open_current_execution, concrete_execution_exists and stale_workflow read the current or concrete execution, inactive_domain_exists reads nothing",
 				},
+				&cli.BoolFlag{
+					Name:    FlagOnlyCorrupted,
+					Aliases: []string{"only-corrupted"},
+					Usage:   "Only output executions which are not healthy",
+				},
+				&cli.StringSliceFlag{
+					Name: FlagPlugin,
+					Usage: "Go plugin (.so) with a custom invariant to run in addition to the built-in ones. It must export NewInvariant with the signature func(persistence.Retryer, cache.DomainCache) invariant.Invariant, " +
+						"and may export InvariantNames, a []string with the name of each invariant, to skip them with --skip_invariant",
+				},
+				&cli.StringFlag{
+					Name:    FlagStartTime,
+					Aliases: []string{"start-time"},
+					Usage: "Only scan executions started at or after this time, in the formats of --" + FlagEarliestTime + " of `workflow list`. " +
+						"Costs an extra read of the mutable state of every execution",
+				},
+				&cli.StringFlag{
+					Name:    FlagEndTime,
+					Aliases: []string{"end-time"},
+					Usage: "Only scan executions started at or before this time, in the formats of --" + FlagLatestTime + " of `workflow list`. " +
+						"Costs an extra read of the mutable state of every execution",
+				},
+				inputGzipFlag,
+				outputGzipFlag,
 				verboseFlag,
 			),
 
 			Action: AdminDBScan,
 		},
+		{
+			Name:  "validate-shard",
+			Usage: "run the scan invariants on every execution of a shard, without an input file of executions",
+			Flags: append(getDBFlags(),
+				&cli.IntFlag{
+					Name:     FlagShardID,
+					Aliases:  []string{"sid"},
+					Usage:    "ID of the shard to validate",
+					Required: true,
+				},
+				scanFlag,
+				collectionsFlag,
+				&cli.StringSliceFlag{
+					Name:  FlagSkipInvariant,
+					Usage: "Name of an invariant to skip, as in `admin db scan`",
+				},
+				&cli.BoolFlag{
+					Name:    FlagOnlyCorrupted,
+					Aliases: []string{"only-corrupted"},
+					Usage:   "Only output executions which are not healthy",
+				},
+				&cli.StringSliceFlag{
+					Name:  FlagPlugin,
+					Usage: "Go plugin (.so) with a custom invariant to run in addition to the built-in ones, as in `admin db scan`",
+				},
+				&cli.StringFlag{
+					Name:    FlagOutputFilename,
+					Aliases: []string{"of"},
+					Usage:   "Output file to write to, if not provided output is written to stdout",
+				},
+				pageSizeFlag,
+				outputGzipFlag,
+				verboseFlag,
+			),
+			Action: AdminDBValidateShard,
+		},
 		{
 			Name:  "unsupported-workflow",
 			Usage: "use this command when upgrade the Cadence server from version less than 0.16.0. This scan database and detect unsupported workflow type.",
@@ -989,6 +1717,11 @@ func newDBCommands() []*cli.Command {
 					Aliases: []string{"of"},
 					Usage:   "Output file to write to, if not provided output is written to stdout",
 				},
+				&cli.BoolFlag{
+					Name:    FlagOutputJSONL,
+					Aliases: []string{"output-jsonl"},
+					Usage:   "Write the unsupported executions as JSON lines for `admin workflow reset-batch`, instead of `workflow reset` commands",
+				},
 				&cli.IntFlag{
 					Name:     FlagLowerShardBound,
 					Usage:    "FlagLowerShardBound for the start shard to scan. (Default: 0)",
@@ -1001,6 +1734,13 @@ func newDBCommands() []*cli.Command {
 					Value:    16383,
 					Required: true,
 				},
+				&cli.IntFlag{
+					Name:    FlagResumeFromShard,
+					Aliases: []string{"resume-from-shard"},
+					Usage:   "Skip the shards below this shard ID, to resume an interrupted scan from the shard it reported",
+				},
+				pageSizeFlag,
+				outputGzipFlag,
 			),
 
 			Action: AdminDBScanUnsupportedWorkflow,
@@ -1020,6 +1760,32 @@ func newDBCommands() []*cli.Command {
 			),
 			Action: AdminDBClean,
 		},
+		{
+			Name:  "fix",
+			Usage: "apply invariant fixes to the corrupted executions found by scan",
+			Flags: append(getDBFlags(),
+				scanFlag,
+				collectionsFlag,
+				&cli.StringFlag{
+					Name:    FlagInputFile,
+					Aliases: []string{"if"},
+					Usage:   "Input file of scan results in JSON format, as written by the `scan` command. Reads from stdin when unset or \"-\"",
+				},
+				&cli.StringSliceFlag{
+					Name:  FlagInvariant,
+					Usage: "Name of an invariant to fix, defaults to all the invariants of the scan type and collections",
+				},
+				&cli.BoolFlag{
+					Name:    FlagDryRun,
+					Aliases: []string{"dry-run"},
+					Usage:   "Report the fixes which would be applied without applying them",
+				},
+				inputGzipFlag,
+				outputGzipFlag,
+				verboseFlag,
+			),
+			Action: AdminDBFix,
+		},
 		{
 			Name:  "decode_thrift",
 			Usage: "decode thrift object, print into JSON if the data is matching with any supported struct",
@@ -1093,6 +1859,11 @@ func newAdminFailoverCommands() []*cli.Command {
 					Usage:   "Optional Failover wait time after each batch in seconds",
 					Value:   defaultBatchFailoverWaitTimeInSeconds,
 				},
+				&cli.IntFlag{
+					Name:    FlagFailoverWaitJitter,
+					Aliases: []string{"batch-wait-jitter"},
+					Usage:   "Optional random jitter in seconds added to the wait time after each batch, to spread the replication load of large failovers",
+				},
 				&cli.IntFlag{
 					Name:    FlagFailoverBatchSize,
 					Aliases: []string{"fbs"},
@@ -1104,6 +1875,18 @@ func newAdminFailoverCommands() []*cli.Command {
 					Usage: "Optional domains to failover, eg d1,d2..,dn. " +
 						"Only provided domains in source cluster will be failover.",
 				},
+				&cli.StringFlag{
+					Name:    FlagFailoverDomainsFile,
+					Aliases: []string{"domains-file"},
+					Usage: "Optional file of domains to failover, one per line. Blank lines and lines starting with # are ignored. " +
+						"Merged with --" + FlagFailoverDomains + ".",
+				},
+				&cli.BoolFlag{
+					Name:    FlagFailoverAllDomains,
+					Aliases: []string{"all-domains"},
+					Usage: "Failover every global domain active in the source cluster with cadence managed failover, " +
+						"listed before starting. Cannot be used with --" + FlagFailoverDomains + " or --" + FlagFailoverDomainsFile + ".",
+				},
 				&cli.IntFlag{
 					Name:    FlagFailoverDrillWaitTime,
 					Aliases: []string{"fdws"},
@@ -1116,6 +1899,29 @@ func newAdminFailoverCommands() []*cli.Command {
 					Usage: "Optional cron schedule on failover drill. Please specify failover drill wait time " +
 						"if this field is specific",
 				},
+				&cli.StringFlag{
+					Name:    FlagOutputFilename,
+					Aliases: []string{"of", "output-file"},
+					Usage:   "Optional file to write the started workflow's ID, run ID, clusters and domains to, in JSON format",
+				},
+				&cli.StringFlag{
+					Name:  FlagOperator,
+					Usage: "Optional operator recorded in the failover workflow memo. Defaults to the current OS user and hostname",
+				},
+				&cli.StringFlag{
+					Name:  FlagTag,
+					Usage: "Optional tag recorded in the failover workflow memo, e.g. an incident ticket, shown by failover query and list",
+				},
+				&cli.StringFlag{
+					Name:    FlagRequestID,
+					Aliases: []string{"request-id"},
+					Usage:   "Optional request ID of the start, so that retrying a start with the same ID does not start another failover. Defaults to a random UUID",
+				},
+				&cli.BoolFlag{
+					Name:    FlagSkipClusterCheck,
+					Aliases: []string{"skip-cluster-check"},
+					Usage:   "Skip checking that the target cluster is in the replication configuration of the global domains",
+				},
 			},
 			Action: AdminFailoverStart,
 		},
@@ -1174,9 +1980,26 @@ func newAdminFailoverCommands() []*cli.Command {
 					Aliases: []string{"rid", "r"},
 					Usage:   "Optional Failover workflow runID, default is latest runID",
 				},
+				&cli.BoolFlag{
+					Name:  FlagWatch,
+					Usage: "Poll the workflow until it completes or is aborted, printing the domains that succeeded or failed since the previous poll",
+				},
+				&cli.DurationFlag{
+					Name:  FlagInterval,
+					Value: 10 * time.Second,
+					Usage: "Interval between polls with --" + FlagWatch,
+				},
 			},
 			Action: AdminFailoverQuery,
 		},
+		{
+			Name:  "status",
+			Usage: "show whether a failover or failover drill workflow is in progress",
+			Flags: []cli.Flag{
+				getFormatFlag(),
+			},
+			Action: AdminFailoverStatus,
+		},
 		{
 			Name:    "abort",
 			Aliases: []string{"a"},
@@ -1198,6 +2021,16 @@ func newAdminFailoverCommands() []*cli.Command {
 					Usage: "Optional to abort failover workflow or failover drill workflow." +
 						" The default is normal failover workflow",
 				},
+				&cli.BoolFlag{
+					Name:  FlagWait,
+					Usage: "Wait until the workflow is terminated, since termination is asynchronous",
+				},
+				&cli.DurationFlag{
+					Name:    FlagWaitTimeout,
+					Aliases: []string{"wait-timeout"},
+					Value:   time.Minute,
+					Usage:   "How long to wait for the workflow to be terminated with --" + FlagWait,
+				},
 			},
 			Action: AdminFailoverAbort,
 		},
@@ -1228,12 +2061,25 @@ func newAdminFailoverCommands() []*cli.Command {
 					Usage:   "Optional Failover wait time after each batch in seconds",
 					Value:   defaultBatchFailoverWaitTimeInSeconds,
 				},
+				&cli.IntFlag{
+					Name:    FlagFailoverWaitJitter,
+					Aliases: []string{"batch-wait-jitter"},
+					Usage:   "Optional random jitter in seconds added to the wait time after each batch, to spread the replication load of large failovers",
+				},
 				&cli.IntFlag{
 					Name:    FlagFailoverBatchSize,
 					Aliases: []string{"fbs"},
 					Usage:   "Optional number of domains to failover in one batch",
 					Value:   defaultBatchFailoverSize,
 				},
+				&cli.StringFlag{
+					Name:  FlagOperator,
+					Usage: "Optional operator recorded in the failover workflow memo. Defaults to the current OS user and hostname",
+				},
+				&cli.StringFlag{
+					Name:  FlagTag,
+					Usage: "Optional tag recorded in the failover workflow memo, e.g. an incident ticket, shown by failover query and list",
+				},
 			},
 			Action: AdminFailoverRollback,
 		},
@@ -1264,6 +2110,13 @@ func newAdminFailoverCommands() []*cli.Command {
 					Usage: "Optional to query failover workflow or failover drill workflow." +
 						" The default is normal failover workflow",
 				},
+				&cli.BoolFlag{
+					Name:    FlagPrintMemo,
+					Aliases: []string{"pme"},
+					Value:   true,
+					Usage:   "Print memo (operator and tag) of failover runs",
+				},
+				idsOnlyFlag,
 			},
 			Action: AdminFailoverList,
 		},
@@ -0,0 +1,141 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/tokenbucket"
+	"github.com/uber/cadence/tools/common/commoncli"
+)
+
+// batchRunner runs an operation on each entry of the input of a batch command
+type batchRunner struct {
+	// done is the operation in the summary, e.g. "Signaled"
+	done string
+	// verb is the operation in the errors, e.g. "signal"
+	verb        string
+	concurrency int
+	rps         int
+}
+
+// newBatchRunner validates the --concurrency and --rps flags of a batch command
+func newBatchRunner(c *cli.Context, done, verb string) (*batchRunner, error) {
+	concurrency := c.Int(FlagConcurrency)
+	if concurrency < 1 {
+		return nil, commoncli.Problem(fmt.Sprintf("--%s must be positive", FlagConcurrency), nil)
+	}
+	rps := c.Int(FlagRPS)
+	if rps < 1 {
+		return nil, commoncli.Problem(fmt.Sprintf("--%s must be positive", FlagRPS), nil)
+	}
+	return &batchRunner{done: done, verb: verb, concurrency: concurrency, rps: rps}, nil
+}
+
+// readBatchInput decodes the JSON entries of the input of a batch command, validating each with validate if set
+func readBatchInput[T any](c *cli.Context, validate func(index int, entry *T) error) ([]T, error) {
+	input, err := openScanInput(c)
+	if err != nil {
+		return nil, commoncli.Problem("Input file not found", err)
+	}
+	defer input.Close()
+	var entries []T
+	dec := json.NewDecoder(input)
+	for {
+		var entry T
+		if err := dec.Decode(&entry); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, commoncli.Problem("Error decoding input file", err)
+		}
+		if validate != nil {
+			if err := validate(len(entries), &entry); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// run runs fn on each of the total entries of the batch with --concurrency workers, at most --rps per second,
+// printing the line returned for each entry and a summary. It stops sending entries to the workers when interrupted.
+func (r *batchRunner) run(c *cli.Context, total int, fn func(index int) (string, error)) error {
+	stop := cancelOnInterrupt(c)
+	defer stop()
+
+	ratelimiter := tokenbucket.New(r.rps, clock.NewRealTimeSource())
+	output := getDeps(c).Output()
+	var (
+		mu       sync.Mutex
+		failed   int
+		executed int
+		wg       sync.WaitGroup
+	)
+	work := make(chan int)
+	for i := 0; i < r.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range work {
+				for ok, wait := ratelimiter.TryConsume(1); !ok; ok, wait = ratelimiter.TryConsume(1) {
+					time.Sleep(wait)
+				}
+				line, err := fn(index)
+
+				mu.Lock()
+				executed++
+				if err != nil {
+					failed++
+				}
+				fmt.Fprintln(output, line)
+				mu.Unlock()
+			}
+		}()
+	}
+	for index := 0; index < total; index++ {
+		if c.Context.Err() != nil {
+			break
+		}
+		work <- index
+	}
+	close(work)
+	wg.Wait()
+
+	fmt.Fprintf(output, "%v %v of %v workflows, %v failed.\n", r.done, executed-failed, total, failed)
+	if executed < total {
+		return executionsInterrupted(c, "Batch "+r.verb, executed, total)
+	}
+	if failed > 0 {
+		return commoncli.Problem(fmt.Sprintf("%v of %v workflows failed to %v", failed, total, r.verb), nil)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/tools/cli/clitest"
+)
+
+func TestBatchRunner(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []clitest.CliArgument
+		interrupt      bool
+		expectedError  string
+		expectedOutput []string
+	}{
+		{
+			name:           "runs every entry",
+			args:           []clitest.CliArgument{clitest.IntArgument(FlagConcurrency, 2), clitest.IntArgument(FlagRPS, 100)},
+			expectedError:  "1 of 3 workflows failed to signal",
+			expectedOutput: []string{"Signaled 0\n", "Failed to signal 1\n", "Signaled 2\n", "Signaled 2 of 3 workflows, 1 failed.\n"},
+		},
+		{
+			name:           "interrupted",
+			args:           []clitest.CliArgument{clitest.IntArgument(FlagConcurrency, 1), clitest.IntArgument(FlagRPS, 100)},
+			interrupt:      true,
+			expectedError:  "Batch signal interrupted",
+			expectedOutput: []string{"Signaled 0 of 3 workflows, 0 failed.\n"},
+		},
+		{
+			name:          "concurrency not set",
+			args:          []clitest.CliArgument{clitest.IntArgument(FlagRPS, 100)},
+			expectedError: "--concurrency must be positive",
+		},
+		{
+			name:          "rps not set",
+			args:          []clitest.CliArgument{clitest.IntArgument(FlagConcurrency, 1)},
+			expectedError: "--rps must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newCLITestData(t)
+			cliCtx := clitest.NewCLIContext(t, td.app, tt.args...)
+			if tt.interrupt {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				cliCtx.Context = ctx
+			}
+
+			runner, err := newBatchRunner(cliCtx, "Signaled", "signal")
+			if err == nil {
+				err = runner.run(cliCtx, 3, func(index int) (string, error) {
+					if index == 1 {
+						return fmt.Sprintf("Failed to signal %v", index), errors.New("failed")
+					}
+					return fmt.Sprintf("Signaled %v", index), nil
+				})
+			}
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedError)
+			}
+			for _, line := range tt.expectedOutput {
+				assert.Contains(t, td.consoleOutput(), line)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/fatih/color"
 	"github.com/pborman/uuid"
@@ -102,10 +103,126 @@ func AdminDescribeCluster(c *cli.Context) error {
 		return commoncli.Problem("Operation DescribeCluster failed.", err)
 	}
 
-	prettyPrintJSONObject(getDeps(c).Output(), response)
+	printObject(c, response)
 	return nil
 }
 
+// ClusterMetadataRow is a cluster of the replication configuration of the global domains
+type ClusterMetadataRow struct {
+	Cluster       string `header:"Cluster"`
+	Current       bool   `header:"Current"`
+	Domains       int    `header:"Global Domains"`
+	ActiveDomains int    `header:"Active Domains"`
+}
+
+// ClusterMetadata is the cluster configuration as seen from the cluster the CLI is connected to
+type ClusterMetadata struct {
+	CurrentCluster          string
+	Clusters                []ClusterMetadataRow
+	SupportedClientVersions *types.SupportedClientVersions
+}
+
+// AdminDescribeClusterMetadata prints the current cluster, the clusters known from the replication configuration
+// of the global domains and the supported client versions.
+// The server doesn't expose its cluster metadata, so the current cluster is the active cluster of the local system domain.
+func AdminDescribeClusterMetadata(c *cli.Context) error {
+	adminClient, err := getDeps(c).ServerAdminClient(c)
+	if err != nil {
+		return err
+	}
+	client, err := getCadenceClient(c)
+	if err != nil {
+		return err
+	}
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return commoncli.Problem("Error in creating context: ", err)
+	}
+
+	var metadata ClusterMetadata
+	cluster, err := adminClient.DescribeCluster(ctx)
+	if err != nil {
+		return commoncli.Problem("Operation DescribeCluster failed.", err)
+	}
+	metadata.SupportedClientVersions = cluster.SupportedClientVersions
+	systemDomain, err := client.DescribeDomain(ctx, &types.DescribeDomainRequest{Name: common.StringPtr(common.SystemLocalDomainName)})
+	if err != nil {
+		fmt.Fprintf(getDeps(c).Progress(), "%s failed to resolve the current cluster from domain %v: %v\n", colorRed("Warning:"), common.SystemLocalDomainName, err)
+	} else {
+		metadata.CurrentCluster = systemDomain.ReplicationConfiguration.GetActiveClusterName()
+	}
+	metadata.Clusters, err = listReplicationClusters(c)
+	if err != nil {
+		return commoncli.Problem("Failed to list the replication clusters", err)
+	}
+	for i := range metadata.Clusters {
+		metadata.Clusters[i].Current = metadata.Clusters[i].Cluster == metadata.CurrentCluster
+	}
+
+	switch getOutputFormat(c) {
+	case formatJSON, formatYAML:
+		printObject(c, metadata)
+		return nil
+	}
+	output := getDeps(c).Output()
+	fmt.Fprintf(output, "Current cluster: %v\n", metadata.CurrentCluster)
+	if versions := metadata.SupportedClientVersions; versions != nil {
+		fmt.Fprintf(output, "Supported client versions: go %v, java %v\n", versions.GoSdk, versions.JavaSdk)
+	}
+	return RenderTable(output, metadata.Clusters, RenderOptions{Color: true})
+}
+
+// listReplicationClusters lists the clusters of the replication configuration of all global domains,
+// with the number of domains replicated to and active in each of them
+func listReplicationClusters(c *cli.Context) ([]ClusterMetadataRow, error) {
+	client, err := getCadenceClient(c)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel, err := newContext(c)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	clusters := map[string]*ClusterMetadataRow{}
+	var token []byte
+	for more := true; more; more = len(token) > 0 {
+		resp, err := client.ListDomains(ctx, &types.ListDomainsRequest{
+			PageSize:      failoverDomainsPageSize,
+			NextPageToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, domain := range resp.GetDomains() {
+			if !domain.GetIsGlobalDomain() {
+				continue
+			}
+			for _, cluster := range domain.ReplicationConfiguration.GetClusters() {
+				name := cluster.GetClusterName()
+				row, ok := clusters[name]
+				if !ok {
+					row = &ClusterMetadataRow{Cluster: name}
+					clusters[name] = row
+				}
+				row.Domains++
+				if name == domain.ReplicationConfiguration.GetActiveClusterName() {
+					row.ActiveDomains++
+				}
+			}
+		}
+		token = resp.GetNextPageToken()
+	}
+	rows := make([]ClusterMetadataRow, 0, len(clusters))
+	for _, row := range clusters {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Cluster < rows[j].Cluster })
+	return rows, nil
+}
+
 func AdminRebalanceStart(c *cli.Context) error {
 	client, err := getCadenceClient(c)
 	if err != nil {
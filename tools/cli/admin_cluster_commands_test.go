@@ -23,10 +23,12 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/mock/gomock"
 
@@ -206,6 +208,63 @@ func TestAdminDescribeCluster(t *testing.T) {
 	}
 }
 
+func TestAdminDescribeClusterMetadata(t *testing.T) {
+	globalDomain := func(name, active string, clusters ...string) *types.DescribeDomainResponse {
+		config := &types.DomainReplicationConfiguration{ActiveClusterName: active}
+		for _, cluster := range clusters {
+			config.Clusters = append(config.Clusters, &types.ClusterReplicationConfiguration{ClusterName: cluster})
+		}
+		return &types.DescribeDomainResponse{DomainInfo: &types.DomainInfo{Name: name}, ReplicationConfiguration: config, IsGlobalDomain: true}
+	}
+	mockSetup := func(td *cliTestData, systemDomainErr error) {
+		td.mockAdminClient.EXPECT().DescribeCluster(gomock.Any()).Return(&types.DescribeClusterResponse{
+			SupportedClientVersions: &types.SupportedClientVersions{GoSdk: "1.7.0", JavaSdk: "1.5.0"},
+		}, nil)
+		systemDomain := &types.DescribeDomainResponse{ReplicationConfiguration: &types.DomainReplicationConfiguration{ActiveClusterName: "cluster1"}}
+		if systemDomainErr != nil {
+			systemDomain = nil
+		}
+		td.mockFrontendClient.EXPECT().DescribeDomain(gomock.Any(), &types.DescribeDomainRequest{Name: common.StringPtr(common.SystemLocalDomainName)}).
+			Return(systemDomain, systemDomainErr)
+		td.mockFrontendClient.EXPECT().ListDomains(gomock.Any(), gomock.Any()).Return(&types.ListDomainsResponse{
+			Domains: []*types.DescribeDomainResponse{
+				globalDomain("d1", "cluster1", "cluster1", "cluster2"),
+				globalDomain("d2", "cluster2", "cluster1", "cluster2", "cluster3"),
+				{DomainInfo: &types.DomainInfo{Name: "local"}, ReplicationConfiguration: &types.DomainReplicationConfiguration{ActiveClusterName: "cluster1"}},
+			},
+		}, nil)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		td := newCLITestData(t)
+		mockSetup(td, nil)
+		require.NoError(t, AdminDescribeClusterMetadata(clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagFormat, formatJSON))))
+
+		var metadata ClusterMetadata
+		require.NoError(t, json.Unmarshal([]byte(td.consoleOutput()), &metadata))
+		assert.Equal(t, ClusterMetadata{
+			CurrentCluster: "cluster1",
+			Clusters: []ClusterMetadataRow{
+				{Cluster: "cluster1", Current: true, Domains: 2, ActiveDomains: 1},
+				{Cluster: "cluster2", Domains: 2, ActiveDomains: 1},
+				{Cluster: "cluster3", Domains: 1},
+			},
+			SupportedClientVersions: &types.SupportedClientVersions{GoSdk: "1.7.0", JavaSdk: "1.5.0"},
+		}, metadata)
+	})
+
+	t.Run("table without the current cluster", func(t *testing.T) {
+		td := newCLITestData(t)
+		mockSetup(td, &types.EntityNotExistsError{})
+		require.NoError(t, AdminDescribeClusterMetadata(clitest.NewCLIContext(t, td.app)))
+
+		output := td.consoleOutput()
+		assert.Contains(t, output, "Current cluster: \n")
+		assert.Contains(t, output, "Supported client versions: go 1.7.0, java 1.5.0\n")
+		assert.Contains(t, output, "cluster3")
+	})
+}
+
 func TestAdminRebalanceStart(t *testing.T) {
 	tests := []struct {
 		name           string
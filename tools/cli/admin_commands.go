@@ -22,117 +22,594 @@
 package cli
 
 import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/pborman/uuid"
 	"github.com/urfave/cli/v2"
 
 	"github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/client/admin"
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/codec"
+	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/common/types/mapper/thrift"
 	"github.com/uber/cadence/tools/common/commoncli"
 )
 
 const (
-	tableRenderSize = 10
+	tableRenderSize       = 10
+	historyBranchPageSize = 1000 // events read per page by readHistoryBranchBatches
+
+	// historyHostImbalanceThreshold is how far, as a percentage of the mean, the shard count
+	// of a history host may drift before AdminListHistoryHosts flags it by default
+	historyHostImbalanceThreshold = 20
 )
 
 // AdminShowWorkflow shows history
 func AdminShowWorkflow(c *cli.Context) error {
-	tid := c.String(FlagTreeID)
-	bid := c.String(FlagBranchID)
-	sid := c.Int(FlagShardID)
 	minEventID := c.Int64(FlagMinEventID)
 	maxEventID := c.Int64(FlagMaxEventID)
 	outputFileName := c.String(FlagOutputFilename)
 	domainName := c.String(FlagDomain)
+	if c.Bool(FlagOutputJSONL) && outputFileName == "" {
+		return commoncli.Problem(fmt.Sprintf("--%s requires --%s", FlagOutputJSONL, FlagOutputFilename), nil)
+	}
+	if c.Bool(FlagAppend) && outputFileName != "" && !c.Bool(FlagOutputJSONL) {
+		return commoncli.Problem(fmt.Sprintf("--%s requires --%s, appending a JSON array to the output file makes it invalid JSON", FlagAppend, FlagOutputJSONL), nil)
+	}
+	follow := c.Bool(FlagFollow)
+	if follow && (outputFileName != "" || c.Bool(FlagDecisionChain) || c.Int(FlagMaxEvents) > 0) {
+		return commoncli.Problem(fmt.Sprintf("--%s cannot be used with --%s, --%s or --%s", FlagFollow, FlagOutputFilename, FlagDecisionChain, FlagMaxEvents), nil)
+	}
 	ctx, cancel, err := newContext(c)
 	defer cancel()
 	if err != nil {
 		return commoncli.Problem("Error in creating context: ", err)
 	}
-	serializer := persistence.NewPayloadSerializer()
-	var history []*persistence.DataBlob
-	if len(tid) != 0 {
-		thriftrwEncoder := codec.NewThriftRWEncoder()
-		histV2, err := getDeps(c).initializeHistoryManager(c)
-		if err != nil {
-			return commoncli.Problem("Error in Admin delete WF: ", err)
-		}
-		branchToken, err := thriftrwEncoder.Encode(&shared.HistoryBranch{
-			TreeID:   &tid,
-			BranchID: &bid,
-		})
-		if err != nil {
-			return commoncli.Problem("encoding branch token err", err)
-		}
-		resp, err := histV2.ReadRawHistoryBranch(ctx, &persistence.ReadHistoryBranchRequest{
-			BranchToken: branchToken,
-			MinEventID:  minEventID,
-			MaxEventID:  maxEventID,
-			PageSize:    int(maxEventID - minEventID + 1),
-			ShardID:     &sid,
-			DomainName:  domainName,
-		})
-		if err != nil {
-			return commoncli.Problem("ReadHistoryBranch err", err)
-		}
-
-		history = resp.HistoryEventBlobs
-	} else {
-		return commoncli.Problem("need to specify TreeID/BranchID/ShardID", nil)
+	branchToken, sid, err := getHistoryBranchToken(c)
+	if err != nil {
+		return err
 	}
 
-	if len(history) == 0 {
-		return commoncli.Problem("no events", nil)
+	histV2, err := getDeps(c).initializeHistoryManager(c)
+	if err != nil {
+		return commoncli.Problem("Error in Admin delete WF: ", err)
+	}
+	var activityFilter *activityEventFilter
+	if activityID := c.String(FlagActivityID); activityID != "" {
+		activityFilter = newActivityEventFilter(activityID)
+	}
+	eventTypes, err := parseEventTypes(c.StringSlice(FlagEventType))
+	if err != nil {
+		return commoncli.Problem("Invalid event type", err)
 	}
+	var jsonlWriter *bufio.Writer
+	if c.Bool(FlagOutputJSONL) {
+		f, err := getOutputFile(outputFileName, c.Bool(FlagAppend))
+		if err != nil {
+			return commoncli.Problem("Failed to create history data file.", err)
+		}
+		defer f.Close()
+		jsonlWriter = bufio.NewWriter(f)
+	}
+	decisionChain := c.Bool(FlagDecisionChain)
+	// color helpers are disabled automatically when stdout is not a terminal
+	useColor := c.Bool(FlagColor)
+	decodePayloads := c.Bool(FlagDecodePayloads)
+	maxEvents := c.Int(FlagMaxEvents)
+	truncated := false
+	var decisionEvents []*types.HistoryEvent
 	allEvents := &shared.History{}
+	eventCount := 0
 	totalSize := 0
-	for idx, b := range history {
-		totalSize += len(b.Data)
-		fmt.Printf("======== batch %v, blob len: %v ======\n", idx+1, len(b.Data))
-		internalHistoryBatch, err := serializer.DeserializeBatchEvents(b)
-		if err != nil {
-			return commoncli.Problem("DeserializeBatchEvents err", err)
+	batchCount := 0
+	var lastEventID int64
+	// printBatch prints the events of a batch after the filters and returns the ID of the last event of the batch
+	printBatch := func(blobBytes int, internalHistoryBatch []*types.HistoryEvent) (int64, error) {
+		batchCount++
+		totalSize += blobBytes
+		if !decisionChain {
+			fmt.Printf("======== batch %v, blob len: %v ======\n", batchCount, blobBytes)
+		}
+		var batchLastEventID int64
+		if len(internalHistoryBatch) > 0 {
+			batchLastEventID = internalHistoryBatch[len(internalHistoryBatch)-1].ID
 		}
+		if activityFilter != nil {
+			internalHistoryBatch = activityFilter.filter(internalHistoryBatch)
+		}
+		if len(eventTypes) > 0 {
+			internalHistoryBatch = filterEventsByType(internalHistoryBatch, eventTypes)
+		}
+		if maxEvents > 0 && eventCount+len(internalHistoryBatch) > maxEvents {
+			internalHistoryBatch = internalHistoryBatch[:maxEvents-eventCount]
+			truncated = true
+		}
+		eventCount += len(internalHistoryBatch)
 		historyBatch := thrift.FromHistoryEventArray(internalHistoryBatch)
-		allEvents.Events = append(allEvents.Events, historyBatch...)
-		for _, e := range historyBatch {
+		if jsonlWriter != nil {
+			if err := writeJSONLines(jsonlWriter, historyBatch); err != nil {
+				return 0, commoncli.Problem("Failed to export history data file.", err)
+			}
+		} else {
+			allEvents.Events = append(allEvents.Events, historyBatch...)
+		}
+		if decisionChain {
+			decisionEvents = append(decisionEvents, internalHistoryBatch...)
+			return batchLastEventID, nil
+		}
+		for i, e := range historyBatch {
 			jsonstr, err := json.Marshal(e)
 			if err != nil {
-				return commoncli.Problem("json.Marshal err", err)
+				return 0, commoncli.Problem("json.Marshal err", err)
 			}
-			fmt.Println(string(jsonstr))
+			if decodePayloads {
+				if jsonstr, err = decodeEventPayloads(jsonstr); err != nil {
+					return 0, commoncli.Problem("Failed to decode payloads", err)
+				}
+			}
+			line := string(jsonstr)
+			if colorize, ok := eventCategoryColors[eventCategory(internalHistoryBatch[i].GetEventType())]; useColor && ok {
+				line = colorize(line)
+			}
+			fmt.Println(line)
+		}
+		return batchLastEventID, nil
+	}
+	var printErr error
+	err = readHistoryBranchBatches(ctx, histV2, branchToken, sid, domainName, minEventID, maxEventID, func(blobBytes int, batch []*types.HistoryEvent) error {
+		if truncated {
+			return nil
 		}
+		lastEventID, printErr = printBatch(blobBytes, batch)
+		return printErr
+	})
+	if printErr != nil {
+		return printErr
+	}
+	if err != nil {
+		return commoncli.Problem("ReadHistoryBranch err", err)
+	}
+	if batchCount == 0 {
+		return commoncli.Problem("no events", nil)
+	}
+	if decisionChain {
+		if err := RenderTable(os.Stdout, buildDecisionChain(decisionEvents), RenderOptions{Color: true, Border: true, PrintDateTime: true}); err != nil {
+			return commoncli.Problem("Failed to render decision chain", err)
+		}
+	} else {
+		fmt.Printf("======== total batches %v, total blob len: %v ======\n", batchCount, totalSize)
+	}
+	if truncated {
+		fmt.Printf("======== truncated at %v events ======\n", maxEvents)
 	}
-	fmt.Printf("======== total batches %v, total blob len: %v ======\n", len(history), totalSize)
 
-	if outputFileName != "" {
+	if jsonlWriter != nil {
+		if err := jsonlWriter.Flush(); err != nil {
+			return commoncli.Problem("Failed to export history data file.", err)
+		}
+	} else if outputFileName != "" {
 		data, err := json.Marshal(allEvents.Events)
 		if err != nil {
 			return commoncli.Problem("Failed to serialize history data.", err)
 		}
-		if err := ioutil.WriteFile(outputFileName, data, 0666); err != nil {
+		if err := writeOutputFile(outputFileName, data, false); err != nil {
 			return commoncli.Problem("Failed to export history data file.", err)
 		}
 	}
+	if follow {
+		return followHistoryBranch(c, histV2, branchToken, sid, lastEventID, printBatch)
+	}
+	return nil
+}
+
+// followHistoryBranch reads the history branch every --interval from the event after lastEventID,
+// passing the new batches to printBatch, until interrupted or --max_event_id is reached if set.
+func followHistoryBranch(
+	c *cli.Context,
+	histV2 persistence.HistoryManager,
+	branchToken []byte,
+	shardID int,
+	lastEventID int64,
+	printBatch func(blobBytes int, batch []*types.HistoryEvent) (int64, error),
+) error {
+	interval := c.Duration(FlagInterval)
+	if interval <= 0 {
+		return commoncli.Problem(fmt.Sprintf("--%s must be positive", FlagInterval), nil)
+	}
+	maxEventID := common.EndEventID
+	if c.IsSet(FlagMaxEventID) {
+		maxEventID = c.Int64(FlagMaxEventID)
+	}
+	stop := cancelOnInterrupt(c)
+	defer stop()
+
+	for lastEventID+1 < maxEventID {
+		select {
+		case <-time.After(interval):
+		case <-c.Context.Done():
+			return nil
+		}
+
+		ctx, cancel, err := newContext(c)
+		if err != nil {
+			cancel()
+			return commoncli.Problem("Error in creating context: ", err)
+		}
+		var printErr error
+		err = readHistoryBranchBatches(ctx, histV2, branchToken, shardID, c.String(FlagDomain), lastEventID+1, maxEventID, func(blobBytes int, batch []*types.HistoryEvent) error {
+			var batchLastEventID int64
+			if batchLastEventID, printErr = printBatch(blobBytes, batch); printErr == nil {
+				lastEventID = batchLastEventID
+			}
+			return printErr
+		})
+		cancel()
+		if printErr != nil {
+			return printErr
+		}
+		var notExistsErr *types.EntityNotExistsError
+		if errors.As(err, &notExistsErr) {
+			// no events after lastEventID yet
+			continue
+		}
+		if err != nil {
+			return commoncli.Problem("ReadHistoryBranch err", err)
+		}
+	}
 	return nil
 }
 
+// printMutableStateField prints the value at path in the mutable state, strings without quotes so they can be used in scripts
+func printMutableStateField(c *cli.Context, msStr string, path string) error {
+	ms := persistence.WorkflowMutableState{}
+	if err := json.Unmarshal([]byte(msStr), &ms); err != nil {
+		return commoncli.Problem("json.Unmarshal err", err)
+	}
+	value, err := lookupJSONPath(ms, path)
+	if err != nil {
+		return commoncli.Problem(fmt.Sprintf("JSON path %q not found in mutable state", path), err)
+	}
+	switch v := value.(type) {
+	case map[string]interface{}, []interface{}:
+		printObject(c, v)
+	case nil:
+		fmt.Fprintln(getDeps(c).Output(), "null")
+	default:
+		fmt.Fprintln(getDeps(c).Output(), v)
+	}
+	return nil
+}
+
+// writeJSONLines writes each event as a JSON object on its own line
+func writeJSONLines(w io.Writer, events []*shared.HistoryEvent) error {
+	enc := json.NewEncoder(w)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eventPayloadKeys are the JSON keys of the event attributes which hold payloads encoded by the client
+var eventPayloadKeys = map[string]bool{
+	"input":                   true,
+	"result":                  true,
+	"details":                 true,
+	"control":                 true,
+	"executionContext":        true,
+	"heartbeatDetails":        true,
+	"lastCompletionResult":    true,
+	"lastFailureDetails":      true,
+	"failureDetails":          true,
+	"continuedFailureDetails": true,
+}
+
+// decodeEventPayloads replaces the base64 payloads of a JSON encoded history event, and the values of
+// its header and memo fields, with the JSON they contain. Payloads which are not JSON are left as base64.
+func decodeEventPayloads(event []byte) ([]byte, error) {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(event, &decoded); err != nil {
+		return nil, err
+	}
+	for key, value := range decoded {
+		if attributes, ok := value.(map[string]interface{}); ok && strings.HasSuffix(key, "EventAttributes") {
+			decodePayloadFields(attributes)
+		}
+	}
+	return json.Marshal(decoded)
+}
+
+func decodePayloadFields(attributes map[string]interface{}) {
+	for key, value := range attributes {
+		switch v := value.(type) {
+		case string:
+			if eventPayloadKeys[key] {
+				attributes[key] = decodePayload(v)
+			}
+		case map[string]interface{}:
+			if fields, ok := v["fields"].(map[string]interface{}); ok {
+				for name, field := range fields {
+					if encoded, ok := field.(string); ok {
+						fields[name] = decodePayload(encoded)
+					}
+				}
+			}
+		}
+	}
+}
+
+// decodePayload returns the JSON in a base64 payload, as an array when it holds several JSON values
+// like the encoded arguments of an activity, or the payload unchanged when it is not JSON.
+func decodePayload(encoded string) interface{} {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return encoded
+	}
+	var values []json.RawMessage
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var value json.RawMessage
+		if err := dec.Decode(&value); err == io.EOF {
+			break
+		} else if err != nil {
+			return encoded
+		}
+		values = append(values, value)
+	}
+	switch len(values) {
+	case 0:
+		return encoded
+	case 1:
+		return values[0]
+	default:
+		return values
+	}
+}
+
+// DecisionChainRow is a decision task event shown by `admin workflow show --decision_chain`
+type DecisionChainRow struct {
+	EventID          int64     `header:"Event ID"`
+	EventType        string    `header:"Event Type"`
+	Attempt          int64     `header:"Attempt"`
+	ScheduledEventID int64     `header:"Scheduled Event ID"`
+	Time             time.Time `header:"Time"`
+	Details          string    `header:"Details"`
+}
+
+// buildDecisionChain returns a row per decision task event. Only scheduled events carry the attempt,
+// the other events get it from the scheduled event they refer to.
+func buildDecisionChain(events []*types.HistoryEvent) []DecisionChainRow {
+	attempts := map[int64]int64{}
+	var rows []DecisionChainRow
+	for _, e := range events {
+		row := DecisionChainRow{
+			EventID:   e.ID,
+			EventType: e.GetEventType().String(),
+			Time:      time.Unix(0, e.GetTimestamp()),
+		}
+		switch e.GetEventType() {
+		case types.EventTypeDecisionTaskScheduled:
+			row.ScheduledEventID = e.ID
+			row.Attempt = e.DecisionTaskScheduledEventAttributes.GetAttempt()
+			attempts[e.ID] = row.Attempt
+		case types.EventTypeDecisionTaskStarted:
+			row.ScheduledEventID = e.DecisionTaskStartedEventAttributes.GetScheduledEventID()
+		case types.EventTypeDecisionTaskCompleted:
+			if attr := e.DecisionTaskCompletedEventAttributes; attr != nil {
+				row.ScheduledEventID = attr.ScheduledEventID
+			}
+		case types.EventTypeDecisionTaskFailed:
+			if attr := e.DecisionTaskFailedEventAttributes; attr != nil {
+				row.ScheduledEventID = attr.ScheduledEventID
+				if attr.Cause != nil {
+					row.Details = "cause: " + attr.Cause.String()
+				}
+			}
+		case types.EventTypeDecisionTaskTimedOut:
+			if attr := e.DecisionTaskTimedOutEventAttributes; attr != nil {
+				row.ScheduledEventID = attr.ScheduledEventID
+				if attr.TimeoutType != nil {
+					row.Details = "timeout: " + attr.TimeoutType.String()
+				}
+			}
+		default:
+			continue
+		}
+		if e.GetEventType() != types.EventTypeDecisionTaskScheduled {
+			row.Attempt = attempts[row.ScheduledEventID]
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+const (
+	eventCategoryWorkflow      = "workflow"
+	eventCategoryDecision      = "decision"
+	eventCategoryActivity      = "activity"
+	eventCategoryTimer         = "timer"
+	eventCategorySignal        = "signal"
+	eventCategoryChildWorkflow = "child-workflow"
+)
+
+var eventCategoryColors = map[string]func(a ...interface{}) string{
+	eventCategoryWorkflow:      colorRed,
+	eventCategoryDecision:      colorBlue,
+	eventCategoryActivity:      colorGreen,
+	eventCategoryTimer:         colorYellow,
+	eventCategorySignal:        colorMagenta,
+	eventCategoryChildWorkflow: colorCyan,
+}
+
+// eventCategory returns the category used to color an event, or "" for events that are not colored
+func eventCategory(eventType types.EventType) string {
+	switch eventType {
+	case types.EventTypeWorkflowExecutionStarted,
+		types.EventTypeWorkflowExecutionCompleted,
+		types.EventTypeWorkflowExecutionFailed,
+		types.EventTypeWorkflowExecutionTimedOut,
+		types.EventTypeWorkflowExecutionCancelRequested,
+		types.EventTypeWorkflowExecutionCanceled,
+		types.EventTypeWorkflowExecutionTerminated,
+		types.EventTypeWorkflowExecutionContinuedAsNew:
+		return eventCategoryWorkflow
+	case types.EventTypeDecisionTaskScheduled,
+		types.EventTypeDecisionTaskStarted,
+		types.EventTypeDecisionTaskCompleted,
+		types.EventTypeDecisionTaskTimedOut,
+		types.EventTypeDecisionTaskFailed:
+		return eventCategoryDecision
+	case types.EventTypeActivityTaskScheduled,
+		types.EventTypeActivityTaskStarted,
+		types.EventTypeActivityTaskCompleted,
+		types.EventTypeActivityTaskFailed,
+		types.EventTypeActivityTaskTimedOut,
+		types.EventTypeActivityTaskCancelRequested,
+		types.EventTypeRequestCancelActivityTaskFailed,
+		types.EventTypeActivityTaskCanceled:
+		return eventCategoryActivity
+	case types.EventTypeTimerStarted,
+		types.EventTypeTimerFired,
+		types.EventTypeCancelTimerFailed,
+		types.EventTypeTimerCanceled:
+		return eventCategoryTimer
+	case types.EventTypeWorkflowExecutionSignaled,
+		types.EventTypeSignalExternalWorkflowExecutionInitiated,
+		types.EventTypeSignalExternalWorkflowExecutionFailed,
+		types.EventTypeExternalWorkflowExecutionSignaled:
+		return eventCategorySignal
+	case types.EventTypeStartChildWorkflowExecutionInitiated,
+		types.EventTypeStartChildWorkflowExecutionFailed,
+		types.EventTypeChildWorkflowExecutionStarted,
+		types.EventTypeChildWorkflowExecutionCompleted,
+		types.EventTypeChildWorkflowExecutionFailed,
+		types.EventTypeChildWorkflowExecutionCanceled,
+		types.EventTypeChildWorkflowExecutionTimedOut,
+		types.EventTypeChildWorkflowExecutionTerminated:
+		return eventCategoryChildWorkflow
+	}
+	return ""
+}
+
+// parseEventTypes returns the set of event type names given by --event_type
+func parseEventTypes(names []string) (map[string]bool, error) {
+	eventTypes := make(map[string]bool, len(names))
+	for _, name := range names {
+		var eventType shared.EventType
+		if err := eventType.UnmarshalText([]byte(name)); err != nil {
+			return nil, err
+		}
+		eventTypes[eventType.String()] = true
+	}
+	return eventTypes, nil
+}
+
+func filterEventsByType(events []*types.HistoryEvent, eventTypes map[string]bool) []*types.HistoryEvent {
+	var result []*types.HistoryEvent
+	for _, e := range events {
+		if eventTypes[e.GetEventType().String()] {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// activityEventFilter selects the events that belong to a single activity.
+// Only scheduled and cancel-request events carry the ActivityID, the rest of the
+// lifecycle refers back to the scheduled event, so scheduled event IDs are tracked as events are seen.
+type activityEventFilter struct {
+	activityID        string
+	scheduledEventIDs map[int64]struct{}
+}
+
+func newActivityEventFilter(activityID string) *activityEventFilter {
+	return &activityEventFilter{
+		activityID:        activityID,
+		scheduledEventIDs: map[int64]struct{}{},
+	}
+}
+
+func (f *activityEventFilter) filter(events []*types.HistoryEvent) []*types.HistoryEvent {
+	var result []*types.HistoryEvent
+	for _, e := range events {
+		if f.matches(e) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func (f *activityEventFilter) matches(e *types.HistoryEvent) bool {
+	var scheduledEventID int64
+	switch e.GetEventType() {
+	case types.EventTypeActivityTaskScheduled:
+		if e.ActivityTaskScheduledEventAttributes.GetActivityID() != f.activityID {
+			return false
+		}
+		f.scheduledEventIDs[e.ID] = struct{}{}
+		return true
+	case types.EventTypeActivityTaskCancelRequested:
+		return e.ActivityTaskCancelRequestedEventAttributes.GetActivityID() == f.activityID
+	case types.EventTypeRequestCancelActivityTaskFailed:
+		attr := e.RequestCancelActivityTaskFailedEventAttributes
+		return attr != nil && attr.ActivityID == f.activityID
+	case types.EventTypeActivityTaskStarted:
+		scheduledEventID = e.ActivityTaskStartedEventAttributes.GetScheduledEventID()
+	case types.EventTypeActivityTaskCompleted:
+		scheduledEventID = e.ActivityTaskCompletedEventAttributes.GetScheduledEventID()
+	case types.EventTypeActivityTaskFailed:
+		scheduledEventID = e.ActivityTaskFailedEventAttributes.GetScheduledEventID()
+	case types.EventTypeActivityTaskTimedOut:
+		scheduledEventID = e.ActivityTaskTimedOutEventAttributes.GetScheduledEventID()
+	case types.EventTypeActivityTaskCanceled:
+		scheduledEventID = e.ActivityTaskCanceledEventAttributes.GetScheduledEventID()
+	default:
+		return false
+	}
+	_, ok := f.scheduledEventIDs[scheduledEventID]
+	return ok
+}
+
 // AdminDescribeWorkflow describe a new workflow execution for admin
 func AdminDescribeWorkflow(c *cli.Context) error {
+	raw := c.Bool(FlagRaw)
+	if raw && (c.Bool(FlagDecodeSearchAttributes) || c.Bool(FlagShowTimers)) {
+		return commoncli.Problem(fmt.Sprintf("--%s cannot be used with --%s or --%s, they decode the mutable state", FlagRaw, FlagDecodeSearchAttributes, FlagShowTimers), nil)
+	}
 
 	resp, err := describeMutableState(c)
 	if err != nil {
 		return err
 	}
-	prettyPrintJSONObject(getDeps(c).Output(), resp)
+	if path := c.String(FlagJSONPath); path != "" {
+		return printMutableStateField(c, resp.GetMutableStateInDatabase(), path)
+	}
+	printObject(c, resp)
+	if raw {
+		// the response already holds the mutable state as stored in the database
+		return nil
+	}
 
 	if resp != nil {
 		msStr := resp.GetMutableStateInDatabase()
@@ -141,14 +618,9 @@ func AdminDescribeWorkflow(c *cli.Context) error {
 		if err != nil {
 			return commoncli.Problem("json.Unmarshal err", err)
 		}
-		currentBranchToken := ms.ExecutionInfo.BranchToken
-		if ms.VersionHistories != nil {
-			// if VersionHistories is set, then all branch infos are stored in VersionHistories
-			currentVersionHistory, err := ms.VersionHistories.GetCurrentVersionHistory()
-			if err != nil {
-				return commoncli.Problem("ms.VersionHistories.GetCurrentVersionHistory err", err)
-			}
-			currentBranchToken = currentVersionHistory.GetBranchToken()
+		currentBranchToken, err := getCurrentBranchToken(&ms)
+		if err != nil {
+			return commoncli.Problem("ms.VersionHistories.GetCurrentVersionHistory err", err)
 		}
 
 		branchInfo := shared.HistoryBranch{}
@@ -157,7 +629,7 @@ func AdminDescribeWorkflow(c *cli.Context) error {
 		if err != nil {
 			return commoncli.Problem("thriftrwEncoder.Decode err", err)
 		}
-		prettyPrintJSONObject(getDeps(c).Output(), branchInfo)
+		printObject(c, branchInfo)
 		if ms.ExecutionInfo.AutoResetPoints != nil {
 			getDeps(c).Output().Write([]byte("auto-reset-points:"))
 			for _, p := range ms.ExecutionInfo.AutoResetPoints.Points {
@@ -166,10 +638,478 @@ func AdminDescribeWorkflow(c *cli.Context) error {
 				getDeps(c).Output().Write([]byte(fmt.Sprintln(p.GetBinaryChecksum(), p.GetRunID(), p.GetFirstDecisionCompletedID(), p.GetResettable(), createT, expireT)))
 			}
 		}
+		if c.Bool(FlagDecodeSearchAttributes) {
+			searchAttributes, err := decodeSearchAttributes(ms.ExecutionInfo.SearchAttributes)
+			if err != nil {
+				return commoncli.Problem("Failed to decode search attributes", err)
+			}
+			keys := make([]string, 0, len(searchAttributes))
+			for k := range searchAttributes {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			getDeps(c).Output().Write([]byte("search-attributes:\n"))
+			for _, k := range keys {
+				getDeps(c).Output().Write([]byte(fmt.Sprintf("  %v: %v\n", k, searchAttributes[k])))
+			}
+		}
+		if c.Bool(FlagShowTimers) {
+			printPendingTimers(getDeps(c).Output(), ms.TimerInfos, time.Now())
+		}
+	}
+	return nil
+}
+
+// printPendingTimers lists the user timers of a mutable state by fire time, relative to now
+func printPendingTimers(w io.Writer, timerInfos map[string]*persistence.TimerInfo, now time.Time) {
+	timers := make([]*persistence.TimerInfo, 0, len(timerInfos))
+	for _, ti := range timerInfos {
+		timers = append(timers, ti)
+	}
+	sort.Slice(timers, func(i, j int) bool {
+		if !timers[i].ExpiryTime.Equal(timers[j].ExpiryTime) {
+			return timers[i].ExpiryTime.Before(timers[j].ExpiryTime)
+		}
+		return timers[i].TimerID < timers[j].TimerID
+	})
+
+	fmt.Fprintf(w, "pending-timers: %d\n", len(timers))
+	for _, ti := range timers {
+		fireTime := ti.ExpiryTime.UTC().Format(time.RFC3339)
+		if ti.ExpiryTime.After(now) {
+			fmt.Fprintf(w, "  %v: fires at %v (in %v)\n", ti.TimerID, fireTime, ti.ExpiryTime.Sub(now).Round(time.Second))
+		} else {
+			fmt.Fprintf(w, "  %v: fires at %v (%v ago, overdue)\n", ti.TimerID, fireTime, now.Sub(ti.ExpiryTime).Round(time.Second))
+		}
+	}
+}
+
+// WorkflowBranchRow is a row of the history branch table in `admin workflow list-branches`
+type WorkflowBranchRow struct {
+	Current     bool   `header:"Current"`
+	TreeID      string `header:"TreeID"`
+	BranchID    string `header:"BranchID"`
+	Ancestors   string `header:"Ancestors"`
+	LastEventID int64  `header:"Last Event ID"`
+	LastVersion int64  `header:"Last Version"`
+	BranchToken string `header:"Branch Token"`
+}
+
+// AdminListWorkflowBranches lists every version history branch of a workflow execution
+func AdminListWorkflowBranches(c *cli.Context) error {
+	resp, err := describeMutableState(c)
+	if err != nil {
+		return err
+	}
+	ms := persistence.WorkflowMutableState{}
+	if err := json.Unmarshal([]byte(resp.GetMutableStateInDatabase()), &ms); err != nil {
+		return commoncli.Problem("json.Unmarshal err", err)
+	}
+	rows, err := buildWorkflowBranchRows(&ms)
+	if err != nil {
+		return err
+	}
+	return Render(c, rows, RenderOptions{DefaultTemplate: templateTable, Color: true})
+}
+
+// buildWorkflowBranchRows decodes the branch of every version history of the mutable state
+func buildWorkflowBranchRows(ms *persistence.WorkflowMutableState) ([]WorkflowBranchRow, error) {
+	histories := []*persistence.VersionHistory{{BranchToken: ms.ExecutionInfo.BranchToken}}
+	currentIndex := 0
+	if ms.VersionHistories != nil {
+		histories = ms.VersionHistories.Histories
+		currentIndex = ms.VersionHistories.CurrentVersionHistoryIndex
+	}
+
+	thriftrwEncoder := codec.NewThriftRWEncoder()
+	rows := make([]WorkflowBranchRow, 0, len(histories))
+	for i, history := range histories {
+		branchInfo := shared.HistoryBranch{}
+		if err := thriftrwEncoder.Decode(history.BranchToken, &branchInfo); err != nil {
+			return nil, commoncli.Problem(fmt.Sprintf("decoding branch token of version history %d err", i), err)
+		}
+		row := WorkflowBranchRow{
+			Current:     i == currentIndex,
+			TreeID:      branchInfo.GetTreeID(),
+			BranchID:    branchInfo.GetBranchID(),
+			Ancestors:   formatBranchAncestors(branchInfo.Ancestors),
+			BranchToken: base64.StdEncoding.EncodeToString(history.BranchToken),
+		}
+		if len(history.Items) > 0 {
+			lastItem := history.Items[len(history.Items)-1]
+			row.LastEventID = lastItem.EventID
+			row.LastVersion = lastItem.Version
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// WorkflowDump bundles the mutable state, the decoded branches and the history of a workflow execution,
+// to be attached to support cases
+type WorkflowDump struct {
+	Domain       string
+	WorkflowID   string
+	RunID        string
+	ShardID      string
+	HistoryAddr  string
+	Branches     []WorkflowBranchRow
+	MutableState json.RawMessage `json:",omitempty"`
+	History      *types.History  `json:",omitempty"`
+}
+
+// AdminDumpWorkflow writes the mutable state, the decoded branches and the full history of a workflow execution
+// to a single JSON document, or to a zip archive when the output file name ends with .zip
+func AdminDumpWorkflow(c *cli.Context) error {
+	resp, err := describeMutableState(c)
+	if err != nil {
+		return err
+	}
+	ms := persistence.WorkflowMutableState{}
+	if err := json.Unmarshal([]byte(resp.GetMutableStateInDatabase()), &ms); err != nil {
+		return commoncli.Problem("json.Unmarshal err", err)
+	}
+	branches, err := buildWorkflowBranchRows(&ms)
+	if err != nil {
+		return err
+	}
+
+	frontendClient, err := getDeps(c).ServerFrontendClient(c)
+	if err != nil {
+		return err
+	}
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return commoncli.Problem("Error in creating context: ", err)
+	}
+	domain := c.String(FlagDomain)
+	history, err := GetHistory(ctx, frontendClient, domain, ms.ExecutionInfo.WorkflowID, ms.ExecutionInfo.RunID)
+	if err != nil {
+		return commoncli.Problem("Failed to get history", err)
+	}
+
+	dump := WorkflowDump{
+		Domain:       domain,
+		WorkflowID:   ms.ExecutionInfo.WorkflowID,
+		RunID:        ms.ExecutionInfo.RunID,
+		ShardID:      resp.GetShardID(),
+		HistoryAddr:  resp.HistoryAddr,
+		Branches:     branches,
+		MutableState: json.RawMessage(resp.GetMutableStateInDatabase()),
+		History:      history,
+	}
+	outputFileName := c.String(FlagOutputFilename)
+	if outputFileName == "" {
+		data, err := json.MarshalIndent(dump, "", "  ")
+		if err != nil {
+			return commoncli.Problem("Failed to serialize workflow dump", err)
+		}
+		_, err = fmt.Fprintln(getDeps(c).Output(), string(data))
+		return err
+	}
+	if err := writeWorkflowDump(outputFileName, dump); err != nil {
+		return commoncli.Problem("Failed to write workflow dump", err)
+	}
+	fmt.Fprintf(getDeps(c).Output(), "Dumped %v events of %v %v to %v\n", len(history.Events), dump.WorkflowID, dump.RunID, outputFileName)
+	return nil
+}
+
+// writeWorkflowDump writes the dump to a JSON file, or to a zip archive with the mutable state and the history
+// in their own entries when the file name ends with .zip
+func writeWorkflowDump(fileName string, dump WorkflowDump) (err error) {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	if !strings.HasSuffix(fileName, ".zip") {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(dump)
+	}
+
+	workflow := dump
+	workflow.MutableState, workflow.History = nil, nil
+	entries := []struct {
+		name  string
+		value interface{}
+	}{
+		{"workflow.json", workflow},
+		{"mutable_state.json", dump.MutableState},
+		{"history.json", dump.History},
+	}
+
+	zw := zip.NewWriter(f)
+	for _, entry := range entries {
+		w, err := zw.Create(entry.name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entry.value); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// formatBranchAncestors prints each ancestor as BranchID[BeginNodeID, EndNodeID)
+func formatBranchAncestors(ancestors []*shared.HistoryBranchRange) string {
+	formatted := make([]string, 0, len(ancestors))
+	for _, ancestor := range ancestors {
+		formatted = append(formatted, fmt.Sprintf("%v[%v, %v)", ancestor.GetBranchID(), ancestor.GetBeginNodeID(), ancestor.GetEndNodeID()))
+	}
+	return strings.Join(formatted, " ")
+}
+
+// getHistoryBranchToken returns the history branch token and shard given either by
+// TreeID/BranchID/ShardID, BranchToken/ShardID or WorkflowID/RunID.
+func getHistoryBranchToken(c *cli.Context) ([]byte, int, error) {
+	tid := c.String(FlagTreeID)
+	bid := c.String(FlagBranchID)
+	encodedBranchToken := c.String(FlagBranchToken)
+	sid := c.Int(FlagShardID)
+	branchToken, err := encodeBranchToken(FlagBranchToken, encodedBranchToken, tid, bid)
+	if err != nil {
+		return nil, 0, err
+	}
+	if branchToken == nil && len(c.String(FlagWorkflowID)) == 0 {
+		return nil, 0, commoncli.Problem("need to specify TreeID/BranchID/ShardID, BranchToken/ShardID or WorkflowID/RunID", nil)
+	}
+	if len(c.String(FlagWorkflowID)) == 0 {
+		return branchToken, sid, nil
+	}
+
+	descResp, err := describeMutableState(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	ms := persistence.WorkflowMutableState{}
+	if err := json.Unmarshal([]byte(descResp.GetMutableStateInDatabase()), &ms); err != nil {
+		return nil, 0, commoncli.Problem("json.Unmarshal err", err)
+	}
+	currentBranchToken, err := getCurrentBranchToken(&ms)
+	if err != nil {
+		return nil, 0, commoncli.Problem("ms.VersionHistories.GetCurrentVersionHistory err", err)
+	}
+	if !c.IsSet(FlagShardID) {
+		sid, err = strconv.Atoi(descResp.GetShardID())
+		if err != nil {
+			return nil, 0, commoncli.Problem("invalid shard ID in describe response", err)
+		}
+	}
+	if branchToken == nil {
+		return currentBranchToken, sid, nil
+	}
+	// the requested branch was given explicitly, the workflow is only used to check it is the current one
+	if err := warnIfNotCurrentBranch(getDeps(c).Progress(), branchToken, currentBranchToken); err != nil {
+		return nil, 0, err
+	}
+	return branchToken, sid, nil
+}
+
+// encodeBranchToken returns the branch token from the base64 encoded token given with tokenFlag,
+// or else encodes the TreeID/BranchID pair. It returns nil when neither is given.
+func encodeBranchToken(tokenFlag, encodedBranchToken, tid, bid string) ([]byte, error) {
+	switch {
+	case len(encodedBranchToken) != 0:
+		if len(tid) != 0 || len(bid) != 0 {
+			return nil, commoncli.Problem(fmt.Sprintf("--%s cannot be used together with TreeID/BranchID", tokenFlag), nil)
+		}
+		branchToken, err := base64.StdEncoding.DecodeString(encodedBranchToken)
+		if err != nil {
+			return nil, commoncli.Problem("decoding branch token err", err)
+		}
+		return branchToken, nil
+	case len(tid) != 0:
+		thriftrwEncoder := codec.NewThriftRWEncoder()
+		branchToken, err := thriftrwEncoder.Encode(&shared.HistoryBranch{
+			TreeID:   &tid,
+			BranchID: &bid,
+		})
+		if err != nil {
+			return nil, commoncli.Problem("encoding branch token err", err)
+		}
+		return branchToken, nil
+	}
+	return nil, nil
+}
+
+// warnIfNotCurrentBranch warns when the requested history branch is not the current branch of the workflow,
+// as the events of an abandoned branch (e.g. after a reset or a conflict resolution) are not the workflow's history.
+func warnIfNotCurrentBranch(w io.Writer, branchToken, currentBranchToken []byte) error {
+	thriftrwEncoder := codec.NewThriftRWEncoder()
+	var requested, current shared.HistoryBranch
+	if err := thriftrwEncoder.Decode(branchToken, &requested); err != nil {
+		return commoncli.Problem("thriftrwEncoder.Decode err", err)
+	}
+	if err := thriftrwEncoder.Decode(currentBranchToken, &current); err != nil {
+		return commoncli.Problem("thriftrwEncoder.Decode err", err)
+	}
+	if requested.GetTreeID() != current.GetTreeID() || requested.GetBranchID() != current.GetBranchID() {
+		fmt.Fprintf(w, "%s the requested branch (TreeID: %v, BranchID: %v) is not the current branch of the workflow (TreeID: %v, BranchID: %v)\n",
+			colorRed("Warning:"), requested.GetTreeID(), requested.GetBranchID(), current.GetTreeID(), current.GetBranchID())
+	}
+	return nil
+}
+
+// AdminCountHistoryEvents counts the events and blob bytes of a history branch without printing the events
+func AdminCountHistoryEvents(c *cli.Context) error {
+	minEventID := c.Int64(FlagMinEventID)
+	maxEventID := c.Int64(FlagMaxEventID)
+	domainName := c.String(FlagDomain)
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return commoncli.Problem("Error in creating context: ", err)
+	}
+	branchToken, sid, err := getHistoryBranchToken(c)
+	if err != nil {
+		return err
+	}
+	histV2, err := getDeps(c).initializeHistoryManager(c)
+	if err != nil {
+		return commoncli.Problem("Error in Admin count history events: ", err)
+	}
+	defer histV2.Close()
+
+	var counts historyEventCounts
+	err = readHistoryBranchBatches(ctx, histV2, branchToken, sid, domainName, minEventID, maxEventID, func(blobBytes int, events []*types.HistoryEvent) error {
+		counts.add(blobBytes, events)
+		return nil
+	})
+	if err != nil {
+		return commoncli.Problem("Failed to read the history branch", err)
+	}
+
+	output := getDeps(c).Output()
+	fmt.Fprintf(output, "batches: %v\n", counts.Batches)
+	fmt.Fprintf(output, "events: %v\n", counts.Events)
+	fmt.Fprintf(output, "blob bytes: %v\n", counts.BlobBytes)
+	if counts.Events > 0 {
+		fmt.Fprintf(output, "min event ID: %v\n", counts.MinEventID)
+		fmt.Fprintf(output, "max event ID: %v\n", counts.MaxEventID)
+	}
+	return nil
+}
+
+// readHistoryBranchBatches reads the event batches of a branch from minEventID (inclusive) to maxEventID (exclusive) page by page,
+// calling fn with the events of each batch and the size of its blob.
+func readHistoryBranchBatches(
+	ctx context.Context,
+	histV2 persistence.HistoryManager,
+	branchToken []byte,
+	shardID int,
+	domainName string,
+	minEventID int64,
+	maxEventID int64,
+	fn func(blobBytes int, events []*types.HistoryEvent) error,
+) error {
+	serializer := persistence.NewPayloadSerializer()
+	var pageToken []byte
+	for {
+		resp, err := histV2.ReadRawHistoryBranch(ctx, &persistence.ReadHistoryBranchRequest{
+			BranchToken:   branchToken,
+			MinEventID:    minEventID,
+			MaxEventID:    maxEventID,
+			PageSize:      historyBranchPageSize,
+			NextPageToken: pageToken,
+			ShardID:       &shardID,
+			DomainName:    domainName,
+		})
+		if err != nil {
+			return err
+		}
+		for _, blob := range resp.HistoryEventBlobs {
+			events, err := serializer.DeserializeBatchEvents(blob)
+			if err != nil {
+				return fmt.Errorf("deserializing history batch: %w", err)
+			}
+			if err := fn(len(blob.Data), events); err != nil {
+				return err
+			}
+		}
+		if len(resp.NextPageToken) == 0 {
+			return nil
+		}
+		pageToken = resp.NextPageToken
 	}
+}
+
+type historyEventCounts struct {
+	Batches    int
+	Events     int
+	BlobBytes  int
+	MinEventID int64
+	MaxEventID int64
+}
+
+func (h *historyEventCounts) add(blobBytes int, events []*types.HistoryEvent) {
+	h.Batches++
+	h.BlobBytes += blobBytes
+	for _, e := range events {
+		if h.Events == 0 || e.ID < h.MinEventID {
+			h.MinEventID = e.ID
+		}
+		if h.Events == 0 || e.ID > h.MaxEventID {
+			h.MaxEventID = e.ID
+		}
+		h.Events++
+	}
+}
+
+func getCurrentBranchToken(ms *persistence.WorkflowMutableState) ([]byte, error) {
+	if ms.VersionHistories != nil {
+		// if VersionHistories is set, then all branch infos are stored in VersionHistories
+		currentVersionHistory, err := ms.VersionHistories.GetCurrentVersionHistory()
+		if err != nil {
+			return nil, err
+		}
+		return currentVersionHistory.GetBranchToken(), nil
+	}
+	return ms.ExecutionInfo.BranchToken, nil
+}
+
+// AdminDecodeBranchToken decodes a base64 encoded history branch token
+func AdminDecodeBranchToken(c *cli.Context) error {
+	encodedBranchToken, err := getRequiredOption(c, FlagBranchToken)
+	if err != nil {
+		return commoncli.Problem("Required flag not found", err)
+	}
+	branchToken, err := base64.StdEncoding.DecodeString(encodedBranchToken)
+	if err != nil {
+		return commoncli.Problem("decoding branch token err", err)
+	}
+	branchInfo := shared.HistoryBranch{}
+	if err := codec.NewThriftRWEncoder().Decode(branchToken, &branchInfo); err != nil {
+		return commoncli.Problem("thriftrwEncoder.Decode err", err)
+	}
+	printObject(c, branchInfo)
 	return nil
 }
 
+// decodeSearchAttributes decodes the JSON encoded search attribute values stored in mutable state.
+// Numbers are kept as json.Number so that int values are printed without losing precision.
+func decodeSearchAttributes(searchAttributes map[string][]byte) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(searchAttributes))
+	for k, v := range searchAttributes {
+		dec := json.NewDecoder(bytes.NewReader(v))
+		dec.UseNumber()
+		var decoded interface{}
+		if err := dec.Decode(&decoded); err != nil {
+			return nil, fmt.Errorf("decoding search attribute %v: %w", k, err)
+		}
+		result[k] = decoded
+	}
+	return result, nil
+}
+
 func describeMutableState(c *cli.Context) (*types.AdminDescribeWorkflowExecutionResponse, error) {
 	adminClient, err := getDeps(c).ServerAdminClient(c)
 	if err != nil {
@@ -249,19 +1189,52 @@ func AdminDeleteWorkflow(c *cli.Context) error {
 	if err != nil {
 		return commoncli.Problem("Required flag not found", err)
 	}
-	wid, err := getRequiredOption(c, FlagWorkflowID)
-	if err != nil {
-		return commoncli.Problem("Required flag not found", err)
-	}
-	rid := c.String(FlagRunID)
 	remote := c.Bool(FlagRemote)
 	skipError := c.Bool(FlagSkipErrorMode)
+	historyOnly := c.Bool(FlagHistoryOnly)
+	mutableStateOnly := c.Bool(FlagMutableStateOnly)
+	currentRowOnly := c.Bool(FlagCurrentRowOnly)
+	scopes := 0
+	for _, scoped := range []bool{historyOnly, mutableStateOnly, currentRowOnly} {
+		if scoped {
+			scopes++
+		}
+	}
+	if scopes > 1 {
+		return commoncli.Problem(fmt.Sprintf("only one of --%s, --%s and --%s can be used", FlagHistoryOnly, FlagMutableStateOnly, FlagCurrentRowOnly), nil)
+	}
+	if remote && scopes > 0 {
+		return commoncli.Problem(fmt.Sprintf("--%s, --%s and --%s are not supported with --%s", FlagHistoryOnly, FlagMutableStateOnly, FlagCurrentRowOnly, FlagRemote), nil)
+	}
+	branchToken, err := encodeBranchToken(FlagBranchToken, c.String(FlagBranchToken), c.String(FlagTreeID), c.String(FlagBranchID))
+	if err != nil {
+		return err
+	}
+	if branchToken != nil && !historyOnly {
+		return commoncli.Problem(fmt.Sprintf("TreeID/BranchID and --%s can only be used with --%s", FlagBranchToken, FlagHistoryOnly), nil)
+	}
 
 	ctx, cancel, err := newContext(c)
 	defer cancel()
 	if err != nil {
 		return commoncli.Problem("Error in creating context: ", err)
 	}
+	// With a branch token the history is deleted directly, which works even if the mutable state is already gone
+	if branchToken != nil {
+		if !c.IsSet(FlagShardID) {
+			return commoncli.Problem(fmt.Sprintf("--%s is required with TreeID/BranchID or --%s", FlagShardID, FlagBranchToken), nil)
+		}
+		return deleteHistoryBranches(ctx, c, [][]byte{branchToken}, c.Int(FlagShardID), domain, skipError)
+	}
+
+	wid, err := getRequiredOption(c, FlagWorkflowID)
+	if err != nil {
+		return commoncli.Problem("Required flag not found", err)
+	}
+	rid := c.String(FlagRunID)
+	if currentRowOnly {
+		return deleteCurrentRowOnly(ctx, c, domain, wid, skipError)
+	}
 	// With remote flag, we run the command on the server side using existing APIs
 	// Without remote, commands are run directly through some DB clients. This is
 	// useful if server is down somehow. However, we only support couple DB clients
@@ -303,36 +1276,113 @@ func AdminDeleteWorkflow(c *cli.Context) error {
 	if err != nil {
 		return commoncli.Problem("strconv.Atoi(shardID) err", err)
 	}
-	histV2, err := getDeps(c).initializeHistoryManager(c)
-	defer histV2.Close()
-	if err != nil {
-		return commoncli.Problem("Error in Admin delete WF: ", err)
+	if !mutableStateOnly {
+		if err := deleteHistoryBranches(ctx, c, mutableStateBranchTokens(ms), shardIDInt, domain, skipError); err != nil {
+			return err
+		}
+	}
+	if historyOnly {
+		return nil
 	}
+
 	exeStore, err := getDeps(c).initializeExecutionManager(c, shardIDInt)
 	if err != nil {
 		return commoncli.Problem("Error in Admin delete WF: ", err)
 	}
-	branchInfo := shared.HistoryBranch{}
-	thriftrwEncoder := codec.NewThriftRWEncoder()
-	branchTokens := [][]byte{ms.ExecutionInfo.BranchToken}
-	if ms.VersionHistories != nil {
-		// if VersionHistories is set, then all branch infos are stored in VersionHistories
-		branchTokens = [][]byte{}
-		for _, versionHistory := range ms.VersionHistories.ToInternalType().Histories {
-			branchTokens = append(branchTokens, versionHistory.BranchToken)
+	req := &persistence.DeleteWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: wid,
+		RunID:      rid,
+		DomainName: domain,
+	}
+
+	err = exeStore.DeleteWorkflowExecution(ctx, req)
+	if err != nil {
+		if skipError {
+			fmt.Println("delete mutableState row failed, ", err)
+		} else {
+			return commoncli.Problem("delete mutableState row failed", err)
 		}
 	}
+	fmt.Println("delete mutableState row successfully")
+
+	runID := rid
+	if runID == "" {
+		runID = ms.ExecutionInfo.RunID
+	}
+	return deleteCurrentExecution(ctx, exeStore, domainID, domain, wid, runID, c.Bool(FlagForceCurrent), skipError)
+}
 
+// deleteCurrentRowOnly deletes the current execution row of the workflow without reading its mutable state,
+// which may already be gone. The domain ID is resolved through the frontend and the shard from the workflow ID.
+func deleteCurrentRowOnly(ctx context.Context, c *cli.Context, domain, wid string, skipError bool) error {
+	rid, err := getRequiredOption(c, FlagRunID)
+	if err != nil {
+		return commoncli.Problem("Required flag not found", err)
+	}
+	shardID := c.Int(FlagShardID)
+	if !c.IsSet(FlagShardID) {
+		numberOfShards := c.Int(FlagNumberOfShards)
+		if numberOfShards <= 0 {
+			return commoncli.Problem(fmt.Sprintf("--%s or --%s is required with --%s", FlagShardID, FlagNumberOfShards, FlagCurrentRowOnly), nil)
+		}
+		shardID = common.WorkflowIDToHistoryShard(wid, numberOfShards)
+	}
+	client, err := getCadenceClient(c)
+	if err != nil {
+		return err
+	}
+	domainID, err := getDomainID(ctx, domain, client)
+	if err != nil {
+		return commoncli.Problem("Failed to resolve the domain ID", err)
+	}
+	exeStore, err := getDeps(c).initializeExecutionManager(c, shardID)
+	if err != nil {
+		return commoncli.Problem("Error in Admin delete WF: ", err)
+	}
+	return deleteCurrentExecution(ctx, exeStore, domainID, domain, wid, rid, c.Bool(FlagForceCurrent), skipError)
+}
+
+// mutableStateBranchTokens returns the branch tokens of every history branch of the workflow.
+func mutableStateBranchTokens(ms persistence.WorkflowMutableState) [][]byte {
+	if ms.VersionHistories == nil {
+		return [][]byte{ms.ExecutionInfo.BranchToken}
+	}
+	// if VersionHistories is set, then all branch infos are stored in VersionHistories
+	branchTokens := [][]byte{}
+	for _, versionHistory := range ms.VersionHistories.ToInternalType().Histories {
+		branchTokens = append(branchTokens, versionHistory.BranchToken)
+	}
+	return branchTokens
+}
+
+// deleteHistoryBranches deletes the history events of the given branches.
+func deleteHistoryBranches(
+	ctx context.Context,
+	c *cli.Context,
+	branchTokens [][]byte,
+	shardID int,
+	domain string,
+	skipError bool,
+) error {
+	histV2, err := getDeps(c).initializeHistoryManager(c)
+	if err != nil {
+		return commoncli.Problem("Error in Admin delete WF: ", err)
+	}
+	defer histV2.Close()
+
+	branchInfo := shared.HistoryBranch{}
+	thriftrwEncoder := codec.NewThriftRWEncoder()
 	for _, branchToken := range branchTokens {
 		err = thriftrwEncoder.Decode(branchToken, &branchInfo)
 		if err != nil {
 			return commoncli.Problem("thriftrwEncoder.Decode err", err)
 		}
 		fmt.Println("deleting history events for ...")
-		prettyPrintJSONObject(getDeps(c).Output(), branchInfo)
+		printObject(c, branchInfo)
 		err = histV2.DeleteHistoryBranch(ctx, &persistence.DeleteHistoryBranchRequest{
 			BranchToken: branchToken,
-			ShardID:     &shardIDInt,
+			ShardID:     &shardID,
 			DomainName:  domain,
 		})
 		if err != nil {
@@ -343,47 +1393,220 @@ func AdminDeleteWorkflow(c *cli.Context) error {
 			}
 		}
 	}
+	return nil
+}
+
+// deleteCurrentExecution deletes the current execution row of the workflow, but only if it points to runID
+// (or force is set), since deleting an old run must not clobber the pointer to a newer run.
+func deleteCurrentExecution(
+	ctx context.Context,
+	exeStore persistence.ExecutionManager,
+	domainID string,
+	domainName string,
+	wid string,
+	runID string,
+	force bool,
+	skipError bool,
+) error {
+	current, err := exeStore.GetCurrentExecution(ctx, &persistence.GetCurrentExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: wid,
+		DomainName: domainName,
+	})
+	switch {
+	case err == nil:
+		fmt.Printf("current row points to runID %v\n", current.RunID)
+		if current.RunID != runID && !force {
+			fmt.Printf("skipped deleting current row as it does not point to runID %v, use --%s to delete it anyway\n", runID, FlagForceCurrent)
+			return nil
+		}
+	case errors.As(err, new(*types.EntityNotExistsError)):
+		fmt.Println("current row not found, nothing to delete")
+		return nil
+	case skipError:
+		fmt.Println("get current row failed, ", err)
+	default:
+		return commoncli.Problem("get current row failed", err)
+	}
+
+	err = exeStore.DeleteCurrentWorkflowExecution(ctx, &persistence.DeleteCurrentWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: wid,
+		RunID:      runID,
+	})
+	if err != nil {
+		if skipError {
+			fmt.Println("delete current row failed, ", err)
+		} else {
+			return commoncli.Problem("delete current row failed", err)
+		}
+	}
+	fmt.Println("delete current row successfully")
+	return nil
+}
+
+// DomainReplicationRow is a cluster of the replication configuration shown by `admin domain describe-replication`.
+// DLQMessages is nil when the replication DLQ could not be counted.
+type DomainReplicationRow struct {
+	Cluster     string `header:"Cluster" json:"cluster"`
+	Active      bool   `header:"Active" json:"active"`
+	DLQMessages *int64 `header:"Replication DLQ Messages" json:"replicationDLQMessages"`
+}
+
+// DomainReplicationStatus is the replication status of a domain shown by `admin domain describe-replication`
+type DomainReplicationStatus struct {
+	Domain          string                 `json:"domain"`
+	DomainID        string                 `json:"domainId"`
+	IsGlobalDomain  bool                   `json:"isGlobalDomain"`
+	ActiveCluster   string                 `json:"activeCluster"`
+	FailoverVersion int64                  `json:"failoverVersion"`
+	FailoverInfo    *types.FailoverInfo    `json:"failoverInfo,omitempty"`
+	Clusters        []DomainReplicationRow `json:"clusters"`
+}
+
+// AdminDescribeDomainReplication shows the replication configuration of a domain, its ongoing graceful failover if any,
+// and for each of its clusters the number of replication tasks from that cluster in the DLQ of the current cluster.
+// The DLQ is shared by all domains, there is no per-domain replication lag in the admin API.
+func AdminDescribeDomainReplication(c *cli.Context) error {
+	domain, err := getRequiredOption(c, FlagDomain)
+	if err != nil {
+		return commoncli.Problem("Required flag not found", err)
+	}
+	client, err := getCadenceClient(c)
+	if err != nil {
+		return err
+	}
+	adminClient, err := getDeps(c).ServerAdminClient(c)
+	if err != nil {
+		return err
+	}
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return commoncli.Problem("Error in creating context: ", err)
+	}
 
-	req := &persistence.DeleteWorkflowExecutionRequest{
-		DomainID:   domainID,
-		WorkflowID: wid,
-		RunID:      rid,
-		DomainName: domain,
+	resp, err := client.DescribeDomain(ctx, &types.DescribeDomainRequest{Name: &domain})
+	if err != nil {
+		return commoncli.Problem("Failed to describe domain", err)
+	}
+	status := DomainReplicationStatus{
+		Domain:          resp.GetDomainInfo().GetName(),
+		DomainID:        resp.GetDomainInfo().GetUUID(),
+		IsGlobalDomain:  resp.GetIsGlobalDomain(),
+		ActiveCluster:   resp.ReplicationConfiguration.GetActiveClusterName(),
+		FailoverVersion: resp.GetFailoverVersion(),
+		FailoverInfo:    resp.GetFailoverInfo(),
 	}
 
-	err = exeStore.DeleteWorkflowExecution(ctx, req)
+	dlqMessages := map[string]int64{}
+	dlqCount, err := adminClient.CountDLQMessages(ctx, &types.CountDLQMessagesRequest{})
 	if err != nil {
-		if skipError {
-			fmt.Println("delete mutableState row failed, ", err)
-		} else {
-			return commoncli.Problem("delete mutableState row failed", err)
+		fmt.Fprintf(getDeps(c).Progress(), "%s failed to count replication DLQ messages: %v\n", colorRed("Warning:"), err)
+	} else {
+		for key, count := range dlqCount.History {
+			dlqMessages[key.SourceCluster] += count
 		}
 	}
-	fmt.Println("delete mutableState row successfully")
+	for _, cluster := range resp.ReplicationConfiguration.GetClusters() {
+		row := DomainReplicationRow{
+			Cluster: cluster.GetClusterName(),
+			Active:  cluster.GetClusterName() == status.ActiveCluster,
+		}
+		if dlqCount != nil {
+			row.DLQMessages = common.Int64Ptr(dlqMessages[row.Cluster])
+		}
+		status.Clusters = append(status.Clusters, row)
+	}
 
-	deleteCurrentReq := &persistence.DeleteCurrentWorkflowExecutionRequest{
-		DomainID:   domainID,
-		WorkflowID: wid,
-		RunID:      rid,
+	switch getOutputFormat(c) {
+	case formatJSON, formatYAML:
+		printObject(c, status)
+		return nil
 	}
+	output := getDeps(c).Output()
+	fmt.Fprintf(output, "Domain: %v (%v)\n", status.Domain, status.DomainID)
+	fmt.Fprintf(output, "Global domain: %v\n", status.IsGlobalDomain)
+	fmt.Fprintf(output, "Active cluster: %v\n", status.ActiveCluster)
+	fmt.Fprintf(output, "Failover version: %v\n", status.FailoverVersion)
+	if info := status.FailoverInfo; info != nil {
+		fmt.Fprintf(output, "Graceful failover: to version %v, started %v, expires %v, %v shards completed, %v pending\n",
+			info.GetFailoverVersion(),
+			time.Unix(0, info.GetFailoverStartTimestamp()).UTC().Format(time.RFC3339),
+			time.Unix(0, info.GetFailoverExpireTimestamp()).UTC().Format(time.RFC3339),
+			info.GetCompletedShardCount(),
+			len(info.GetPendingShards()),
+		)
+	}
+	return RenderTable(output, status.Clusters, RenderOptions{Color: true})
+}
+
+// GlobalDomainRow is a global domain listed by `admin domain list-global`
+type GlobalDomainRow struct {
+	Name          string   `header:"Name" json:"name"`
+	DomainID      string   `header:"Domain ID" json:"domainId"`
+	ActiveCluster string   `header:"Active Cluster" json:"activeCluster"`
+	Clusters      []string `header:"Clusters" json:"clusters"`
+}
 
-	err = exeStore.DeleteCurrentWorkflowExecution(ctx, deleteCurrentReq)
+// AdminListGlobalDomains lists the global domains, only the ones active in --cluster when it is set, sorted by name
+func AdminListGlobalDomains(c *cli.Context) error {
+	client, err := getCadenceClient(c)
 	if err != nil {
-		if skipError {
-			fmt.Println("delete current row failed, ", err)
-		} else {
-			return commoncli.Problem("delete current row failed", err)
+		return err
+	}
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return commoncli.Problem("Error in creating context: ", err)
+	}
+
+	activeCluster := c.String(FlagCluster)
+	var rows []GlobalDomainRow
+	var token []byte
+	for more := true; more; more = len(token) > 0 {
+		resp, err := client.ListDomains(ctx, &types.ListDomainsRequest{
+			PageSize:      failoverDomainsPageSize,
+			NextPageToken: token,
+		})
+		if err != nil {
+			return commoncli.Problem("Failed to list domains", err)
+		}
+		for _, domain := range resp.GetDomains() {
+			if !domain.GetIsGlobalDomain() {
+				continue
+			}
+			row := GlobalDomainRow{
+				Name:          domain.GetDomainInfo().GetName(),
+				DomainID:      domain.GetDomainInfo().GetUUID(),
+				ActiveCluster: domain.ReplicationConfiguration.GetActiveClusterName(),
+			}
+			if activeCluster != "" && row.ActiveCluster != activeCluster {
+				continue
+			}
+			for _, cluster := range domain.ReplicationConfiguration.GetClusters() {
+				row.Clusters = append(row.Clusters, cluster.GetClusterName())
+			}
+			rows = append(rows, row)
 		}
+		token = resp.GetNextPageToken()
 	}
-	fmt.Println("delete current row successfully")
-	return nil
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+	return Render(c, rows, RenderOptions{DefaultTemplate: templateTable, Color: true})
 }
 
 // AdminGetDomainIDOrName map domain
 func AdminGetDomainIDOrName(c *cli.Context) error {
 	domainID := c.String(FlagDomainID)
 	domainName := c.String(FlagDomain)
+	inputFile := c.String(FlagInputFile)
 
+	if len(inputFile) != 0 {
+		if len(domainID) != 0 || len(domainName) != 0 {
+			return commoncli.Problem(fmt.Sprintf("--%s cannot be used together with domainName or domainID", FlagInputFile), nil)
+		}
+		return adminResolveDomains(c, inputFile)
+	}
 	if (len(domainID) == 0 && len(domainName) == 0) || (len(domainID) != 0 && len(domainName) != 0) {
 		return commoncli.Problem("Need either domainName or domainID", nil)
 	}
@@ -398,19 +1621,113 @@ func AdminGetDomainIDOrName(c *cli.Context) error {
 		return commoncli.Problem("Error in creating context: ", err)
 	}
 
-	output := getDeps(c).Output()
+	var res *persistence.GetDomainResponse
 	if len(domainID) > 0 {
-		res, err := domainManager.GetDomain(ctx, &persistence.GetDomainRequest{ID: domainID})
-		if err != nil {
-			return commoncli.Problem("GetDomain error", err)
-		}
-		fmt.Fprintf(output, "domainName for domainID %v is %v\n", domainID, res.Info.Name)
+		res, err = domainManager.GetDomain(ctx, &persistence.GetDomainRequest{ID: domainID})
 	} else {
-		res, err := domainManager.GetDomain(ctx, &persistence.GetDomainRequest{Name: domainName})
-		if err != nil {
-			return commoncli.Problem("GetDomain error", err)
+		res, err = domainManager.GetDomain(ctx, &persistence.GetDomainRequest{Name: domainName})
+	}
+	if err != nil {
+		return commoncli.Problem("GetDomain error", err)
+	}
+
+	switch getOutputFormat(c) {
+	case formatJSON, formatYAML:
+		printObject(c, domainIDAndName{ID: res.Info.ID, Name: res.Info.Name})
+	default:
+		output := getDeps(c).Output()
+		if len(domainID) > 0 {
+			fmt.Fprintf(output, "domainName for domainID %v is %v\n", domainID, res.Info.Name)
+		} else {
+			fmt.Fprintf(output, "domainID for domainName %v is %v\n", domainName, res.Info.ID)
+		}
+	}
+	return nil
+}
+
+type domainIDAndName struct {
+	Input string `json:"input,omitempty"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+// adminResolveDomains resolves every domain name or domainID in inputFile, one per line.
+// Entries that parse as a uuid are treated as domainIDs, everything else as domain names.
+func adminResolveDomains(c *cli.Context, inputFile string) error {
+	file, err := os.Open(inputFile)
+	if err != nil {
+		return commoncli.Problem("Failed to open input file", err)
+	}
+	defer file.Close()
+
+	var entries []domainIDAndName
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			entries = append(entries, domainIDAndName{Input: line})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return commoncli.Problem("Failed to read input file", err)
+	}
+
+	domainManager, err := getDeps(c).initializeDomainManager(c)
+	if err != nil {
+		return commoncli.Problem("Error in initializing domain manager to resolve domains: ", err)
+	}
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return commoncli.Problem("Error in creating context: ", err)
+	}
+
+	concurrency := c.Int(FlagConcurrency)
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	wg := &sync.WaitGroup{}
+	for i := range entries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(entry *domainIDAndName) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			request := &persistence.GetDomainRequest{Name: entry.Input}
+			if uuid.Parse(entry.Input) != nil {
+				request = &persistence.GetDomainRequest{ID: entry.Input}
+			}
+			res, err := domainManager.GetDomain(ctx, request)
+			if err != nil {
+				entry.Error = err.Error()
+				return
+			}
+			entry.ID = res.Info.ID
+			entry.Name = res.Info.Name
+		}(&entries[i])
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, entry := range entries {
+		if entry.Error != "" {
+			failed++
+		}
+	}
+	switch getOutputFormat(c) {
+	case formatJSON, formatYAML:
+		printObject(c, entries)
+	default:
+		output := getDeps(c).Output()
+		for _, entry := range entries {
+			fmt.Fprintf(output, "%v\t%v\t%v\t%v\n", entry.Input, entry.ID, entry.Name, entry.Error)
 		}
-		fmt.Fprintf(output, "domainID for domainName %v is %v\n", domainName, res.Info.ID)
+	}
+	if failed > 0 {
+		return commoncli.Problem(fmt.Sprintf("Failed to resolve %d of %d domains", failed, len(entries)), nil)
 	}
 	return nil
 }
@@ -429,6 +1746,18 @@ func AdminGetShardID(c *cli.Context) error {
 	shardID := common.WorkflowIDToHistoryShard(wid, numberOfShards)
 
 	fmt.Fprintf(getDeps(c).Output(), "ShardID for workflowID: %v is %v\n", wid, shardID)
+	if !c.Bool(FlagWithHost) {
+		return nil
+	}
+	adminClient, err := getDeps(c).ServerAdminClient(c)
+	if err != nil {
+		return err
+	}
+	owner, err := describeShardOwner(c, adminClient, shardID)
+	if err != nil {
+		return commoncli.Problem(fmt.Sprintf("Failed to find the history host of shard %v", shardID), err)
+	}
+	fmt.Fprintf(getDeps(c).Output(), "Shard %v is owned by history host %v\n", shardID, owner)
 	return nil
 }
 
@@ -465,6 +1794,28 @@ func AdminRemoveTask(c *cli.Context) error {
 	if err != nil {
 		return commoncli.Problem("Error in creating context: ", err)
 	}
+
+	if common.TaskType(typeID) == common.TaskTypeReplication {
+		if err := checkReplicationTaskDLQStatus(ctx, c, adminClient, shardID, taskID); err != nil {
+			return err
+		}
+	}
+
+	if c.Bool(FlagShowTask) {
+		task, err := lookupTask(c, shardID, common.TaskType(typeID), taskID, visibilityTimestamp)
+		switch {
+		case err != nil:
+			fmt.Fprintf(getDeps(c).Progress(), "%s could not look up task %d: %v\n", colorRed("Warning:"), taskID, err)
+		case task == nil:
+			fmt.Fprintf(getDeps(c).Progress(), "%s task %d was not found in shard %d\n", colorRed("Warning:"), taskID, shardID)
+		default:
+			printTaskDetails(getDeps(c).Output(), taskID, task)
+		}
+	}
+	if !c.Bool(FlagYes) && !confirm(c, fmt.Sprintf("Remove task %d of shard %d?", taskID, shardID)) {
+		return commoncli.Problem("Task removal cancelled", nil)
+	}
+
 	req := &types.RemoveTaskRequest{
 		ShardID:             int32(shardID),
 		Type:                common.Int32Ptr(int32(typeID)),
@@ -480,41 +1831,417 @@ func AdminRemoveTask(c *cli.Context) error {
 	return nil
 }
 
+// taskDetails is what AdminRemoveTask shows of the task it is about to remove
+type taskDetails struct {
+	TaskType            int
+	DomainID            string
+	WorkflowID          string
+	RunID               string
+	VisibilityTimestamp time.Time
+}
+
+// lookupTask reads a history task from the database of the shard. It returns nil if the task is not found.
+func lookupTask(c *cli.Context, shardID int, taskType common.TaskType, taskID, visibilityTimestamp int64) (*taskDetails, error) {
+	executionManager, err := getDeps(c).initializeExecutionManager(c, shardID)
+	if err != nil {
+		return nil, err
+	}
+	defer executionManager.Close()
+
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	switch taskType {
+	case common.TaskTypeTransfer:
+		resp, err := executionManager.GetTransferTasks(ctx, &persistence.GetTransferTasksRequest{
+			ReadLevel:    taskID - 1,
+			MaxReadLevel: taskID,
+			BatchSize:    1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range resp.Tasks {
+			if t.TaskID == taskID {
+				return &taskDetails{t.TaskType, t.DomainID, t.WorkflowID, t.RunID, t.VisibilityTimestamp}, nil
+			}
+		}
+	case common.TaskTypeTimer:
+		visibilityTime := time.Unix(0, visibilityTimestamp)
+		resp, err := executionManager.GetTimerIndexTasks(ctx, &persistence.GetTimerIndexTasksRequest{
+			MinTimestamp: visibilityTime,
+			MaxTimestamp: visibilityTime.Add(time.Nanosecond),
+			BatchSize:    defaultPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range resp.Timers {
+			if t.TaskID == taskID {
+				return &taskDetails{t.TaskType, t.DomainID, t.WorkflowID, t.RunID, t.VisibilityTimestamp}, nil
+			}
+		}
+	case common.TaskTypeReplication:
+		resp, err := executionManager.GetReplicationTasks(ctx, &persistence.GetReplicationTasksRequest{
+			ReadLevel:    taskID - 1,
+			MaxReadLevel: taskID,
+			BatchSize:    1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range resp.Tasks {
+			if t.TaskID == taskID {
+				return &taskDetails{TaskType: t.TaskType, DomainID: t.DomainID, WorkflowID: t.WorkflowID, RunID: t.RunID}, nil
+			}
+		}
+	default:
+		return nil, fmt.Errorf("looking up tasks of type %v is not supported", taskType)
+	}
+	return nil, nil
+}
+
+func printTaskDetails(w io.Writer, taskID int64, task *taskDetails) {
+	fmt.Fprintf(w, "Task ID: %v\n", taskID)
+	fmt.Fprintf(w, "Task type: %v\n", task.TaskType)
+	fmt.Fprintf(w, "Domain ID: %v\n", task.DomainID)
+	fmt.Fprintf(w, "Workflow ID: %v\n", task.WorkflowID)
+	fmt.Fprintf(w, "Run ID: %v\n", task.RunID)
+	if !task.VisibilityTimestamp.IsZero() {
+		fmt.Fprintf(w, "Visibility timestamp: %v (%d)\n", task.VisibilityTimestamp, task.VisibilityTimestamp.UnixNano())
+	}
+}
+
+// checkReplicationTaskDLQStatus reports whether a replication task is also present in the DLQ of the given cluster.
+// With --dlq_aware the removal is refused when the task is found, so that it is not orphaned in the DLQ.
+func checkReplicationTaskDLQStatus(ctx context.Context, c *cli.Context, adminClient admin.Client, shardID int, taskID int64) error {
+	progress := getDeps(c).Progress()
+	dlqAware := c.Bool(FlagDLQAware)
+	sourceCluster := c.String(FlagCluster)
+	if sourceCluster == "" {
+		if dlqAware {
+			return commoncli.Problem(fmt.Sprintf("--%s is required to check the DLQ status of a replication task", FlagCluster), nil)
+		}
+		fmt.Fprintf(progress, "Skipping DLQ status check for replication task %v: --%s is not provided\n", taskID, FlagCluster)
+		return nil
+	}
+
+	inDLQ, err := isReplicationTaskInDLQ(ctx, adminClient, shardID, sourceCluster, taskID)
+	if err != nil {
+		if dlqAware {
+			return commoncli.Problem("Failed to check DLQ status of replication task", err)
+		}
+		fmt.Fprintf(progress, "Unable to check DLQ status of replication task %v: %v\n", taskID, err)
+		return nil
+	}
+	if !inDLQ {
+		fmt.Fprintf(progress, "Replication task %v is not present in the DLQ for cluster %v\n", taskID, sourceCluster)
+		return nil
+	}
+
+	fmt.Fprintf(progress, "%s replication task %v is also present in the DLQ for cluster %v on shard %v\n", colorRed("Warning:"), taskID, sourceCluster, shardID)
+	if dlqAware {
+		return commoncli.Problem(
+			fmt.Sprintf("refusing to remove replication task %v which is present in the DLQ, merge or purge it with `cadence admin dlq` first", taskID),
+			nil,
+		)
+	}
+	return nil
+}
+
+func isReplicationTaskInDLQ(ctx context.Context, adminClient admin.Client, shardID int, sourceCluster string, taskID int64) (bool, error) {
+	var pageToken []byte
+	for {
+		resp, err := adminClient.ReadDLQMessages(ctx, &types.ReadDLQMessagesRequest{
+			Type:                  types.DLQTypeReplication.Ptr(),
+			SourceCluster:         sourceCluster,
+			ShardID:               int32(shardID),
+			InclusiveEndMessageID: common.Int64Ptr(taskID),
+			MaximumPageSize:       defaultPageSize,
+			NextPageToken:         pageToken,
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, info := range resp.ReplicationTasksInfo {
+			if info.GetTaskID() == taskID {
+				return true, nil
+			}
+		}
+		if len(resp.NextPageToken) == 0 {
+			return false, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
 // AdminDescribeShard describes shard by shard id
 func AdminDescribeShard(c *cli.Context) error {
-	sid, err := getRequiredIntOption(c, FlagShardID)
+	lower, upper, isRange, err := getShardRange(c)
 	if err != nil {
-		return commoncli.Problem("Required flag not found", err)
+		return commoncli.Problem("Invalid shard range", err)
+	}
+	if !isRange {
+		lower, err = getRequiredIntOption(c, FlagShardID)
+		if err != nil {
+			return commoncli.Problem("Required flag not found", err)
+		}
+		upper = lower
+	}
+	if c.Bool(FlagHistory) {
+		if isRange {
+			return commoncli.Problem(fmt.Sprintf("--%s cannot be used with a shard range", FlagHistory), nil)
+		}
+		if interval := c.Duration(FlagInterval); interval < shardProgressMinInterval {
+			return commoncli.Problem(fmt.Sprintf("--%s %v is shorter than %v, the default %v: the persisted ack levels may not move between the samples",
+				FlagInterval, interval, shardProgressMinInterval, dynamicconfig.ShardUpdateMinInterval.String()), nil)
+		}
+	}
+	shardManager, err := getDeps(c).initializeShardManager(c)
+	if err != nil {
+		return commoncli.Problem("Error in describe shard: ", err)
+	}
+	if !isRange {
+		resp, err := getShard(c, shardManager, lower)
+		if err != nil {
+			return commoncli.Problem("Failed to describe shard.", err)
+		}
+		if c.Bool(FlagHistory) {
+			return printShardProgress(c, shardManager, resp)
+		}
+		return printShard(c, resp)
+	}
+
+	output := getDeps(c).Output()
+	failed := 0
+	for sid := lower; sid <= upper; sid++ {
+		resp, err := getShard(c, shardManager, sid)
+		if err != nil {
+			failed++
+			fmt.Fprintf(output, "Failed to describe shard %v: %v\n", sid, err)
+			continue
+		}
+		if err := printShard(c, resp); err != nil {
+			return err
+		}
+	}
+	return shardRangeSummary(output, "Described", upper-lower+1, failed)
+}
+
+// ShardAckLevelRow is a row of the per-cluster ack levels of a shard
+type ShardAckLevelRow struct {
+	Cluster                string    `header:"Cluster"`
+	TransferAckLevel       int64     `header:"Transfer Ack Level"`
+	TimerAckLevel          time.Time `header:"Timer Ack Level"`
+	ReplicationLevel       int64     `header:"Replication Level"`
+	ReplicationDLQAckLevel int64     `header:"Replication DLQ Ack Level"`
+}
+
+func printShard(c *cli.Context, resp *persistence.GetShardResponse) error {
+	printObject(c, resp)
+	if !c.Bool(FlagDecode) || resp.ShardInfo == nil {
+		return nil
+	}
+	if err := RenderTable(getDeps(c).Output(), buildShardAckLevels(resp.ShardInfo), RenderOptions{Color: true, Border: true, PrintDateTime: true}); err != nil {
+		return commoncli.Problem("Failed to render shard ack levels", err)
+	}
+	return nil
+}
+
+// shardProgressMinInterval is the shortest --interval of AdminDescribeShard with --history. The shard info is persisted
+// at most every history.shardUpdateMinInterval, so the ack levels read from the database don't move in a shorter time.
+var shardProgressMinInterval = dynamicconfig.ShardUpdateMinInterval.DefaultDuration()
+
+// printShardProgress describes the shard again after --interval and prints how far its transfer and timer
+// ack levels moved, so that a stuck shard can be told apart from a slow one.
+func printShardProgress(c *cli.Context, shardManager persistence.ShardManager, first *persistence.GetShardResponse) error {
+	interval := c.Duration(FlagInterval)
+	select {
+	case <-time.After(interval):
+	case <-c.Context.Done():
+		return commoncli.Problem("Interrupted before the second sample", c.Context.Err())
 	}
 	ctx, cancel, err := newContext(c)
 	defer cancel()
 	if err != nil {
 		return commoncli.Problem("Error in creating context: ", err)
 	}
+	second, err := shardManager.GetShard(ctx, &persistence.GetShardRequest{ShardID: first.ShardInfo.ShardID})
+	if err != nil {
+		return commoncli.Problem("Failed to describe shard.", err)
+	}
+	before, after := first.ShardInfo, second.ShardInfo
+
+	output := getDeps(c).Output()
+	fmt.Fprintf(output, "Shard %v owned by %v\n", after.ShardID, after.Owner)
+	if before.Owner != after.Owner || before.RangeID != after.RangeID {
+		fmt.Fprintf(output, "%s shard moved from %v (rangeID %v) to %v (rangeID %v) while sampling\n",
+			colorRed("Warning:"), before.Owner, before.RangeID, after.Owner, after.RangeID)
+	}
+	transferDelta := after.TransferAckLevel - before.TransferAckLevel
+	fmt.Fprintf(output, "Transfer ack level: %v -> %v (+%v, %.2f task IDs/s)\n",
+		before.TransferAckLevel, after.TransferAckLevel, transferDelta, float64(transferDelta)/interval.Seconds())
+	timerDelta := after.TimerAckLevel.Sub(before.TimerAckLevel)
+	fmt.Fprintf(output, "Timer ack level: %v -> %v (+%v, %.2fx real time)\n",
+		before.TimerAckLevel.Format(time.RFC3339), after.TimerAckLevel.Format(time.RFC3339), timerDelta, timerDelta.Seconds()/interval.Seconds())
+	if transferDelta == 0 && timerDelta == 0 {
+		fmt.Fprintf(output, "%s neither ack level moved in %v, the shard may be stuck\n", colorRed("Warning:"), interval)
+	}
+	return nil
+}
+
+// buildShardAckLevels returns a row for every cluster that appears in any of the ack level maps of the shard
+func buildShardAckLevels(info *persistence.ShardInfo) []ShardAckLevelRow {
+	clusters := make(map[string]struct{})
+	for cluster := range info.ClusterTransferAckLevel {
+		clusters[cluster] = struct{}{}
+	}
+	for cluster := range info.ClusterTimerAckLevel {
+		clusters[cluster] = struct{}{}
+	}
+	for cluster := range info.ClusterReplicationLevel {
+		clusters[cluster] = struct{}{}
+	}
+	for cluster := range info.ReplicationDLQAckLevel {
+		clusters[cluster] = struct{}{}
+	}
+	rows := make([]ShardAckLevelRow, 0, len(clusters))
+	for cluster := range clusters {
+		rows = append(rows, ShardAckLevelRow{
+			Cluster:                cluster,
+			TransferAckLevel:       info.ClusterTransferAckLevel[cluster],
+			TimerAckLevel:          info.ClusterTimerAckLevel[cluster],
+			ReplicationLevel:       info.ClusterReplicationLevel[cluster],
+			ReplicationDLQAckLevel: info.ReplicationDLQAckLevel[cluster],
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Cluster < rows[j].Cluster })
+	return rows
+}
+
+// AdminSetShardRangeID set shard rangeID by shard id
+func AdminSetShardRangeID(c *cli.Context) error {
+	lower, upper, isRange, err := getShardRange(c)
+	if err != nil {
+		return commoncli.Problem("Invalid shard range", err)
+	}
+	if !isRange {
+		lower, err = getRequiredIntOption(c, FlagShardID)
+		if err != nil {
+			return commoncli.Problem("Required flag not found", err)
+		}
+		upper = lower
+	}
+	var rid, bump int64
+	if c.IsSet(FlagBump) {
+		if c.IsSet(FlagRangeID) {
+			return commoncli.Problem(fmt.Sprintf("--%s and --%s cannot be used together", FlagRangeID, FlagBump), nil)
+		}
+		if bump = c.Int64(FlagBump); bump <= 0 {
+			return commoncli.Problem(fmt.Sprintf("--%s must be positive", FlagBump), nil)
+		}
+	} else {
+		rid, err = getRequiredInt64Option(c, FlagRangeID)
+		if err != nil {
+			return commoncli.Problem("Required flag not found", err)
+		}
+	}
+	if isRange {
+		if maxShards := c.Int(FlagMaxShards); upper-lower+1 > maxShards {
+			return commoncli.Problem(fmt.Sprintf("Shard range contains %v shards, more than --%s %v", upper-lower+1, FlagMaxShards, maxShards), nil)
+		}
+		if !c.Bool(FlagYes) {
+			return commoncli.Problem(fmt.Sprintf("Resetting the rangeID of shards %v to %v requires --%s", lower, upper, FlagYes), nil)
+		}
+	}
 	shardManager, err := getDeps(c).initializeShardManager(c)
 	if err != nil {
-		return commoncli.Problem("Error in describe shard: ", err)
+		return commoncli.Problem("Error in Admin SetShardRangeID: ", err)
+	}
+	if !isRange {
+		previousRangeID, newRangeID, err := setShardRangeID(c, shardManager, lower, rid, bump)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(getDeps(c).Output(), "Successfully updated rangeID from %v to %v for shard %v.\n", previousRangeID, newRangeID, lower)
+		return nil
+	}
+
+	output := getDeps(c).Output()
+	failed := 0
+	for sid := lower; sid <= upper; sid++ {
+		previousRangeID, newRangeID, err := setShardRangeID(c, shardManager, sid, rid, bump)
+		if err != nil {
+			failed++
+			fmt.Fprintf(output, "Failed to update rangeID for shard %v: %v\n", sid, err)
+			continue
+		}
+		fmt.Fprintf(output, "Successfully updated rangeID from %v to %v for shard %v.\n", previousRangeID, newRangeID, sid)
+	}
+	return shardRangeSummary(output, "Updated", upper-lower+1, failed)
+}
+
+// setShardRangeID sets the rangeID of a shard to rid, or to its current rangeID plus bump when bump is positive.
+// It returns the previous and the new rangeID. Every shard gets a context of its own, so that a long shard range
+// is not cut short by a single --context_timeout.
+func setShardRangeID(c *cli.Context, shardManager persistence.ShardManager, sid int, rid int64, bump int64) (int64, int64, error) {
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return 0, 0, commoncli.Problem("Error in creating context: ", err)
+	}
+	getShardResp, err := shardManager.GetShard(ctx, &persistence.GetShardRequest{ShardID: sid})
+	if err != nil {
+		return 0, 0, commoncli.Problem("Failed to get shardInfo.", err)
+	}
+
+	previousRangeID := getShardResp.ShardInfo.RangeID
+	if bump > 0 {
+		rid = previousRangeID + bump
 	}
-	getShardReq := &persistence.GetShardRequest{ShardID: sid}
-	resp, err := shardManager.GetShard(ctx, getShardReq)
+	updatedShardInfo := getShardResp.ShardInfo
+	updatedShardInfo.RangeID = rid
+	updatedShardInfo.StolenSinceRenew++
+	updatedShardInfo.Owner = ""
+	updatedShardInfo.UpdatedAt = time.Now()
+
+	err = shardManager.UpdateShard(ctx, &persistence.UpdateShardRequest{
+		PreviousRangeID: previousRangeID,
+		ShardInfo:       updatedShardInfo,
+	})
 	if err != nil {
-		return commoncli.Problem("Failed to describe shard.", err)
+		return 0, 0, commoncli.Problem("Failed to reset shard rangeID.", err)
 	}
-
-	prettyPrintJSONObject(getDeps(c).Output(), resp)
-	return nil
+	return previousRangeID, rid, nil
 }
 
-// AdminSetShardRangeID set shard rangeID by shard id
-func AdminSetShardRangeID(c *cli.Context) error {
+// AdminResetShardAckLevel sets the transfer or timer ack level of a cluster in a shard, to reprocess or skip its tasks.
+// The processing queue states of the cluster are replaced by a single queue at the new ack level.
+// The rangeID of the shard is bumped so that its owner reloads it instead of overwriting the ack level.
+func AdminResetShardAckLevel(c *cli.Context) error {
 	sid, err := getRequiredIntOption(c, FlagShardID)
 	if err != nil {
 		return commoncli.Problem("Required flag not found", err)
 	}
-	rid, err := getRequiredInt64Option(c, FlagRangeID)
+	cluster, err := getRequiredOption(c, FlagCluster)
 	if err != nil {
 		return commoncli.Problem("Required flag not found", err)
 	}
+	if c.IsSet(FlagTransferAckLevel) == c.IsSet(FlagTimerAckLevel) {
+		return commoncli.Problem(fmt.Sprintf("exactly one of --%s and --%s is required", FlagTransferAckLevel, FlagTimerAckLevel), nil)
+	}
+	var timerAckLevel time.Time
+	if c.IsSet(FlagTimerAckLevel) {
+		nanos, err := parseTime(c.String(FlagTimerAckLevel), 0)
+		if err != nil {
+			return commoncli.Problem(fmt.Sprintf("Invalid --%s", FlagTimerAckLevel), err)
+		}
+		timerAckLevel = time.Unix(0, nanos).UTC()
+	}
+
 	ctx, cancel, err := newContext(c)
 	defer cancel()
 	if err != nil {
@@ -522,29 +2249,108 @@ func AdminSetShardRangeID(c *cli.Context) error {
 	}
 	shardManager, err := getDeps(c).initializeShardManager(c)
 	if err != nil {
-		return commoncli.Problem("Error in Admin SetShardRangeID: ", err)
+		return commoncli.Problem("Error in initializing shard manager", err)
 	}
-	getShardResp, err := shardManager.GetShard(ctx, &persistence.GetShardRequest{ShardID: sid})
+	resp, err := shardManager.GetShard(ctx, &persistence.GetShardRequest{ShardID: sid})
 	if err != nil {
 		return commoncli.Problem("Failed to get shardInfo.", err)
 	}
+	shardInfo := resp.ShardInfo
 
-	previousRangeID := getShardResp.ShardInfo.RangeID
-	updatedShardInfo := getShardResp.ShardInfo
-	updatedShardInfo.RangeID = rid
-	updatedShardInfo.StolenSinceRenew++
-	updatedShardInfo.Owner = ""
-	updatedShardInfo.UpdatedAt = time.Now()
+	queue := "transfer"
+	var before, after string
+	if c.IsSet(FlagTransferAckLevel) {
+		ackLevel := c.Int64(FlagTransferAckLevel)
+		before, after = strconv.FormatInt(shardInfo.ClusterTransferAckLevel[cluster], 10), strconv.FormatInt(ackLevel, 10)
+		if shardInfo.ClusterTransferAckLevel == nil {
+			shardInfo.ClusterTransferAckLevel = map[string]int64{}
+		}
+		shardInfo.ClusterTransferAckLevel[cluster] = ackLevel
+		shardInfo.TransferProcessingQueueStates = resetProcessingQueueStates(shardInfo.TransferProcessingQueueStates, cluster, ackLevel)
+		if c.Bool(FlagCurrentCluster) {
+			shardInfo.TransferAckLevel = ackLevel
+		}
+	} else {
+		queue = "timer"
+		before, after = shardInfo.ClusterTimerAckLevel[cluster].UTC().Format(time.RFC3339Nano), timerAckLevel.Format(time.RFC3339Nano)
+		if shardInfo.ClusterTimerAckLevel == nil {
+			shardInfo.ClusterTimerAckLevel = map[string]time.Time{}
+		}
+		shardInfo.ClusterTimerAckLevel[cluster] = timerAckLevel
+		shardInfo.TimerProcessingQueueStates = resetProcessingQueueStates(shardInfo.TimerProcessingQueueStates, cluster, timerAckLevel.UnixNano())
+		if c.Bool(FlagCurrentCluster) {
+			shardInfo.TimerAckLevel = timerAckLevel
+		}
+	}
+	output := getDeps(c).Output()
+	fmt.Fprintf(output, "Shard %v %v ack level of cluster %v: %v\n", sid, queue, cluster, before)
+	if !c.Bool(FlagYes) && !confirm(c, fmt.Sprintf("Set the %v ack level of cluster %v in shard %v to %v?", queue, cluster, sid, after)) {
+		return commoncli.Problem("Ack level reset cancelled", nil)
+	}
 
+	previousRangeID := shardInfo.RangeID
+	shardInfo.RangeID++
+	shardInfo.StolenSinceRenew++
+	shardInfo.Owner = ""
+	shardInfo.UpdatedAt = time.Now()
 	err = shardManager.UpdateShard(ctx, &persistence.UpdateShardRequest{
 		PreviousRangeID: previousRangeID,
-		ShardInfo:       updatedShardInfo,
+		ShardInfo:       shardInfo,
 	})
 	if err != nil {
-		return commoncli.Problem("Failed to reset shard rangeID.", err)
+		return commoncli.Problem("Failed to reset shard ack level.", err)
+	}
+	fmt.Fprintf(output, "Shard %v %v ack level of cluster %v: %v, rangeID updated from %v to %v\n", sid, queue, cluster, after, previousRangeID, shardInfo.RangeID)
+	return nil
+}
+
+// resetProcessingQueueStates replaces the processing queue states of cluster by a single queue starting at ackLevel,
+// the state a shard starts from when it only has an ack level. Persisted queue states take precedence over
+// the ack levels when a shard is loaded, so the ack level alone would be ignored.
+func resetProcessingQueueStates(states *types.ProcessingQueueStates, cluster string, ackLevel int64) *types.ProcessingQueueStates {
+	if states == nil {
+		states = &types.ProcessingQueueStates{}
+	}
+	if states.StatesByCluster == nil {
+		states.StatesByCluster = map[string][]*types.ProcessingQueueState{}
+	}
+	states.StatesByCluster[cluster] = []*types.ProcessingQueueState{
+		{
+			Level:    common.Int32Ptr(0),
+			AckLevel: common.Int64Ptr(ackLevel),
+			MaxLevel: common.Int64Ptr(math.MaxInt64),
+			DomainFilter: &types.DomainFilter{
+				ReverseMatch: true,
+			},
+		},
 	}
+	return states
+}
+
+// getShardRange returns the inclusive range given by --lower_shard_bound and --upper_shard_bound,
+// or isRange false if neither is set.
+func getShardRange(c *cli.Context) (lower int, upper int, isRange bool, err error) {
+	if !c.IsSet(FlagLowerShardBound) && !c.IsSet(FlagUpperShardBound) {
+		return 0, 0, false, nil
+	}
+	if !c.IsSet(FlagLowerShardBound) || !c.IsSet(FlagUpperShardBound) {
+		return 0, 0, false, fmt.Errorf("both --%s and --%s are required", FlagLowerShardBound, FlagUpperShardBound)
+	}
+	if c.IsSet(FlagShardID) {
+		return 0, 0, false, fmt.Errorf("--%s cannot be used together with a shard range", FlagShardID)
+	}
+	lower, upper = c.Int(FlagLowerShardBound), c.Int(FlagUpperShardBound)
+	if lower < 0 || lower > upper {
+		return 0, 0, false, fmt.Errorf("invalid shard range %v to %v", lower, upper)
+	}
+	return lower, upper, true, nil
+}
 
-	fmt.Fprintf(getDeps(c).Output(), "Successfully updated rangeID from %v to %v for shard %v.\n", previousRangeID, rid, sid)
+func shardRangeSummary(output io.Writer, action string, total int, failed int) error {
+	fmt.Fprintf(output, "%v %v of %v shards, %v failed.\n", action, total-failed, total, failed)
+	if failed > 0 {
+		return commoncli.Problem(fmt.Sprintf("%v of %v shards failed", failed, total), nil)
+	}
 	return nil
 }
 
@@ -573,11 +2379,191 @@ func AdminCloseShard(c *cli.Context) error {
 	return nil
 }
 
+// shardTransferCheckInterval is how often AdminTransferShard checks the owner of the shard
+var shardTransferCheckInterval = time.Second
+
+// AdminTransferShard moves a shard to a target history host. There is no admin API to place a shard, so the persisted
+// owner of the shard is set to the target host and the shard is closed, then the command waits until
+// DescribeHistoryHost reports the target host as the owner.
+// The history host reacquiring the shard is chosen by the membership ring, so the transfer only converges when the
+// ring assigns the shard to the target host, e.g. after the current owner was drained from the ring.
+func AdminTransferShard(c *cli.Context) error {
+	sid, err := getRequiredIntOption(c, FlagShardID)
+	if err != nil {
+		return commoncli.Problem("Required option not found", err)
+	}
+	target, err := getRequiredOption(c, FlagTargetHost)
+	if err != nil {
+		return commoncli.Problem("Required option not found", err)
+	}
+	adminClient, err := getDeps(c).ServerAdminClient(c)
+	if err != nil {
+		return err
+	}
+	output := getDeps(c).Output()
+
+	owner, err := describeShardOwner(c, adminClient, sid)
+	if err != nil {
+		return commoncli.Problem("Failed to describe the shard owner", err)
+	}
+	if owner == target {
+		fmt.Fprintf(output, "Shard %d is already owned by %s\n", sid, target)
+		return nil
+	}
+
+	shardManager, err := getDeps(c).initializeShardManager(c)
+	if err != nil {
+		return commoncli.Problem("Error in initializing shard manager", err)
+	}
+	if err := setShardOwner(c, shardManager, sid, target); err != nil {
+		return err
+	}
+	ctx, cancel, err := newContext(c)
+	if err != nil {
+		cancel()
+		return commoncli.Problem("Error in creating context", err)
+	}
+	err = adminClient.CloseShard(ctx, &types.CloseShardRequest{ShardID: int32(sid)})
+	cancel()
+	if err != nil {
+		return commoncli.Problem("Close shard task has failed", err)
+	}
+
+	previousOwner := owner
+	waitTimeout := c.Duration(FlagWaitTimeout)
+	deadline := time.After(waitTimeout)
+	for {
+		select {
+		case <-time.After(shardTransferCheckInterval):
+		case <-deadline:
+			return commoncli.Problem(fmt.Sprintf(
+				"Shard %d is owned by %s instead of %s after %v. Shard owners follow the history membership ring, "+
+					"make sure %s is the host the ring assigns the shard to (e.g. drain %s first)",
+				sid, owner, target, waitTimeout, target, previousOwner), nil)
+		case <-c.Context.Done():
+			return commoncli.Problem(fmt.Sprintf("Interrupted while waiting for %s to own shard %d", target, sid), c.Context.Err())
+		}
+		currentOwner, err := describeShardOwner(c, adminClient, sid)
+		if err != nil {
+			// the shard is unavailable while it moves
+			continue
+		}
+		if owner = currentOwner; owner == target {
+			fmt.Fprintf(output, "Shard %d moved from %s to %s\n", sid, previousOwner, target)
+			return nil
+		}
+	}
+}
+
+// setShardOwner sets the persisted owner of a shard, keeping its rangeID so that the current owner is not fenced out
+// before the shard is closed
+func setShardOwner(c *cli.Context, shardManager persistence.ShardManager, sid int, owner string) error {
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return commoncli.Problem("Error in creating context", err)
+	}
+	resp, err := shardManager.GetShard(ctx, &persistence.GetShardRequest{ShardID: sid})
+	if err != nil {
+		return commoncli.Problem("Failed to get shardInfo.", err)
+	}
+	info := resp.ShardInfo
+	info.Owner = owner
+	info.UpdatedAt = time.Now()
+	err = shardManager.UpdateShard(ctx, &persistence.UpdateShardRequest{
+		PreviousRangeID: info.RangeID,
+		ShardInfo:       info,
+	})
+	if err != nil {
+		return commoncli.Problem("Failed to update the shard owner.", err)
+	}
+	return nil
+}
+
+// describeShardOwner returns the address of the history host owning shardID
+func describeShardOwner(c *cli.Context, adminClient admin.Client, shardID int) (string, error) {
+	ctx, cancel, err := newContext(c)
+	if err != nil {
+		return "", err
+	}
+	defer cancel()
+	resp, err := adminClient.DescribeHistoryHost(ctx, &types.DescribeHistoryHostRequest{
+		ShardIDForHost: common.Int32Ptr(int32(shardID)),
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Address, nil
+}
+
 type ShardRow struct {
 	ShardID  int32  `header:"ShardID"`
 	Identity string `header:"Identity"`
 }
 
+// ShardInfoRow is a row of the persisted shard info table
+type ShardInfoRow struct {
+	ShardID          int       `header:"ShardID"`
+	RangeID          int64     `header:"RangeID"`
+	StolenSinceRenew int       `header:"Stolen Since Renew"`
+	UpdatedAt        time.Time `header:"Updated At"`
+	Owner            string    `header:"Owner"`
+}
+
+// AdminListShards lists the persisted shard info of every shard
+func AdminListShards(c *cli.Context) error {
+	numberOfShards, err := getRequiredIntOption(c, FlagNumberOfShards)
+	if err != nil {
+		return commoncli.Problem("Required flag not found", err)
+	}
+	filterOwner := c.String(FlagFilterOwner)
+	shardManager, err := getDeps(c).initializeShardManager(c)
+	if err != nil {
+		return commoncli.Problem("Error in list shards: ", err)
+	}
+
+	var table []ShardInfoRow
+	failed := 0
+	for sid := 0; sid < numberOfShards; sid++ {
+		resp, err := getShard(c, shardManager, sid)
+		if err != nil {
+			// keep listing the other shards, a single unavailable shard should not hide the whole table
+			failed++
+			fmt.Fprintf(getDeps(c).Progress(), "%s failed to describe shard %v: %v\n", colorRed("Warning:"), sid, err)
+			continue
+		}
+		info := resp.ShardInfo
+		if filterOwner != "" && info.Owner != filterOwner {
+			continue
+		}
+		table = append(table, ShardInfoRow{
+			ShardID:          info.ShardID,
+			RangeID:          info.RangeID,
+			StolenSinceRenew: info.StolenSinceRenew,
+			UpdatedAt:        info.UpdatedAt,
+			Owner:            info.Owner,
+		})
+	}
+	if err := Render(c, table, RenderOptions{DefaultTemplate: templateTable, Color: true, PrintDateTime: true}); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return commoncli.Problem(fmt.Sprintf("%v of %v shards failed", failed, numberOfShards), nil)
+	}
+	return nil
+}
+
+// getShard reads the shard info of a shard with a context of its own, so that reading many shards one after another
+// is not bounded by a single --context_timeout
+func getShard(c *cli.Context, shardManager persistence.ShardManager, sid int) (*persistence.GetShardResponse, error) {
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return nil, err
+	}
+	return shardManager.GetShard(ctx, &persistence.GetShardRequest{ShardID: sid})
+}
+
 // AdminDescribeShardDistribution describes shard distribution
 func AdminDescribeShardDistribution(c *cli.Context) error {
 	output := getDeps(c).Output()
@@ -627,7 +2613,44 @@ func AdminDescribeShardDistribution(c *cli.Context) error {
 		outputPageSize--
 	}
 	// output the remaining rows
-	return Render(c, table, opts)
+	if err := Render(c, table, opts); err != nil {
+		return err
+	}
+	if c.IsSet(FlagImbalanceThreshold) {
+		printShardImbalance(getDeps(c).Progress(), resp, c.Int(FlagImbalanceThreshold))
+	}
+	return nil
+}
+
+// printShardImbalance warns about the hosts owning more or fewer shards of resp than the mean shards per host
+// by more than thresholdPercent of it
+func printShardImbalance(w io.Writer, resp *types.DescribeShardDistributionResponse, thresholdPercent int) {
+	counts := make(map[string]int)
+	for _, host := range resp.Shards {
+		counts[host]++
+	}
+	hosts := make([]string, 0, len(counts))
+	for host := range counts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	mean := float64(len(resp.Shards)) / float64(len(counts))
+	var lines []string
+	for _, host := range hosts {
+		shards := counts[host]
+		if balance := historyHostBalance(float64(shards), mean, thresholdPercent); balance != "ok" {
+			lines = append(lines, fmt.Sprintf("  %-6s %s owns %d shards (%+.0f%%)", balance+":", host, shards, (float64(shards)/mean-1)*100))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s shard ownership deviates from the mean of %.1f shards per host by more than %d%%", colorRed("Warning:"), mean, thresholdPercent)
+	if int(resp.NumberOfShards) > len(resp.Shards) {
+		fmt.Fprintf(w, " in the %d returned shards", len(resp.Shards))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.Join(lines, "\n"))
 }
 
 // AdminDescribeHistoryHost describes history host
@@ -667,13 +2690,106 @@ func AdminDescribeHistoryHost(c *cli.Context) error {
 		return commoncli.Problem("Describe history host failed", err)
 	}
 
-	if !printFully {
-		resp.ShardIDs = nil
+	if printFully {
+		printObject(c, resp)
+		return nil
+	}
+	resp.ShardIDs = nil
+	if format := getOutputFormat(c); format == formatJSON || format == formatYAML {
+		printObject(c, resp)
+		return nil
 	}
-	prettyPrintJSONObject(getDeps(c).Output(), resp)
+	printHistoryHostSummary(getDeps(c).Output(), resp)
 	return nil
 }
 
+// printHistoryHostSummary prints one line per field of a history host description
+func printHistoryHostSummary(w io.Writer, resp *types.DescribeHistoryHostResponse) {
+	fmt.Fprintf(w, "Address: %v\n", resp.Address)
+	status := resp.ShardControllerStatus
+	if status == "" {
+		status = "unknown"
+	}
+	fmt.Fprintf(w, "Shard controller status: %v\n", status)
+	fmt.Fprintf(w, "Owned shards: %v\n", resp.NumberOfShards)
+	var domainCache types.DomainCacheInfo
+	if resp.DomainCache != nil {
+		domainCache = *resp.DomainCache
+	}
+	fmt.Fprintf(w, "Domain cache items by ID: %v\n", domainCache.NumOfItemsInCacheByID)
+	fmt.Fprintf(w, "Domain cache items by name: %v\n", domainCache.NumOfItemsInCacheByName)
+}
+
+// HistoryHostRow is a row of the history host table in `admin history_host list`
+type HistoryHostRow struct {
+	Address string `header:"Address"`
+	Shards  int32  `header:"Shards"`
+	Balance string `header:"Balance"`
+}
+
+// AdminListHistoryHosts lists every history host of the membership ring with the number of shards it owns
+func AdminListHistoryHosts(c *cli.Context) error {
+	adminClient, err := getDeps(c).ServerAdminClient(c)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return commoncli.Problem("Error in creating context", err)
+	}
+	cluster, err := adminClient.DescribeCluster(ctx)
+	if err != nil {
+		return commoncli.Problem("Operation DescribeCluster failed.", err)
+	}
+
+	var members []*types.HostInfo
+	if cluster.MembershipInfo != nil {
+		for _, ring := range cluster.MembershipInfo.Rings {
+			if ring.Role == service.History {
+				members = ring.Members
+			}
+		}
+	}
+	if len(members) == 0 {
+		return commoncli.Problem("No history host found in the membership ring", nil)
+	}
+
+	rows := make([]HistoryHostRow, 0, len(members))
+	var total int32
+	for _, member := range members {
+		resp, err := adminClient.DescribeHistoryHost(ctx, &types.DescribeHistoryHostRequest{
+			HostAddress: common.StringPtr(member.Identity),
+		})
+		if err != nil {
+			return commoncli.Problem(fmt.Sprintf("Describe history host %v failed", member.Identity), err)
+		}
+		rows = append(rows, HistoryHostRow{Address: member.Identity, Shards: resp.NumberOfShards})
+		total += resp.NumberOfShards
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Address < rows[j].Address })
+
+	mean := float64(total) / float64(len(rows))
+	for i := range rows {
+		rows[i].Balance = historyHostBalance(float64(rows[i].Shards), mean, c.Int(FlagImbalanceThreshold))
+	}
+	return Render(c, rows, RenderOptions{DefaultTemplate: templateTable, Color: true})
+}
+
+// historyHostBalance tells if a host owns more or fewer shards than the mean by more than thresholdPercent of it
+func historyHostBalance(shards, mean float64, thresholdPercent int) string {
+	threshold := float64(thresholdPercent) / 100
+	switch {
+	case shards > mean*(1+threshold):
+		return "over"
+	case shards < mean*(1-threshold):
+		return "under"
+	default:
+		return "ok"
+	}
+}
+
 // AdminRefreshWorkflowTasks refreshes all the tasks of a workflow
 func AdminRefreshWorkflowTasks(c *cli.Context) error {
 	adminClient, err := getDeps(c).ServerAdminClient(c)
@@ -734,18 +2850,109 @@ func AdminResetQueue(c *cli.Context) error {
 	if err != nil {
 		return commoncli.Problem("Error in creating context: ", err)
 	}
+	describeReq := &types.DescribeQueueRequest{
+		ShardID:     int32(shardID),
+		ClusterName: clusterName,
+		Type:        common.Int32Ptr(int32(typeID)),
+	}
 	req := &types.ResetQueueRequest{
 		ShardID:     int32(shardID),
 		ClusterName: clusterName,
 		Type:        common.Int32Ptr(int32(typeID)),
 	}
 
+	output := getDeps(c).Output()
+	// the queue may need a reset because it is in a bad state, so failing to describe it does not block the reset
+	if before, err := adminClient.DescribeQueue(ctx, describeReq); err != nil {
+		fmt.Fprintf(getDeps(c).Progress(), "%s failed to describe queue before reset: %v\n", colorRed("Warning:"), err)
+	} else {
+		printQueueStates(output, "Before reset", before.ProcessingQueueStates)
+	}
+
 	err = adminClient.ResetQueue(ctx, req)
 	if err != nil {
 		return commoncli.Problem("Failed to reset queue", err)
 	}
+	fmt.Fprintln(output, "Reset queue state succeeded")
+
+	after, err := adminClient.DescribeQueue(ctx, describeReq)
+	if err != nil {
+		fmt.Fprintf(getDeps(c).Progress(), "%s failed to describe queue after reset: %v\n", colorRed("Warning:"), err)
+		return nil
+	}
+	printQueueStates(output, "After reset", after.ProcessingQueueStates)
+	return nil
+}
+
+// printQueueStates prints the processing queue states of a queue, as returned by DescribeQueue, under a label
+func printQueueStates(w io.Writer, label string, states []string) {
+	fmt.Fprintf(w, "%s: %d processing queue states\n", label, len(states))
+	for _, state := range states {
+		fmt.Fprintln(w, state)
+	}
+}
+
+// AdminMergeQueue merges fragmented processing queue states into a single state.
+// There is no dedicated merge API: resetting the queue collapses all states into one that starts from the
+// minimum ack level of the existing states, so no task is skipped, though tasks past that level may be reprocessed.
+func AdminMergeQueue(c *cli.Context) error {
+	adminClient, err := getDeps(c).ServerAdminClient(c)
+	if err != nil {
+		return err
+	}
+
+	shardID, err := getRequiredIntOption(c, FlagShardID)
+	if err != nil {
+		return commoncli.Problem("Required flag not found", err)
+	}
+	clusterName, err := getRequiredOption(c, FlagCluster)
+	if err != nil {
+		return commoncli.Problem("Required flag not found", err)
+	}
+	typeID, err := getRequiredIntOption(c, FlagQueueType)
+	if err != nil {
+		return commoncli.Problem("Required flag not found", err)
+	}
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return commoncli.Problem("Error in creating context: ", err)
+	}
+	describeReq := &types.DescribeQueueRequest{
+		ShardID:     int32(shardID),
+		ClusterName: clusterName,
+		Type:        common.Int32Ptr(int32(typeID)),
+	}
+
+	before, err := adminClient.DescribeQueue(ctx, describeReq)
+	if err != nil {
+		return commoncli.Problem("Failed to describe queue", err)
+	}
+	output := getDeps(c).Output()
+	printQueueStates(output, "Before merge", before.ProcessingQueueStates)
+	if len(before.ProcessingQueueStates) <= 1 {
+		fmt.Fprintln(output, "Nothing to merge")
+		return nil
+	}
+
+	if !c.Bool(FlagYes) && !confirm(c, fmt.Sprintf("Merge %d processing queue states of shard %d?", len(before.ProcessingQueueStates), shardID)) {
+		return commoncli.Problem("Queue merge cancelled", nil)
+	}
+
+	err = adminClient.ResetQueue(ctx, &types.ResetQueueRequest{
+		ShardID:     int32(shardID),
+		ClusterName: clusterName,
+		Type:        common.Int32Ptr(int32(typeID)),
+	})
+	if err != nil {
+		return commoncli.Problem("Failed to merge queue", err)
+	}
 
-	fmt.Fprintln(getDeps(c).Output(), "Reset queue state succeeded")
+	after, err := adminClient.DescribeQueue(ctx, describeReq)
+	if err != nil {
+		return commoncli.Problem("Merged queue states, but failed to describe queue", err)
+	}
+	printQueueStates(output, "After merge", after.ProcessingQueueStates)
 	return nil
 }
 
@@ -786,8 +2993,52 @@ func AdminDescribeQueue(c *cli.Context) error {
 	}
 
 	output := getDeps(c).Output()
+	domainID := c.String(FlagDomainID)
+	matched := 0
 	for _, state := range resp.ProcessingQueueStates {
-		fmt.Fprintln(output, state)
+		if domainID == "" {
+			fmt.Fprintln(output, state)
+			continue
+		}
+		included, catchAll, ok := queueStateIncludesDomain(state, domainID)
+		switch {
+		case !ok:
+			fmt.Fprintln(output, state, "(domain filter not recognized)")
+		case catchAll:
+			fmt.Fprintln(output, state, "(catch-all)")
+		case included:
+			fmt.Fprintln(output, state)
+		default:
+			continue
+		}
+		matched++
+	}
+	if domainID != "" && matched == 0 {
+		fmt.Fprintf(getDeps(c).Progress(), "No queue state processes domain %v\n", domainID)
 	}
 	return nil
 }
+
+// queueStateDomainFilterRegex matches the domain filter of a serialized processing queue state, e.g.
+// domainFilter: {DomainIDs:map[id1:{} id2:{}] ReverseMatch:false}
+var queueStateDomainFilterRegex = regexp.MustCompile(`domainFilter: \{DomainIDs:map\[([^\]]*)\] ReverseMatch:(true|false)\}`)
+
+// queueStateIncludesDomain tells if a serialized processing queue state processes the tasks of domainID,
+// and if it does so as a catch-all state which lists the domains it excludes. ok is false if the state has no recognizable domain filter.
+func queueStateIncludesDomain(state, domainID string) (included, catchAll, ok bool) {
+	match := queueStateDomainFilterRegex.FindStringSubmatch(state)
+	if match == nil {
+		return false, false, false
+	}
+	listed := false
+	for _, entry := range strings.Fields(match[1]) {
+		if strings.TrimSuffix(entry, ":{}") == domainID {
+			listed = true
+			break
+		}
+	}
+	if match[2] == "true" {
+		return !listed, !listed, true
+	}
+	return listed, false, true
+}
@@ -23,10 +23,18 @@
 package cli
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -34,10 +42,13 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/mock/gomock"
+	"go.uber.org/yarpc"
 
+	"github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/client/admin"
 	"github.com/uber/cadence/client/frontend"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/codec"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/tools/cli/clitest"
@@ -140,16 +151,51 @@ func TestAdminResetQueue(t *testing.T) {
 					clitest.IntArgument(FlagQueueType, testQueueType),
 				)
 
-				td.mockAdminClient.EXPECT().ResetQueue(gomock.Any(), &types.ResetQueueRequest{
+				describeReq := &types.DescribeQueueRequest{
 					ShardID:     testShardID,
 					ClusterName: testCluster,
 					Type:        common.Int32Ptr(testQueueType),
-				})
+				}
+				gomock.InOrder(
+					td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), describeReq).
+						Return(&types.DescribeQueueResponse{ProcessingQueueStates: []string{"state-1", "state-2"}}, nil),
+					td.mockAdminClient.EXPECT().ResetQueue(gomock.Any(), &types.ResetQueueRequest{
+						ShardID:     testShardID,
+						ClusterName: testCluster,
+						Type:        common.Int32Ptr(testQueueType),
+					}),
+					td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), describeReq).
+						Return(&types.DescribeQueueResponse{ProcessingQueueStates: []string{"state-reset"}}, nil),
+				)
+
+				return cliCtx
+			},
+			errContains:    "",
+			expectedOutput: "Before reset: 2 processing queue states\nstate-1\nstate-2\nReset queue state succeeded\nAfter reset: 1 processing queue states\nstate-reset\n",
+		},
+		{
+			name: "DescribeQueue before reset returns an error",
+			testSetup: func(td *cliTestData) *cli.Context {
+				cliCtx := clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.StringArgument(FlagCluster, testCluster),
+					clitest.IntArgument(FlagQueueType, testQueueType),
+				)
+
+				gomock.InOrder(
+					td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).
+						Return(nil, errors.New("critical error")),
+					td.mockAdminClient.EXPECT().ResetQueue(gomock.Any(), gomock.Any()),
+					td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).
+						Return(&types.DescribeQueueResponse{ProcessingQueueStates: []string{"state-reset"}}, nil),
+				)
 
 				return cliCtx
 			},
 			errContains:    "",
-			expectedOutput: "Reset queue state succeeded\n",
+			expectedOutput: "Reset queue state succeeded\nAfter reset: 1 processing queue states\nstate-reset\n",
 		},
 		{
 			name: "ResetQueue returns an error",
@@ -162,12 +208,39 @@ func TestAdminResetQueue(t *testing.T) {
 					clitest.IntArgument(FlagQueueType, testQueueType),
 				)
 
+				td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).
+					Return(&types.DescribeQueueResponse{}, nil)
 				td.mockAdminClient.EXPECT().ResetQueue(gomock.Any(), gomock.Any()).
 					Return(errors.New("critical error"))
 
 				return cliCtx
 			},
-			errContains: "Failed to reset queue",
+			errContains:    "Failed to reset queue",
+			expectedOutput: "Before reset: 0 processing queue states\n",
+		},
+		{
+			name: "DescribeQueue after reset returns an error",
+			testSetup: func(td *cliTestData) *cli.Context {
+				cliCtx := clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.StringArgument(FlagCluster, testCluster),
+					clitest.IntArgument(FlagQueueType, testQueueType),
+				)
+
+				gomock.InOrder(
+					td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).
+						Return(&types.DescribeQueueResponse{}, nil),
+					td.mockAdminClient.EXPECT().ResetQueue(gomock.Any(), gomock.Any()),
+					td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).
+						Return(nil, errors.New("critical error")),
+				)
+
+				return cliCtx
+			},
+			errContains:    "",
+			expectedOutput: "Before reset: 0 processing queue states\nReset queue state succeeded\n",
 		},
 	}
 
@@ -187,6 +260,93 @@ func TestAdminResetQueue(t *testing.T) {
 	}
 }
 
+func TestAdminMergeQueue(t *testing.T) {
+	queueArgs := func(td *cliTestData, extra ...clitest.CliArgument) *cli.Context {
+		args := append([]clitest.CliArgument{
+			clitest.IntArgument(FlagShardID, testShardID),
+			clitest.StringArgument(FlagCluster, testCluster),
+			clitest.IntArgument(FlagQueueType, testQueueType),
+		}, extra...)
+		return clitest.NewCLIContext(t, td.app, args...)
+	}
+	fragmented := &types.DescribeQueueResponse{ProcessingQueueStates: []string{"state1", "state2"}}
+	merged := &types.DescribeQueueResponse{ProcessingQueueStates: []string{"merged"}}
+
+	tests := []struct {
+		name           string
+		testSetup      func(td *cliTestData) *cli.Context
+		errContains    string // empty if no error is expected
+		expectedOutput string
+	}{
+		{
+			name: "no shardID argument",
+			testSetup: func(td *cliTestData) *cli.Context {
+				return clitest.NewCLIContext(t, td.app /* arguments are missing */)
+			},
+			errContains: "Required flag not found",
+		},
+		{
+			name: "nothing to merge",
+			testSetup: func(td *cliTestData) *cli.Context {
+				td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).Return(merged, nil)
+				return queueArgs(td)
+			},
+			expectedOutput: "Before merge: 1 processing queue states\nmerged\nNothing to merge\n",
+		},
+		{
+			name: "merged with --yes",
+			testSetup: func(td *cliTestData) *cli.Context {
+				gomock.InOrder(
+					td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).Return(fragmented, nil),
+					td.mockAdminClient.EXPECT().ResetQueue(gomock.Any(), &types.ResetQueueRequest{
+						ShardID:     testShardID,
+						ClusterName: testCluster,
+						Type:        common.Int32Ptr(testQueueType),
+					}).Return(nil),
+					td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).Return(merged, nil),
+				)
+				return queueArgs(td, clitest.BoolArgument(FlagYes, true))
+			},
+			expectedOutput: "Before merge: 2 processing queue states\nstate1\nstate2\nAfter merge: 1 processing queue states\nmerged\n",
+		},
+		{
+			name: "not confirmed",
+			testSetup: func(td *cliTestData) *cli.Context {
+				td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).Return(fragmented, nil)
+				td.ioHandler.input = strings.NewReader("n\n")
+				return queueArgs(td)
+			},
+			errContains:    "Queue merge cancelled",
+			expectedOutput: "Before merge: 2 processing queue states\nstate1\nstate2\n",
+		},
+		{
+			name: "ResetQueue returns an error",
+			testSetup: func(td *cliTestData) *cli.Context {
+				td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).Return(fragmented, nil)
+				td.mockAdminClient.EXPECT().ResetQueue(gomock.Any(), gomock.Any()).Return(errors.New("critical error"))
+				return queueArgs(td, clitest.BoolArgument(FlagYes, true))
+			},
+			errContains:    "Failed to merge queue",
+			expectedOutput: "Before merge: 2 processing queue states\nstate1\nstate2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newCLITestData(t)
+			cliCtx := tt.testSetup(td)
+
+			err := AdminMergeQueue(cliCtx)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errContains)
+			}
+			assert.Equal(t, tt.expectedOutput, td.consoleOutput())
+		})
+	}
+}
+
 func TestAdminDescribeQueue(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -250,6 +410,35 @@ func TestAdminDescribeQueue(t *testing.T) {
 			errContains:    "",
 			expectedOutput: "state1\nstate2\n",
 		},
+		{
+			name: "filter by domain ID",
+			testSetup: func(td *cliTestData) *cli.Context {
+				cliCtx := clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.StringArgument(FlagCluster, testCluster),
+					clitest.IntArgument(FlagQueueType, testQueueType),
+					clitest.StringArgument(FlagDomainID, "a-id"),
+				)
+
+				td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).
+					Return(&types.DescribeQueueResponse{
+						ProcessingQueueStates: []string{
+							"&{level: 0, domainFilter: {DomainIDs:map[a-id:{} b-id:{}] ReverseMatch:false}}",
+							"&{level: 1, domainFilter: {DomainIDs:map[b-id:{}] ReverseMatch:false}}",
+							"&{level: 2, domainFilter: {DomainIDs:map[b-id:{}] ReverseMatch:true}}",
+							"&{level: 3, domainFilter: {DomainIDs:map[a-id:{}] ReverseMatch:true}}",
+							"state",
+						},
+					}, nil)
+
+				return cliCtx
+			},
+			expectedOutput: "&{level: 0, domainFilter: {DomainIDs:map[a-id:{} b-id:{}] ReverseMatch:false}}\n" +
+				"&{level: 2, domainFilter: {DomainIDs:map[b-id:{}] ReverseMatch:true}} (catch-all)\n" +
+				"state (domain filter not recognized)\n",
+		},
 		{
 			name: "DescribeQueue returns an error",
 			testSetup: func(td *cliTestData) *cli.Context {
@@ -427,8 +616,71 @@ func TestAdminDescribeHistoryHost(t *testing.T) {
 				return cliCtx
 			},
 			errContains: "",
+			expectedOutput: `Address: host:port
+Shard controller status: unknown
+Owned shards: 12
+Domain cache items by ID: 0
+Domain cache items by name: 0
+`,
+		},
+		{
+			name: "summary with domain cache",
+			testSetup: func(td *cliTestData) *cli.Context {
+				td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), gomock.Any()).Return(
+					&types.DescribeHistoryHostResponse{
+						NumberOfShards:        2,
+						ShardIDs:              []int32{1, 2},
+						DomainCache:           &types.DomainCacheInfo{NumOfItemsInCacheByID: 5, NumOfItemsInCacheByName: 4},
+						ShardControllerStatus: "started",
+						Address:               "host:port",
+					},
+					nil,
+				)
+				return clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagHistoryAddress, "host:port"))
+			},
+			expectedOutput: `Address: host:port
+Shard controller status: started
+Owned shards: 2
+Domain cache items by ID: 5
+Domain cache items by name: 4
+`,
+		},
+		{
+			name: "json without shard IDs",
+			testSetup: func(td *cliTestData) *cli.Context {
+				td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), gomock.Any()).Return(
+					&types.DescribeHistoryHostResponse{NumberOfShards: 2, ShardIDs: []int32{1, 2}, Address: "host:port"},
+					nil,
+				)
+				return clitest.NewCLIContext(t, td.app,
+					clitest.StringArgument(FlagHistoryAddress, "host:port"),
+					clitest.StringArgument(FlagFormat, formatJSON),
+				)
+			},
+			expectedOutput: `{
+  "numberOfShards": 2,
+  "address": "host:port"
+}
+`,
+		},
+		{
+			name: "print fully",
+			testSetup: func(td *cliTestData) *cli.Context {
+				td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), gomock.Any()).Return(
+					&types.DescribeHistoryHostResponse{NumberOfShards: 2, ShardIDs: []int32{1, 2}, Address: "host:port"},
+					nil,
+				)
+				return clitest.NewCLIContext(t, td.app,
+					clitest.StringArgument(FlagHistoryAddress, "host:port"),
+					clitest.BoolArgument(FlagPrintFullyDetail, true),
+				)
+			},
 			expectedOutput: `{
-  "numberOfShards": 12,
+  "numberOfShards": 2,
+  "shardIDs": [
+    1,
+    2
+  ],
   "address": "host:port"
 }
 `,
@@ -467,6 +719,247 @@ func TestAdminDescribeHistoryHost(t *testing.T) {
 	}
 }
 
+func TestAdminListHistoryHosts(t *testing.T) {
+	cluster := &types.DescribeClusterResponse{
+		MembershipInfo: &types.MembershipInfo{
+			Rings: []*types.RingInfo{
+				{Role: "cadence-frontend", Members: []*types.HostInfo{{Identity: "frontend:7933"}}},
+				{Role: "cadence-history", Members: []*types.HostInfo{{Identity: "host-b:7934"}, {Identity: "host-a:7934"}, {Identity: "host-c:7934"}}},
+			},
+		},
+	}
+	shards := map[string]int32{"host-a:7934": 10, "host-b:7934": 2, "host-c:7934": 18}
+
+	td := newCLITestData(t)
+	td.mockAdminClient.EXPECT().DescribeCluster(gomock.Any()).Return(cluster, nil)
+	td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *types.DescribeHistoryHostRequest, _ ...yarpc.CallOption) (*types.DescribeHistoryHostResponse, error) {
+			return &types.DescribeHistoryHostResponse{NumberOfShards: shards[req.GetHostAddress()]}, nil
+		}).Times(3)
+
+	cliCtx := clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagFormat, formatJSON), clitest.IntArgument(FlagImbalanceThreshold, historyHostImbalanceThreshold))
+	require.NoError(t, AdminListHistoryHosts(cliCtx))
+
+	var rows []HistoryHostRow
+	require.NoError(t, json.Unmarshal([]byte(td.consoleOutput()), &rows))
+	assert.Equal(t, []HistoryHostRow{
+		{Address: "host-a:7934", Shards: 10, Balance: "ok"},
+		{Address: "host-b:7934", Shards: 2, Balance: "under"},
+		{Address: "host-c:7934", Shards: 18, Balance: "over"},
+	}, rows)
+
+	td = newCLITestData(t)
+	td.mockAdminClient.EXPECT().DescribeCluster(gomock.Any()).Return(cluster, nil)
+	td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *types.DescribeHistoryHostRequest, _ ...yarpc.CallOption) (*types.DescribeHistoryHostResponse, error) {
+			return &types.DescribeHistoryHostResponse{NumberOfShards: shards[req.GetHostAddress()]}, nil
+		}).Times(3)
+	cliCtx = clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagFormat, formatJSON), clitest.IntArgument(FlagImbalanceThreshold, 90))
+	require.NoError(t, AdminListHistoryHosts(cliCtx))
+	require.NoError(t, json.Unmarshal([]byte(td.consoleOutput()), &rows))
+	for _, row := range rows {
+		assert.Equal(t, "ok", row.Balance, row.Address)
+	}
+
+	td = newCLITestData(t)
+	td.mockAdminClient.EXPECT().DescribeCluster(gomock.Any()).Return(&types.DescribeClusterResponse{}, nil)
+	cliCtx = clitest.NewCLIContext(t, td.app)
+	assert.ErrorContains(t, AdminListHistoryHosts(cliCtx), "No history host found")
+}
+
+func TestAdminDescribeDomainReplication(t *testing.T) {
+	domain := &types.DescribeDomainResponse{
+		DomainInfo: &types.DomainInfo{Name: "test-domain", UUID: "test-domain-id"},
+		ReplicationConfiguration: &types.DomainReplicationConfiguration{
+			ActiveClusterName: "cluster-a",
+			Clusters:          []*types.ClusterReplicationConfiguration{{ClusterName: "cluster-a"}, {ClusterName: "cluster-b"}},
+		},
+		FailoverVersion: 10,
+		IsGlobalDomain:  true,
+	}
+
+	td := newCLITestData(t)
+	td.mockFrontendClient.EXPECT().DescribeDomain(gomock.Any(), &types.DescribeDomainRequest{Name: common.StringPtr("test-domain")}).Return(domain, nil)
+	td.mockAdminClient.EXPECT().CountDLQMessages(gomock.Any(), gomock.Any()).Return(&types.CountDLQMessagesResponse{
+		History: map[types.HistoryDLQCountKey]int64{
+			{ShardID: 1, SourceCluster: "cluster-b"}: 3,
+			{ShardID: 2, SourceCluster: "cluster-b"}: 4,
+		},
+	}, nil)
+
+	cliCtx := clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagDomain, "test-domain"), clitest.StringArgument(FlagFormat, formatJSON))
+	require.NoError(t, AdminDescribeDomainReplication(cliCtx))
+
+	var status DomainReplicationStatus
+	require.NoError(t, json.Unmarshal([]byte(td.consoleOutput()), &status))
+	assert.Equal(t, DomainReplicationStatus{
+		Domain:          "test-domain",
+		DomainID:        "test-domain-id",
+		IsGlobalDomain:  true,
+		ActiveCluster:   "cluster-a",
+		FailoverVersion: 10,
+		Clusters: []DomainReplicationRow{
+			{Cluster: "cluster-a", Active: true, DLQMessages: common.Int64Ptr(0)},
+			{Cluster: "cluster-b", Active: false, DLQMessages: common.Int64Ptr(7)},
+		},
+	}, status)
+
+	td = newCLITestData(t)
+	td.mockFrontendClient.EXPECT().DescribeDomain(gomock.Any(), gomock.Any()).Return(domain, nil)
+	td.mockAdminClient.EXPECT().CountDLQMessages(gomock.Any(), gomock.Any()).Return(nil, errors.New("no access"))
+
+	cliCtx = clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagDomain, "test-domain"))
+	require.NoError(t, AdminDescribeDomainReplication(cliCtx))
+	assert.Contains(t, td.consoleOutput(), "Active cluster: cluster-a\n")
+	assert.Contains(t, td.consoleOutput(), "cluster-b")
+
+	td = newCLITestData(t)
+	td.mockFrontendClient.EXPECT().DescribeDomain(gomock.Any(), gomock.Any()).Return(domain, nil)
+	td.mockAdminClient.EXPECT().CountDLQMessages(gomock.Any(), gomock.Any()).Return(nil, errors.New("no access"))
+
+	cliCtx = clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagDomain, "test-domain"), clitest.StringArgument(FlagFormat, formatJSON))
+	require.NoError(t, AdminDescribeDomainReplication(cliCtx))
+	assert.Contains(t, td.consoleOutput(), `"replicationDLQMessages": null`)
+}
+
+func TestAdminListGlobalDomains(t *testing.T) {
+	domain := func(name, activeCluster string, isGlobal bool) *types.DescribeDomainResponse {
+		return &types.DescribeDomainResponse{
+			DomainInfo: &types.DomainInfo{Name: name, UUID: name + "-id"},
+			ReplicationConfiguration: &types.DomainReplicationConfiguration{
+				ActiveClusterName: activeCluster,
+				Clusters:          []*types.ClusterReplicationConfiguration{{ClusterName: "cluster-a"}, {ClusterName: "cluster-b"}},
+			},
+			IsGlobalDomain: isGlobal,
+		}
+	}
+	td := newCLITestData(t)
+	gomock.InOrder(
+		td.mockFrontendClient.EXPECT().ListDomains(gomock.Any(), &types.ListDomainsRequest{PageSize: failoverDomainsPageSize}).
+			Return(&types.ListDomainsResponse{
+				Domains: []*types.DescribeDomainResponse{
+					domain("domain-c", "cluster-a", true),
+					domain("domain-local", "cluster-a", false),
+				},
+				NextPageToken: []byte("next"),
+			}, nil),
+		td.mockFrontendClient.EXPECT().ListDomains(gomock.Any(), &types.ListDomainsRequest{PageSize: failoverDomainsPageSize, NextPageToken: []byte("next")}).
+			Return(&types.ListDomainsResponse{
+				Domains: []*types.DescribeDomainResponse{
+					domain("domain-b", "cluster-b", true),
+					domain("domain-a", "cluster-a", true),
+				},
+			}, nil),
+	)
+
+	cliCtx := clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagCluster, "cluster-a"), clitest.StringArgument(FlagFormat, formatJSON))
+	require.NoError(t, AdminListGlobalDomains(cliCtx))
+
+	var rows []GlobalDomainRow
+	require.NoError(t, json.Unmarshal([]byte(td.consoleOutput()), &rows))
+	assert.Equal(t, []GlobalDomainRow{
+		{Name: "domain-a", DomainID: "domain-a-id", ActiveCluster: "cluster-a", Clusters: []string{"cluster-a", "cluster-b"}},
+		{Name: "domain-c", DomainID: "domain-c-id", ActiveCluster: "cluster-a", Clusters: []string{"cluster-a", "cluster-b"}},
+	}, rows)
+
+	td = newCLITestData(t)
+	td.mockFrontendClient.EXPECT().ListDomains(gomock.Any(), gomock.Any()).
+		Return(&types.ListDomainsResponse{
+			Domains: []*types.DescribeDomainResponse{domain("domain-b", "cluster-b", true), domain("domain-a", "cluster-a", true)},
+		}, nil)
+	cliCtx = clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagFormat, "{{.Name}}"))
+	require.NoError(t, AdminListGlobalDomains(cliCtx))
+	assert.Equal(t, "domain-a\ndomain-b\n", td.consoleOutput())
+}
+
+func TestAdminTransferShard(t *testing.T) {
+	oldInterval := shardTransferCheckInterval
+	shardTransferCheckInterval = time.Millisecond
+	defer func() { shardTransferCheckInterval = oldInterval }()
+
+	describeReq := &types.DescribeHistoryHostRequest{ShardIDForHost: common.Int32Ptr(int32(testShardID))}
+	owner := func(address string) *types.DescribeHistoryHostResponse {
+		return &types.DescribeHistoryHostResponse{Address: address}
+	}
+	expectOwnerUpdate := func(td *cliTestData) {
+		mockShardManager := persistence.NewMockShardManager(td.ctrl)
+		mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: testShardID}).
+			Return(&persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{ShardID: testShardID, Owner: "host-a", RangeID: 10}}, nil)
+		mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, req *persistence.UpdateShardRequest) {
+				assert.Equal(t, int64(10), req.PreviousRangeID)
+				assert.Equal(t, int64(10), req.ShardInfo.RangeID)
+				assert.Equal(t, "host-b", req.ShardInfo.Owner)
+			}).
+			Return(nil)
+		td.mockManagerFactory.EXPECT().initializeShardManager(gomock.Any()).Return(mockShardManager, nil)
+	}
+	tests := []struct {
+		name           string
+		mockSetup      func(td *cliTestData)
+		errContains    string
+		expectedOutput string
+	}{
+		{
+			name: "already owned",
+			mockSetup: func(td *cliTestData) {
+				td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), describeReq).Return(owner("host-b"), nil)
+			},
+			expectedOutput: fmt.Sprintf("Shard %d is already owned by host-b\n", testShardID),
+		},
+		{
+			name: "moved",
+			mockSetup: func(td *cliTestData) {
+				td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), describeReq).Return(owner("host-a"), nil)
+				expectOwnerUpdate(td)
+				gomock.InOrder(
+					td.mockAdminClient.EXPECT().CloseShard(gomock.Any(), &types.CloseShardRequest{ShardID: int32(testShardID)}).Return(nil),
+					td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), describeReq).Return(nil, &types.ShardOwnershipLostError{}),
+					td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), describeReq).Return(owner("host-b"), nil),
+				)
+			},
+			expectedOutput: fmt.Sprintf("Shard %d moved from host-a to host-b\n", testShardID),
+		},
+		{
+			name: "does not converge",
+			mockSetup: func(td *cliTestData) {
+				td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), describeReq).Return(owner("host-a"), nil).MinTimes(2)
+				expectOwnerUpdate(td)
+				td.mockAdminClient.EXPECT().CloseShard(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			errContains: fmt.Sprintf("Shard %d is owned by host-a instead of host-b after 20ms", testShardID),
+		},
+		{
+			name: "owner update failed",
+			mockSetup: func(td *cliTestData) {
+				td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), describeReq).Return(owner("host-a"), nil)
+				mockShardManager := persistence.NewMockShardManager(td.ctrl)
+				mockShardManager.EXPECT().GetShard(gomock.Any(), gomock.Any()).Return(nil, errors.New("db unavailable"))
+				td.mockManagerFactory.EXPECT().initializeShardManager(gomock.Any()).Return(mockShardManager, nil)
+			},
+			errContains: "Failed to get shardInfo.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newCLITestData(t)
+			tt.mockSetup(td)
+			cliCtx := clitest.NewCLIContext(t, td.app,
+				clitest.IntArgument(FlagShardID, testShardID),
+				clitest.StringArgument(FlagTargetHost, "host-b"),
+				clitest.StringArgument(FlagWaitTimeout, "20ms"),
+			)
+			err := AdminTransferShard(cliCtx)
+			if tt.errContains != "" {
+				assert.ErrorContains(t, err, tt.errContains)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedOutput, td.consoleOutput())
+		})
+	}
+}
+
 func TestAdminCloseShard(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -583,6 +1076,7 @@ func TestAdminRemoveTask(t *testing.T) {
 					clitest.IntArgument(FlagShardID, testShardID),
 					clitest.Int64Argument(FlagTaskID, 123),
 					clitest.IntArgument(FlagTaskType, 1), // some valid type
+					clitest.BoolArgument(FlagYes, true),
 				)
 
 				td.mockAdminClient.EXPECT().RemoveTask(gomock.Any(),
@@ -607,6 +1101,7 @@ func TestAdminRemoveTask(t *testing.T) {
 					clitest.IntArgument(FlagShardID, testShardID),
 					clitest.Int64Argument(FlagTaskID, 123),
 					clitest.IntArgument(FlagTaskType, 1), // some valid type
+					clitest.BoolArgument(FlagYes, true),
 				)
 
 				td.mockAdminClient.EXPECT().RemoveTask(gomock.Any(), gomock.Any()).
@@ -625,6 +1120,7 @@ func TestAdminRemoveTask(t *testing.T) {
 					clitest.IntArgument(FlagShardID, testShardID),
 					clitest.Int64Argument(FlagTaskID, 123),
 					clitest.IntArgument(FlagTaskType, int(common.TaskTypeTimer)),
+					clitest.BoolArgument(FlagYes, true),
 					clitest.Int64Argument(FlagTaskVisibilityTimestamp, 1616161616), // visibility timestamp
 				)
 
@@ -642,68 +1138,196 @@ func TestAdminRemoveTask(t *testing.T) {
 			errContains: "",
 		},
 		{
-			name: "calling with Timer task requiring visibility timestamp, but not provided",
+			name: "replication task found in DLQ with dlq_aware is not removed",
 			testSetup: func(td *cliTestData) *cli.Context {
 				cliCtx := clitest.NewCLIContext(
 					t,
 					td.app,
 					clitest.IntArgument(FlagShardID, testShardID),
 					clitest.Int64Argument(FlagTaskID, 123),
-					clitest.IntArgument(FlagTaskType, int(common.TaskTypeTimer)),
-					// visibility timestamp is missing though FlagTaskType is common.TaskTypeTimer
+					clitest.IntArgument(FlagTaskType, int(common.TaskTypeReplication)),
+					clitest.StringArgument(FlagCluster, "cluster-a"),
+					clitest.BoolArgument(FlagDLQAware, true),
 				)
 
+				td.mockAdminClient.EXPECT().ReadDLQMessages(gomock.Any(), &types.ReadDLQMessagesRequest{
+					Type:                  types.DLQTypeReplication.Ptr(),
+					SourceCluster:         "cluster-a",
+					ShardID:               int32(testShardID),
+					InclusiveEndMessageID: common.Int64Ptr(123),
+					MaximumPageSize:       defaultPageSize,
+				}).Return(&types.ReadDLQMessagesResponse{
+					ReplicationTasksInfo: []*types.ReplicationTaskInfo{{TaskID: 122}, {TaskID: 123}},
+				}, nil)
+
 				return cliCtx
 			},
-			errContains: "Required flag not found",
+			errContains: "refusing to remove replication task 123",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			td := newCLITestData(t)
-			cliCtx := tt.testSetup(td)
-
-			err := AdminRemoveTask(cliCtx)
-			if tt.errContains == "" {
-				assert.NoError(t, err)
-			} else {
-				assert.ErrorContains(t, err, tt.errContains)
-			}
-		})
-	}
-}
-
-func TestAdminGetShardID(t *testing.T) {
-	tests := []struct {
-		name           string
-		testSetup      func(td *cliTestData) *cli.Context
-		expectedOutput string // expected output to check against
-		errContains    string // empty if no error is expected
-	}{
 		{
-			name: "no WorkflowID provided",
+			name: "replication task not found in DLQ is removed",
 			testSetup: func(td *cliTestData) *cli.Context {
-				return clitest.NewCLIContext(t, td.app /* arguments are missing */)
+				cliCtx := clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.Int64Argument(FlagTaskID, 123),
+					clitest.IntArgument(FlagTaskType, int(common.TaskTypeReplication)),
+					clitest.BoolArgument(FlagYes, true),
+					clitest.StringArgument(FlagCluster, "cluster-a"),
+					clitest.BoolArgument(FlagDLQAware, true),
+				)
+
+				td.mockAdminClient.EXPECT().ReadDLQMessages(gomock.Any(), gomock.Any()).
+					Return(&types.ReadDLQMessagesResponse{
+						ReplicationTasksInfo: []*types.ReplicationTaskInfo{{TaskID: 100}},
+					}, nil)
+				td.mockAdminClient.EXPECT().RemoveTask(gomock.Any(), gomock.Any()).Return(nil)
+
+				return cliCtx
 			},
-			errContains: "Required flag not found",
+			errContains: "",
 		},
 		{
-			name: "numberOfShards not provided",
+			name: "replication task with dlq_aware requires cluster",
 			testSetup: func(td *cliTestData) *cli.Context {
-				return clitest.NewCLIContext(t, td.app,
-					clitest.StringArgument(FlagWorkflowID, "some-workflow-id"),
-					/* numberOfShards is missing */
+				return clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.Int64Argument(FlagTaskID, 123),
+					clitest.IntArgument(FlagTaskType, int(common.TaskTypeReplication)),
+					clitest.BoolArgument(FlagDLQAware, true),
 				)
 			},
-			errContains: "numberOfShards is required",
+			errContains: "--cluster is required",
 		},
 		{
-			name: "numberOfShards is zero",
+			name: "task is shown and its removal confirmed",
 			testSetup: func(td *cliTestData) *cli.Context {
-				return clitest.NewCLIContext(t, td.app,
-					clitest.StringArgument(FlagWorkflowID, "some-workflow-id"),
-					clitest.IntArgument(FlagNumberOfShards, 0), // zero is invalid
+				cliCtx := clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.Int64Argument(FlagTaskID, 123),
+					clitest.IntArgument(FlagTaskType, int(common.TaskTypeTransfer)),
+					clitest.BoolArgument(FlagShowTask, true),
+				)
+				td.ioHandler.input = strings.NewReader("y\n")
+
+				mockExecutionManager := persistence.NewMockExecutionManager(td.ctrl)
+				td.mockManagerFactory.EXPECT().initializeExecutionManager(gomock.Any(), testShardID).Return(mockExecutionManager, nil)
+				mockExecutionManager.EXPECT().GetTransferTasks(gomock.Any(), &persistence.GetTransferTasksRequest{
+					ReadLevel:    122,
+					MaxReadLevel: 123,
+					BatchSize:    1,
+				}).Return(&persistence.GetTransferTasksResponse{
+					Tasks: []*persistence.TransferTaskInfo{{TaskID: 123, WorkflowID: testWorkflowID, RunID: testRunID}},
+				}, nil)
+				mockExecutionManager.EXPECT().Close()
+				td.mockAdminClient.EXPECT().RemoveTask(gomock.Any(), gomock.Any()).Return(nil)
+
+				return cliCtx
+			},
+			errContains: "",
+		},
+		{
+			name: "task lookup fails and the task is still removed",
+			testSetup: func(td *cliTestData) *cli.Context {
+				cliCtx := clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.Int64Argument(FlagTaskID, 123),
+					clitest.IntArgument(FlagTaskType, int(common.TaskTypeTransfer)),
+					clitest.BoolArgument(FlagShowTask, true),
+					clitest.BoolArgument(FlagYes, true),
+				)
+				td.mockManagerFactory.EXPECT().initializeExecutionManager(gomock.Any(), testShardID).Return(nil, errors.New("no database"))
+				td.mockAdminClient.EXPECT().RemoveTask(gomock.Any(), gomock.Any()).Return(nil)
+
+				return cliCtx
+			},
+			errContains: "",
+		},
+		{
+			name: "task removal not confirmed",
+			testSetup: func(td *cliTestData) *cli.Context {
+				cliCtx := clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.Int64Argument(FlagTaskID, 123),
+					clitest.IntArgument(FlagTaskType, int(common.TaskTypeTransfer)),
+				)
+				td.ioHandler.input = strings.NewReader("n\n")
+
+				return cliCtx
+			},
+			errContains: "Task removal cancelled",
+		},
+		{
+			name: "calling with Timer task requiring visibility timestamp, but not provided",
+			testSetup: func(td *cliTestData) *cli.Context {
+				cliCtx := clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.Int64Argument(FlagTaskID, 123),
+					clitest.IntArgument(FlagTaskType, int(common.TaskTypeTimer)),
+					// visibility timestamp is missing though FlagTaskType is common.TaskTypeTimer
+				)
+
+				return cliCtx
+			},
+			errContains: "Required flag not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newCLITestData(t)
+			cliCtx := tt.testSetup(td)
+			err := AdminRemoveTask(cliCtx)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestAdminGetShardID(t *testing.T) {
+	tests := []struct {
+		name           string
+		testSetup      func(td *cliTestData) *cli.Context
+		expectedOutput string // expected output to check against
+		errContains    string // empty if no error is expected
+	}{
+		{
+			name: "no WorkflowID provided",
+			testSetup: func(td *cliTestData) *cli.Context {
+				return clitest.NewCLIContext(t, td.app /* arguments are missing */)
+			},
+			errContains: "Required flag not found",
+		},
+		{
+			name: "numberOfShards not provided",
+			testSetup: func(td *cliTestData) *cli.Context {
+				return clitest.NewCLIContext(t, td.app,
+					clitest.StringArgument(FlagWorkflowID, "some-workflow-id"),
+					/* numberOfShards is missing */
+				)
+			},
+			errContains: "numberOfShards is required",
+		},
+		{
+			name: "numberOfShards is zero",
+			testSetup: func(td *cliTestData) *cli.Context {
+				return clitest.NewCLIContext(t, td.app,
+					clitest.StringArgument(FlagWorkflowID, "some-workflow-id"),
+					clitest.IntArgument(FlagNumberOfShards, 0), // zero is invalid
 				)
 			},
 			errContains: "numberOfShards is required",
@@ -721,6 +1345,32 @@ func TestAdminGetShardID(t *testing.T) {
 			expectedOutput: "ShardID for workflowID: test-workflow-id is 6\n",
 			errContains:    "",
 		},
+		{
+			name: "with host",
+			testSetup: func(td *cliTestData) *cli.Context {
+				td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), &types.DescribeHistoryHostRequest{ShardIDForHost: common.Int32Ptr(6)}).
+					Return(&types.DescribeHistoryHostResponse{Address: "host-1:7934"}, nil)
+				return clitest.NewCLIContext(t, td.app,
+					clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+					clitest.IntArgument(FlagNumberOfShards, 10),
+					clitest.BoolArgument(FlagWithHost, true),
+				)
+			},
+			expectedOutput: "ShardID for workflowID: test-workflow-id is 6\nShard 6 is owned by history host host-1:7934\n",
+		},
+		{
+			name: "with host when the server is unavailable",
+			testSetup: func(td *cliTestData) *cli.Context {
+				td.mockAdminClient.EXPECT().DescribeHistoryHost(gomock.Any(), gomock.Any()).
+					Return(nil, &types.InternalServiceError{Message: "unavailable"})
+				return clitest.NewCLIContext(t, td.app,
+					clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+					clitest.IntArgument(FlagNumberOfShards, 10),
+					clitest.BoolArgument(FlagWithHost, true),
+				)
+			},
+			errContains: "Failed to find the history host of shard 6",
+		},
 	}
 
 	for _, tt := range tests {
@@ -847,6 +1497,49 @@ Number of Shards Returned: 1
 	}
 }
 
+func TestAdminDescribeShardDistributionImbalance(t *testing.T) {
+	td := newCLITestData(t)
+	td.mockAdminClient.EXPECT().DescribeShardDistribution(gomock.Any(), gomock.Any()).
+		Return(
+			&types.DescribeShardDistributionResponse{
+				NumberOfShards: 8,
+				Shards: map[int32]string{
+					0: "host1", 1: "host1", 2: "host1", 3: "host1",
+					4: "host2", 5: "host3",
+				},
+			}, nil,
+		)
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.IntArgument(FlagPageSize, 6),
+		clitest.IntArgument(FlagImbalanceThreshold, 40),
+		clitest.StringArgument(FlagFormat, formatJSON),
+	)
+
+	require.NoError(t, AdminDescribeShardDistribution(cliCtx))
+	output := td.consoleOutput()
+	assert.NotContains(t, output, "Warning:", "the warning goes to the progress output, not in the JSON")
+	assert.True(t, json.Valid([]byte(output[strings.Index(output, "["):])), output)
+
+	resp := &types.DescribeShardDistributionResponse{
+		NumberOfShards: 8,
+		Shards: map[int32]string{
+			0: "host1", 1: "host1", 2: "host1", 3: "host1",
+			4: "host2", 5: "host3",
+		},
+	}
+	var w strings.Builder
+	printShardImbalance(&w, resp, 40)
+	assert.Equal(t, colorRed("Warning:")+` shard ownership deviates from the mean of 2.0 shards per host by more than 40% in the 6 returned shards
+  over:  host1 owns 4 shards (+100%)
+  under: host2 owns 1 shards (-50%)
+  under: host3 owns 1 shards (-50%)
+`, w.String())
+
+	w.Reset()
+	printShardImbalance(&w, &types.DescribeShardDistributionResponse{NumberOfShards: 3, Shards: map[int32]string{0: "host1", 1: "host1", 2: "host2"}}, 50)
+	assert.Empty(t, w.String())
+}
+
 func TestAdminMaintainCorruptWorkflow(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -922,6 +1615,10 @@ func TestAdminMaintainCorruptWorkflow(t *testing.T) {
 }
 
 func TestAdminDescribeShard(t *testing.T) {
+	oldMinInterval := shardProgressMinInterval
+	shardProgressMinInterval = time.Millisecond
+	defer func() { shardProgressMinInterval = oldMinInterval }()
+
 	tests := []struct {
 		name        string
 		testSetup   func(td *cliTestData) *cli.Context
@@ -1007,6 +1704,92 @@ func TestAdminDescribeShard(t *testing.T) {
 				assert.Equal(t, "host-abc", resp.ShardInfo.Owner)
 			},
 		},
+		{
+			name: "history of a moving shard",
+			testSetup: func(td *cliTestData) *cli.Context {
+				cliCtx := clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.BoolArgument(FlagHistory, true),
+					clitest.StringArgument(FlagInterval, "10ms"),
+				)
+
+				timerAckLevel := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				mockShardManager := persistence.NewMockShardManager(td.ctrl)
+				gomock.InOrder(
+					mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: testShardID}).
+						Return(&persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{
+							ShardID: testShardID, Owner: "host-abc", TransferAckLevel: 100, TimerAckLevel: timerAckLevel,
+						}}, nil),
+					mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: testShardID}).
+						Return(&persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{
+							ShardID: testShardID, Owner: "host-abc", TransferAckLevel: 150, TimerAckLevel: timerAckLevel.Add(20 * time.Millisecond),
+						}}, nil),
+				)
+				td.mockManagerFactory.EXPECT().initializeShardManager(gomock.Any()).
+					Return(mockShardManager, nil)
+
+				return cliCtx
+			},
+			checkOutput: func(td *cliTestData) {
+				assert.Equal(t, fmt.Sprintf(`Shard %v owned by host-abc
+Transfer ack level: 100 -> 150 (+50, 5000.00 task IDs/s)
+Timer ack level: 2024-01-01T00:00:00Z -> 2024-01-01T00:00:00Z (+20ms, 2.00x real time)
+`, testShardID), td.consoleOutput())
+			},
+		},
+		{
+			name: "history of a stuck shard",
+			testSetup: func(td *cliTestData) *cli.Context {
+				cliCtx := clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.BoolArgument(FlagHistory, true),
+					clitest.StringArgument(FlagInterval, "10ms"),
+				)
+
+				mockShardManager := persistence.NewMockShardManager(td.ctrl)
+				mockShardManager.EXPECT().GetShard(gomock.Any(), gomock.Any()).
+					Return(&persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{
+						ShardID: testShardID, Owner: "host-abc", TransferAckLevel: 100,
+					}}, nil).Times(2)
+				td.mockManagerFactory.EXPECT().initializeShardManager(gomock.Any()).
+					Return(mockShardManager, nil)
+
+				return cliCtx
+			},
+			checkOutput: func(td *cliTestData) {
+				assert.Contains(t, td.consoleOutput(), "neither ack level moved in 10ms, the shard may be stuck")
+			},
+		},
+		{
+			name: "history of a shard range",
+			testSetup: func(td *cliTestData) *cli.Context {
+				return clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagLowerShardBound, 1),
+					clitest.IntArgument(FlagUpperShardBound, 2),
+					clitest.BoolArgument(FlagHistory, true),
+				)
+			},
+			errContains: "--history cannot be used with a shard range",
+		},
+		{
+			name: "history interval shorter than the shard update interval",
+			testSetup: func(td *cliTestData) *cli.Context {
+				return clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.BoolArgument(FlagHistory, true),
+					clitest.StringArgument(FlagInterval, "0s"),
+				)
+			},
+			errContains: "--interval 0s is shorter than 1ms, the default history.shardUpdateMinInterval",
+		},
 		{
 			name: "GetShard returns an error",
 			testSetup: func(td *cliTestData) *cli.Context {
@@ -1139,6 +1922,48 @@ func TestAdminSetShardRangeID(t *testing.T) {
 			errContains:    "",
 			expectedOutput: "Successfully updated rangeID from 123 to 133 for shard 1234.\n",
 		},
+		{
+			name: "bump current rangeID",
+			testSetup: func(td *cliTestData) *cli.Context {
+				cliCtx := clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.Int64Argument(FlagBump, 1000),
+				)
+
+				mockShardManager := persistence.NewMockShardManager(td.ctrl)
+				mockShardManager.EXPECT().GetShard(gomock.Any(), gomock.Any()).
+					Return(&persistence.GetShardResponse{
+						ShardInfo: &persistence.ShardInfo{
+							ShardID: testShardID,
+							RangeID: 123,
+						},
+					}, nil)
+				mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).
+					Do(func(ctx context.Context, req *persistence.UpdateShardRequest) {
+						assert.Equal(t, int64(123), req.PreviousRangeID)
+						assert.Equal(t, int64(1123), req.ShardInfo.RangeID)
+					}).Return(nil)
+
+				td.mockManagerFactory.EXPECT().initializeShardManager(gomock.Any()).
+					Return(mockShardManager, nil)
+
+				return cliCtx
+			},
+			expectedOutput: "Successfully updated rangeID from 123 to 1123 for shard 1234.\n",
+		},
+		{
+			name: "both RangeID and bump provided",
+			testSetup: func(td *cliTestData) *cli.Context {
+				return clitest.NewCLIContext(t, td.app,
+					clitest.IntArgument(FlagShardID, testShardID),
+					clitest.Int64Argument(FlagRangeID, 133),
+					clitest.Int64Argument(FlagBump, 1000),
+				)
+			},
+			errContains: "cannot be used together",
+		},
 		{
 			name: "all arguments provided, but UpdateShard fails",
 			testSetup: func(td *cliTestData) *cli.Context {
@@ -1298,6 +2123,35 @@ func TestAdminGetDomainIDOrName(t *testing.T) {
 			errContains:    "",
 			expectedOutput: fmt.Sprintf("domainID for domainName %v is %v\n", testDomain, testDomainID),
 		},
+		{
+			name: "DomainName provided, json format",
+			testSetup: func(td *cliTestData) *cli.Context {
+				cliCtx := clitest.NewCLIContext(
+					t,
+					td.app,
+					clitest.StringArgument(FlagDomain, testDomain),
+					clitest.StringArgument(FlagFormat, formatJSON),
+				)
+
+				mockDomainManager := persistence.NewMockDomainManager(td.ctrl)
+				mockDomainManager.EXPECT().GetDomain(
+					gomock.Any(),
+					&persistence.GetDomainRequest{Name: testDomain},
+				).Return(&persistence.GetDomainResponse{
+					Info: &persistence.DomainInfo{
+						ID:   testDomainID,
+						Name: testDomain,
+					},
+				}, nil)
+
+				td.mockManagerFactory.EXPECT().initializeDomainManager(gomock.Any()).
+					Return(mockDomainManager, nil)
+
+				return cliCtx
+			},
+			errContains:    "",
+			expectedOutput: fmt.Sprintf("{\n  \"id\": \"%v\",\n  \"name\": \"%v\"\n}\n", testDomainID, testDomain),
+		},
 		{
 			name: "DomainManager returns an error for domainID",
 			testSetup: func(td *cliTestData) *cli.Context {
@@ -1385,3 +2239,1105 @@ func TestAdminGetDomainIDOrName(t *testing.T) {
 		})
 	}
 }
+
+func TestActivityEventFilter(t *testing.T) {
+	events := []*types.HistoryEvent{
+		{ID: 1, EventType: types.EventTypeWorkflowExecutionStarted.Ptr()},
+		{ID: 5, EventType: types.EventTypeActivityTaskScheduled.Ptr(), ActivityTaskScheduledEventAttributes: &types.ActivityTaskScheduledEventAttributes{ActivityID: "act-1"}},
+		{ID: 6, EventType: types.EventTypeActivityTaskScheduled.Ptr(), ActivityTaskScheduledEventAttributes: &types.ActivityTaskScheduledEventAttributes{ActivityID: "act-2"}},
+		{ID: 7, EventType: types.EventTypeActivityTaskStarted.Ptr(), ActivityTaskStartedEventAttributes: &types.ActivityTaskStartedEventAttributes{ScheduledEventID: 5}},
+		{ID: 8, EventType: types.EventTypeActivityTaskStarted.Ptr(), ActivityTaskStartedEventAttributes: &types.ActivityTaskStartedEventAttributes{ScheduledEventID: 6}},
+		{ID: 9, EventType: types.EventTypeActivityTaskCancelRequested.Ptr(), ActivityTaskCancelRequestedEventAttributes: &types.ActivityTaskCancelRequestedEventAttributes{ActivityID: "act-1"}},
+		{ID: 10, EventType: types.EventTypeActivityTaskCanceled.Ptr(), ActivityTaskCanceledEventAttributes: &types.ActivityTaskCanceledEventAttributes{ScheduledEventID: 5}},
+		{ID: 11, EventType: types.EventTypeActivityTaskCompleted.Ptr(), ActivityTaskCompletedEventAttributes: &types.ActivityTaskCompletedEventAttributes{ScheduledEventID: 6}},
+	}
+
+	filter := newActivityEventFilter("act-1")
+	// split across batches to make sure scheduled event IDs are carried over
+	result := append(filter.filter(events[:3]), filter.filter(events[3:])...)
+
+	var ids []int64
+	for _, e := range result {
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, []int64{5, 7, 9, 10}, ids)
+}
+
+func TestBuildDecisionChain(t *testing.T) {
+	events := []*types.HistoryEvent{
+		{ID: 1, EventType: types.EventTypeWorkflowExecutionStarted.Ptr()},
+		{ID: 2, EventType: types.EventTypeDecisionTaskScheduled.Ptr(), DecisionTaskScheduledEventAttributes: &types.DecisionTaskScheduledEventAttributes{}},
+		{ID: 3, EventType: types.EventTypeDecisionTaskStarted.Ptr(), DecisionTaskStartedEventAttributes: &types.DecisionTaskStartedEventAttributes{ScheduledEventID: 2}},
+		{ID: 4, EventType: types.EventTypeDecisionTaskTimedOut.Ptr(), DecisionTaskTimedOutEventAttributes: &types.DecisionTaskTimedOutEventAttributes{ScheduledEventID: 2, TimeoutType: types.TimeoutTypeStartToClose.Ptr()}},
+		{ID: 5, EventType: types.EventTypeDecisionTaskScheduled.Ptr(), DecisionTaskScheduledEventAttributes: &types.DecisionTaskScheduledEventAttributes{Attempt: 1}},
+		{ID: 6, EventType: types.EventTypeDecisionTaskStarted.Ptr(), DecisionTaskStartedEventAttributes: &types.DecisionTaskStartedEventAttributes{ScheduledEventID: 5}},
+		{ID: 7, EventType: types.EventTypeDecisionTaskFailed.Ptr(), DecisionTaskFailedEventAttributes: &types.DecisionTaskFailedEventAttributes{ScheduledEventID: 5, Cause: types.DecisionTaskFailedCauseUnhandledDecision.Ptr()}},
+		{ID: 8, EventType: types.EventTypeActivityTaskScheduled.Ptr()},
+	}
+
+	rows := buildDecisionChain(events)
+	require.Len(t, rows, 6)
+	assert.Equal(t, int64(2), rows[0].EventID)
+	assert.Equal(t, int64(0), rows[2].Attempt)
+	assert.Equal(t, "timeout: START_TO_CLOSE", rows[2].Details)
+	assert.Equal(t, DecisionChainRow{
+		EventID:          7,
+		EventType:        types.EventTypeDecisionTaskFailed.String(),
+		Attempt:          1,
+		ScheduledEventID: 5,
+		Time:             time.Unix(0, 0),
+		Details:          "cause: " + types.DecisionTaskFailedCauseUnhandledDecision.String(),
+	}, rows[5])
+}
+
+func TestPrintPendingTimers(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var b strings.Builder
+	printPendingTimers(&b, map[string]*persistence.TimerInfo{
+		"later":   {TimerID: "later", ExpiryTime: now.Add(time.Hour)},
+		"overdue": {TimerID: "overdue", ExpiryTime: now.Add(-90 * time.Second)},
+		"soon":    {TimerID: "soon", ExpiryTime: now.Add(5 * time.Minute)},
+	}, now)
+	assert.Equal(t, "pending-timers: 3\n"+
+		"  overdue: fires at 2024-01-01T11:58:30Z (1m30s ago, overdue)\n"+
+		"  soon: fires at 2024-01-01T12:05:00Z (in 5m0s)\n"+
+		"  later: fires at 2024-01-01T13:00:00Z (in 1h0m0s)\n", b.String())
+}
+
+func TestDecodeEventPayloads(t *testing.T) {
+	event, err := json.Marshal(&shared.HistoryEvent{
+		EventId: common.Int64Ptr(5),
+		ActivityTaskScheduledEventAttributes: &shared.ActivityTaskScheduledEventAttributes{
+			ActivityId: common.StringPtr("activity"),
+			Input:      []byte("\"arg\"\n{\"count\":1}\n"),
+			Header: &shared.Header{Fields: map[string][]byte{
+				"json":   []byte(`{"a":"b"}`),
+				"binary": {0xff, 0x01},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	decoded, err := decodeEventPayloads(event)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"eventId": 5,
+		"activityTaskScheduledEventAttributes": {
+			"activityId": "activity",
+			"input": ["arg", {"count": 1}],
+			"header": {"fields": {"json": {"a": "b"}, "binary": "/wE="}}
+		}
+	}`, string(decoded))
+
+	event, err = json.Marshal(&shared.HistoryEvent{
+		ActivityTaskCompletedEventAttributes: &shared.ActivityTaskCompletedEventAttributes{
+			Result: []byte("not json"),
+		},
+	})
+	require.NoError(t, err)
+	decoded, err = decodeEventPayloads(event)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"activityTaskCompletedEventAttributes": {"result": "bm90IGpzb24="}}`, string(decoded))
+}
+
+func TestDecodeSearchAttributes(t *testing.T) {
+	decoded, err := decodeSearchAttributes(map[string][]byte{
+		"CustomKeywordField": []byte(`"keyword"`),
+		"CustomIntField":     []byte(`9007199254740993`),
+		"CustomBoolField":    []byte(`true`),
+		"CustomKeywordList":  []byte(`["a","b"]`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"CustomKeywordField": "keyword",
+		"CustomIntField":     json.Number("9007199254740993"),
+		"CustomBoolField":    true,
+		"CustomKeywordList":  []interface{}{"a", "b"},
+	}, decoded)
+
+	_, err = decodeSearchAttributes(map[string][]byte{"Broken": []byte(`{`)})
+	assert.ErrorContains(t, err, "decoding search attribute Broken")
+}
+
+func TestAdminShowWorkflow_MaxEvents(t *testing.T) {
+	td := newCLITestData(t)
+
+	serializer := persistence.NewPayloadSerializer()
+	var blobs []*persistence.DataBlob
+	for _, batch := range [][]*types.HistoryEvent{
+		{{ID: 1, EventType: types.EventTypeWorkflowExecutionStarted.Ptr()}, {ID: 2, EventType: types.EventTypeDecisionTaskScheduled.Ptr()}},
+		{{ID: 3, EventType: types.EventTypeDecisionTaskStarted.Ptr()}, {ID: 4, EventType: types.EventTypeDecisionTaskCompleted.Ptr()}},
+	} {
+		blob, err := serializer.SerializeBatchEvents(batch, common.EncodingTypeThriftRW)
+		require.NoError(t, err)
+		blobs = append(blobs, blob)
+	}
+
+	mockHistoryManager := persistence.NewMockHistoryManager(td.ctrl)
+	mockHistoryManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), gomock.Any()).
+		Return(&persistence.ReadRawHistoryBranchResponse{HistoryEventBlobs: blobs}, nil)
+	td.mockManagerFactory.EXPECT().initializeHistoryManager(gomock.Any()).Return(mockHistoryManager, nil)
+
+	outputFile := filepath.Join(t.TempDir(), "history.json")
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagTreeID, "tree-id"),
+		clitest.StringArgument(FlagBranchID, "branch-id"),
+		clitest.IntArgument(FlagMaxEvents, 3),
+		clitest.StringArgument(FlagOutputFilename, outputFile),
+	)
+	require.NoError(t, AdminShowWorkflow(cliCtx))
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	var events []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &events))
+	assert.Len(t, events, 3)
+}
+
+func TestAdminShowWorkflow_Follow(t *testing.T) {
+	td := newCLITestData(t)
+
+	serializer := persistence.NewPayloadSerializer()
+	serialize := func(events ...*types.HistoryEvent) []*persistence.DataBlob {
+		blob, err := serializer.SerializeBatchEvents(events, common.EncodingTypeThriftRW)
+		require.NoError(t, err)
+		return []*persistence.DataBlob{blob}
+	}
+
+	var minEventIDs []int64
+	mockHistoryManager := persistence.NewMockHistoryManager(td.ctrl)
+	gomock.InOrder(
+		mockHistoryManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), gomock.Any()).
+			Return(&persistence.ReadRawHistoryBranchResponse{HistoryEventBlobs: serialize(
+				&types.HistoryEvent{ID: 1, EventType: types.EventTypeWorkflowExecutionStarted.Ptr()},
+				&types.HistoryEvent{ID: 2, EventType: types.EventTypeDecisionTaskScheduled.Ptr()},
+			)}, nil),
+		mockHistoryManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *persistence.ReadHistoryBranchRequest) (*persistence.ReadRawHistoryBranchResponse, error) {
+				minEventIDs = append(minEventIDs, req.MinEventID)
+				return nil, &types.EntityNotExistsError{}
+			}),
+		mockHistoryManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *persistence.ReadHistoryBranchRequest) (*persistence.ReadRawHistoryBranchResponse, error) {
+				minEventIDs = append(minEventIDs, req.MinEventID)
+				return &persistence.ReadRawHistoryBranchResponse{HistoryEventBlobs: serialize(
+					&types.HistoryEvent{ID: 3, EventType: types.EventTypeDecisionTaskStarted.Ptr()},
+					&types.HistoryEvent{ID: 4, EventType: types.EventTypeDecisionTaskCompleted.Ptr()},
+					&types.HistoryEvent{ID: 5, EventType: types.EventTypeWorkflowExecutionCompleted.Ptr()},
+				)}, nil
+			}),
+	)
+	td.mockManagerFactory.EXPECT().initializeHistoryManager(gomock.Any()).Return(mockHistoryManager, nil)
+
+	// following stops once the events up to --max_event_id are read
+	require.NoError(t, AdminShowWorkflow(clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagTreeID, "tree-id"),
+		clitest.StringArgument(FlagBranchID, "branch-id"),
+		clitest.Int64Argument(FlagMinEventID, 1),
+		clitest.Int64Argument(FlagMaxEventID, 6),
+		clitest.BoolArgument(FlagFollow, true),
+		clitest.StringArgument(FlagInterval, "1ms"),
+	)))
+	assert.Equal(t, []int64{3, 3}, minEventIDs)
+
+	err := AdminShowWorkflow(clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagTreeID, "tree-id"),
+		clitest.StringArgument(FlagBranchID, "branch-id"),
+		clitest.BoolArgument(FlagFollow, true),
+		clitest.BoolArgument(FlagDecisionChain, true),
+	))
+	assert.ErrorContains(t, err, "--follow cannot be used with")
+}
+
+func TestAdminShowWorkflow_OutputJSONL(t *testing.T) {
+	td := newCLITestData(t)
+
+	serializer := persistence.NewPayloadSerializer()
+	var blobs []*persistence.DataBlob
+	for _, batch := range [][]*types.HistoryEvent{
+		{{ID: 1, EventType: types.EventTypeWorkflowExecutionStarted.Ptr()}, {ID: 2, EventType: types.EventTypeDecisionTaskScheduled.Ptr()}},
+		{{ID: 3, EventType: types.EventTypeDecisionTaskStarted.Ptr()}},
+	} {
+		blob, err := serializer.SerializeBatchEvents(batch, common.EncodingTypeThriftRW)
+		require.NoError(t, err)
+		blobs = append(blobs, blob)
+	}
+
+	mockHistoryManager := persistence.NewMockHistoryManager(td.ctrl)
+	mockHistoryManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), gomock.Any()).
+		Return(&persistence.ReadRawHistoryBranchResponse{HistoryEventBlobs: blobs}, nil)
+	td.mockManagerFactory.EXPECT().initializeHistoryManager(gomock.Any()).Return(mockHistoryManager, nil)
+
+	outputFile := filepath.Join(t.TempDir(), "history.jsonl")
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagTreeID, "tree-id"),
+		clitest.StringArgument(FlagBranchID, "branch-id"),
+		clitest.BoolArgument(FlagOutputJSONL, true),
+		clitest.StringArgument(FlagOutputFilename, outputFile),
+	)
+	require.NoError(t, AdminShowWorkflow(cliCtx))
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, 3)
+	for i, line := range lines {
+		var event map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		assert.Equal(t, float64(i+1), event["eventId"])
+	}
+
+	cliCtx = clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagTreeID, "tree-id"),
+		clitest.BoolArgument(FlagOutputJSONL, true),
+	)
+	assert.ErrorContains(t, AdminShowWorkflow(cliCtx), "--output_jsonl requires --output_filename")
+
+	cliCtx = clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagTreeID, "tree-id"),
+		clitest.StringArgument(FlagOutputFilename, outputFile),
+		clitest.BoolArgument(FlagAppend, true),
+	)
+	assert.ErrorContains(t, AdminShowWorkflow(cliCtx), "--append requires --output_jsonl")
+}
+
+func TestAdminShowWorkflow_BranchToken(t *testing.T) {
+	branchToken := []byte("branch-token")
+
+	t.Run("uses the decoded token", func(t *testing.T) {
+		td := newCLITestData(t)
+		blob, err := persistence.NewPayloadSerializer().SerializeBatchEvents(
+			[]*types.HistoryEvent{{ID: 1, EventType: types.EventTypeWorkflowExecutionStarted.Ptr()}},
+			common.EncodingTypeThriftRW,
+		)
+		require.NoError(t, err)
+		mockHistoryManager := persistence.NewMockHistoryManager(td.ctrl)
+		mockHistoryManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *persistence.ReadHistoryBranchRequest) (*persistence.ReadRawHistoryBranchResponse, error) {
+				assert.Equal(t, branchToken, req.BranchToken)
+				return &persistence.ReadRawHistoryBranchResponse{HistoryEventBlobs: []*persistence.DataBlob{blob}}, nil
+			})
+		td.mockManagerFactory.EXPECT().initializeHistoryManager(gomock.Any()).Return(mockHistoryManager, nil)
+
+		cliCtx := clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagBranchToken, base64.StdEncoding.EncodeToString(branchToken)),
+			clitest.StringArgument(FlagOutputFilename, filepath.Join(t.TempDir(), "history.json")),
+		)
+		assert.NoError(t, AdminShowWorkflow(cliCtx))
+	})
+	t.Run("invalid token", func(t *testing.T) {
+		td := newCLITestData(t)
+		cliCtx := clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagBranchToken, "not base64!"))
+		assert.ErrorContains(t, AdminShowWorkflow(cliCtx), "decoding branch token err")
+	})
+	t.Run("token with tree id", func(t *testing.T) {
+		td := newCLITestData(t)
+		cliCtx := clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagBranchToken, base64.StdEncoding.EncodeToString(branchToken)),
+			clitest.StringArgument(FlagTreeID, "tree-id"),
+		)
+		assert.ErrorContains(t, AdminShowWorkflow(cliCtx), "--branch_token cannot be used together with TreeID/BranchID")
+	})
+}
+
+func TestAdminShowWorkflow_WorkflowID(t *testing.T) {
+	td := newCLITestData(t)
+	branchToken := []byte("branch-token")
+	msStr, err := json.Marshal(persistence.WorkflowMutableState{
+		ExecutionInfo: &persistence.WorkflowExecutionInfo{BranchToken: branchToken},
+	})
+	require.NoError(t, err)
+	td.mockAdminClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), &types.AdminDescribeWorkflowExecutionRequest{
+		Domain:    testDomain,
+		Execution: &types.WorkflowExecution{WorkflowID: testWorkflowID, RunID: testRunID},
+	}).Return(&types.AdminDescribeWorkflowExecutionResponse{
+		ShardID:                "5",
+		MutableStateInDatabase: string(msStr),
+	}, nil)
+
+	blob, err := persistence.NewPayloadSerializer().SerializeBatchEvents(
+		[]*types.HistoryEvent{{ID: 1, EventType: types.EventTypeWorkflowExecutionStarted.Ptr()}},
+		common.EncodingTypeThriftRW,
+	)
+	require.NoError(t, err)
+	mockHistoryManager := persistence.NewMockHistoryManager(td.ctrl)
+	mockHistoryManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *persistence.ReadHistoryBranchRequest) (*persistence.ReadRawHistoryBranchResponse, error) {
+			assert.Equal(t, branchToken, req.BranchToken)
+			assert.Equal(t, 5, *req.ShardID)
+			return &persistence.ReadRawHistoryBranchResponse{HistoryEventBlobs: []*persistence.DataBlob{blob}}, nil
+		})
+	td.mockManagerFactory.EXPECT().initializeHistoryManager(gomock.Any()).Return(mockHistoryManager, nil)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+		clitest.StringArgument(FlagRunID, testRunID),
+		clitest.StringArgument(FlagOutputFilename, filepath.Join(t.TempDir(), "history.json")),
+	)
+	assert.NoError(t, AdminShowWorkflow(cliCtx))
+}
+
+func TestAdminShowWorkflow_NonCurrentBranch(t *testing.T) {
+	td := newCLITestData(t)
+	encode := func(treeID, branchID string) []byte {
+		token, err := codec.NewThriftRWEncoder().Encode(&shared.HistoryBranch{TreeID: &treeID, BranchID: &branchID})
+		require.NoError(t, err)
+		return token
+	}
+	msStr, err := json.Marshal(persistence.WorkflowMutableState{
+		ExecutionInfo: &persistence.WorkflowExecutionInfo{BranchToken: encode("tree-id", "current-branch")},
+	})
+	require.NoError(t, err)
+	td.mockAdminClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(&types.AdminDescribeWorkflowExecutionResponse{
+		ShardID:                "5",
+		MutableStateInDatabase: string(msStr),
+	}, nil)
+
+	blob, err := persistence.NewPayloadSerializer().SerializeBatchEvents(
+		[]*types.HistoryEvent{{ID: 1, EventType: types.EventTypeWorkflowExecutionStarted.Ptr()}},
+		common.EncodingTypeThriftRW,
+	)
+	require.NoError(t, err)
+	mockHistoryManager := persistence.NewMockHistoryManager(td.ctrl)
+	mockHistoryManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *persistence.ReadHistoryBranchRequest) (*persistence.ReadRawHistoryBranchResponse, error) {
+			assert.Equal(t, encode("tree-id", "old-branch"), req.BranchToken, "the requested branch is read")
+			assert.Equal(t, 5, *req.ShardID)
+			return &persistence.ReadRawHistoryBranchResponse{HistoryEventBlobs: []*persistence.DataBlob{blob}}, nil
+		})
+	td.mockManagerFactory.EXPECT().initializeHistoryManager(gomock.Any()).Return(mockHistoryManager, nil)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+		clitest.StringArgument(FlagTreeID, "tree-id"),
+		clitest.StringArgument(FlagBranchID, "old-branch"),
+		clitest.StringArgument(FlagOutputFilename, filepath.Join(t.TempDir(), "history.json")),
+	)
+	assert.NoError(t, AdminShowWorkflow(cliCtx))
+
+	var warning bytes.Buffer
+	require.NoError(t, warnIfNotCurrentBranch(&warning, encode("tree-id", "old-branch"), encode("tree-id", "current-branch")))
+	assert.Contains(t, warning.String(), "the requested branch (TreeID: tree-id, BranchID: old-branch) is not the current branch of the workflow (TreeID: tree-id, BranchID: current-branch)")
+
+	warning.Reset()
+	require.NoError(t, warnIfNotCurrentBranch(&warning, encode("tree-id", "current-branch"), encode("tree-id", "current-branch")))
+	assert.Empty(t, warning.String())
+}
+
+func TestAdminListWorkflowBranches(t *testing.T) {
+	td := newCLITestData(t)
+	encode := func(branch *shared.HistoryBranch) []byte {
+		token, err := codec.NewThriftRWEncoder().Encode(branch)
+		require.NoError(t, err)
+		return token
+	}
+	msStr, err := json.Marshal(persistence.WorkflowMutableState{
+		ExecutionInfo: &persistence.WorkflowExecutionInfo{},
+		VersionHistories: &persistence.VersionHistories{
+			CurrentVersionHistoryIndex: 1,
+			Histories: []*persistence.VersionHistory{
+				{
+					BranchToken: encode(&shared.HistoryBranch{TreeID: common.StringPtr("tree-id"), BranchID: common.StringPtr("tree-id")}),
+					Items:       []*persistence.VersionHistoryItem{{EventID: 10, Version: 1}},
+				},
+				{
+					BranchToken: encode(&shared.HistoryBranch{
+						TreeID:    common.StringPtr("tree-id"),
+						BranchID:  common.StringPtr("reset-branch"),
+						Ancestors: []*shared.HistoryBranchRange{{BranchID: common.StringPtr("tree-id"), BeginNodeID: common.Int64Ptr(1), EndNodeID: common.Int64Ptr(6)}},
+					}),
+					Items: []*persistence.VersionHistoryItem{{EventID: 5, Version: 1}, {EventID: 8, Version: 2}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	td.mockAdminClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(&types.AdminDescribeWorkflowExecutionResponse{
+		MutableStateInDatabase: string(msStr),
+	}, nil)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+		clitest.StringArgument(FlagFormat, formatJSON),
+	)
+	require.NoError(t, AdminListWorkflowBranches(cliCtx))
+
+	var rows []WorkflowBranchRow
+	require.NoError(t, json.Unmarshal([]byte(td.consoleOutput()), &rows))
+	require.Len(t, rows, 2)
+	assert.Equal(t, WorkflowBranchRow{TreeID: "tree-id", BranchID: "tree-id", LastEventID: 10, LastVersion: 1, BranchToken: rows[0].BranchToken}, rows[0])
+	assert.True(t, rows[1].Current)
+	assert.Equal(t, "reset-branch", rows[1].BranchID)
+	assert.Equal(t, "tree-id[1, 6)", rows[1].Ancestors)
+	assert.Equal(t, int64(8), rows[1].LastEventID)
+	assert.Equal(t, int64(2), rows[1].LastVersion)
+}
+
+func TestAdminDumpWorkflow(t *testing.T) {
+	branchToken, err := codec.NewThriftRWEncoder().Encode(&shared.HistoryBranch{TreeID: common.StringPtr("tree-id"), BranchID: common.StringPtr("branch-id")})
+	require.NoError(t, err)
+	msStr, err := json.Marshal(persistence.WorkflowMutableState{
+		ExecutionInfo: &persistence.WorkflowExecutionInfo{WorkflowID: testWorkflowID, RunID: testRunID, BranchToken: branchToken},
+	})
+	require.NoError(t, err)
+	history := &types.History{Events: []*types.HistoryEvent{
+		{ID: 1, EventType: types.EventTypeWorkflowExecutionStarted.Ptr()},
+		{ID: 2, EventType: types.EventTypeDecisionTaskScheduled.Ptr()},
+	}}
+	expectDump := func(td *cliTestData) {
+		td.mockAdminClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(&types.AdminDescribeWorkflowExecutionResponse{
+			ShardID:                "7",
+			HistoryAddr:            "history-host",
+			MutableStateInDatabase: string(msStr),
+		}, nil)
+		td.mockFrontendClient.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *types.GetWorkflowExecutionHistoryRequest, _ ...yarpc.CallOption) (*types.GetWorkflowExecutionHistoryResponse, error) {
+				assert.Equal(t, testRunID, req.Execution.RunID)
+				return &types.GetWorkflowExecutionHistoryResponse{History: history}, nil
+			})
+	}
+	checkDump := func(t *testing.T, dump WorkflowDump) {
+		assert.Equal(t, testWorkflowID, dump.WorkflowID)
+		assert.Equal(t, testRunID, dump.RunID)
+		assert.Equal(t, "7", dump.ShardID)
+		assert.Equal(t, "history-host", dump.HistoryAddr)
+		require.Len(t, dump.Branches, 1)
+		assert.Equal(t, "branch-id", dump.Branches[0].BranchID)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		td := newCLITestData(t)
+		expectDump(td)
+		outputFile := filepath.Join(t.TempDir(), "dump.json")
+		require.NoError(t, AdminDumpWorkflow(clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagDomain, testDomain),
+			clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+			clitest.StringArgument(FlagOutputFilename, outputFile),
+		)))
+		assert.Equal(t, fmt.Sprintf("Dumped 2 events of %v %v to %v\n", testWorkflowID, testRunID, outputFile), td.consoleOutput())
+
+		data, err := os.ReadFile(outputFile)
+		require.NoError(t, err)
+		var dump WorkflowDump
+		require.NoError(t, json.Unmarshal(data, &dump))
+		checkDump(t, dump)
+		assert.Equal(t, history, dump.History)
+		assert.Contains(t, string(dump.MutableState), testRunID)
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		td := newCLITestData(t)
+		expectDump(td)
+		outputFile := filepath.Join(t.TempDir(), "dump.zip")
+		require.NoError(t, AdminDumpWorkflow(clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagDomain, testDomain),
+			clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+			clitest.StringArgument(FlagOutputFilename, outputFile),
+		)))
+
+		zr, err := zip.OpenReader(outputFile)
+		require.NoError(t, err)
+		defer zr.Close()
+		entries := map[string][]byte{}
+		for _, f := range zr.File {
+			r, err := f.Open()
+			require.NoError(t, err)
+			entries[f.Name], err = io.ReadAll(r)
+			require.NoError(t, err)
+			r.Close()
+		}
+		require.Len(t, entries, 3)
+		var dump WorkflowDump
+		require.NoError(t, json.Unmarshal(entries["workflow.json"], &dump))
+		checkDump(t, dump)
+		assert.Nil(t, dump.History)
+		var dumpedHistory types.History
+		require.NoError(t, json.Unmarshal(entries["history.json"], &dumpedHistory))
+		assert.Equal(t, history, &dumpedHistory)
+		assert.Contains(t, string(entries["mutable_state.json"]), testRunID)
+	})
+}
+
+func TestAdminGetDomainIDOrName_InputFile(t *testing.T) {
+	td := newCLITestData(t)
+	domainID := "c1a8ba9a-4f06-4b04-9bce-2e9cd44e4b3c"
+	inputFile := filepath.Join(t.TempDir(), "domains.txt")
+	require.NoError(t, os.WriteFile(inputFile, []byte(testDomain+"\n\n"+domainID+"\nmissing-domain\n"), 0600))
+
+	mockDomainManager := persistence.NewMockDomainManager(td.ctrl)
+	domainResponse := &persistence.GetDomainResponse{Info: &persistence.DomainInfo{ID: domainID, Name: testDomain}}
+	mockDomainManager.EXPECT().GetDomain(gomock.Any(), &persistence.GetDomainRequest{Name: testDomain}).Return(domainResponse, nil)
+	mockDomainManager.EXPECT().GetDomain(gomock.Any(), &persistence.GetDomainRequest{ID: domainID}).Return(domainResponse, nil)
+	mockDomainManager.EXPECT().GetDomain(gomock.Any(), &persistence.GetDomainRequest{Name: "missing-domain"}).
+		Return(nil, &types.EntityNotExistsError{Message: "domain not found"})
+	td.mockManagerFactory.EXPECT().initializeDomainManager(gomock.Any()).Return(mockDomainManager, nil)
+
+	cliCtx := clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagInputFile, inputFile))
+	err := AdminGetDomainIDOrName(cliCtx)
+	assert.ErrorContains(t, err, "Failed to resolve 1 of 3 domains")
+	assert.Equal(t, fmt.Sprintf("%[1]v\t%[2]v\t%[1]v\t\n%[2]v\t%[2]v\t%[1]v\t\nmissing-domain\t\t\tdomain not found\n", testDomain, domainID), td.consoleOutput())
+}
+
+func TestAdminDescribeShard_Range(t *testing.T) {
+	td := newCLITestData(t)
+	mockShardManager := persistence.NewMockShardManager(td.ctrl)
+	mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: 1}).
+		Return(&persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{ShardID: 1}}, nil)
+	mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: 2}).
+		Return(nil, errors.New("critical error"))
+	td.mockManagerFactory.EXPECT().initializeShardManager(gomock.Any()).Return(mockShardManager, nil)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.IntArgument(FlagLowerShardBound, 1),
+		clitest.IntArgument(FlagUpperShardBound, 2),
+	)
+	err := AdminDescribeShard(cliCtx)
+	assert.ErrorContains(t, err, "1 of 2 shards failed")
+	assert.Contains(t, td.consoleOutput(), "Failed to describe shard 2: critical error\n")
+	assert.Contains(t, td.consoleOutput(), "Described 1 of 2 shards, 1 failed.\n")
+}
+
+func TestAdminDescribeShard_RangePastFirstContext(t *testing.T) {
+	td := newCLITestData(t)
+	mockShardManager := persistence.NewMockShardManager(td.ctrl)
+	var firstCtx context.Context
+	mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: 1}).
+		DoAndReturn(func(ctx context.Context, _ *persistence.GetShardRequest) (*persistence.GetShardResponse, error) {
+			firstCtx = ctx
+			return &persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{ShardID: 1}}, nil
+		})
+	mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: 2}).
+		DoAndReturn(func(ctx context.Context, _ *persistence.GetShardRequest) (*persistence.GetShardResponse, error) {
+			// the context of the first shard is over, the second shard must not depend on it
+			assert.Error(t, firstCtx.Err())
+			assert.NoError(t, ctx.Err())
+			return &persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{ShardID: 2}}, nil
+		})
+	td.mockManagerFactory.EXPECT().initializeShardManager(gomock.Any()).Return(mockShardManager, nil)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.IntArgument(FlagLowerShardBound, 1),
+		clitest.IntArgument(FlagUpperShardBound, 2),
+	)
+	require.NoError(t, AdminDescribeShard(cliCtx))
+	assert.Contains(t, td.consoleOutput(), "Described 2 of 2 shards, 0 failed.\n")
+}
+
+func TestAdminResetShardAckLevel(t *testing.T) {
+	shard := func() *persistence.GetShardResponse {
+		return &persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{
+			ShardID:                 testShardID,
+			Owner:                   "host-abc",
+			RangeID:                 10,
+			ClusterTransferAckLevel: map[string]int64{"cluster-a": 100},
+			TransferAckLevel:        100,
+			TransferProcessingQueueStates: &types.ProcessingQueueStates{StatesByCluster: map[string][]*types.ProcessingQueueState{
+				"cluster-a": {{Level: common.Int32Ptr(0), AckLevel: common.Int64Ptr(100), MaxLevel: common.Int64Ptr(200)}},
+				"cluster-b": {{Level: common.Int32Ptr(0), AckLevel: common.Int64Ptr(300), MaxLevel: common.Int64Ptr(400)}},
+			}},
+		}}
+	}
+	tests := []struct {
+		name           string
+		args           []clitest.CliArgument
+		input          string
+		update         func(t *testing.T, req *persistence.UpdateShardRequest)
+		errContains    string
+		outputContains []string
+	}{
+		{
+			name: "transfer ack level",
+			args: []clitest.CliArgument{
+				clitest.Int64Argument(FlagTransferAckLevel, 50),
+				clitest.BoolArgument(FlagYes, true),
+			},
+			update: func(t *testing.T, req *persistence.UpdateShardRequest) {
+				assert.Equal(t, int64(10), req.PreviousRangeID)
+				assert.Equal(t, int64(11), req.ShardInfo.RangeID)
+				assert.Equal(t, "", req.ShardInfo.Owner)
+				assert.Equal(t, map[string]int64{"cluster-a": 50}, req.ShardInfo.ClusterTransferAckLevel)
+				assert.Equal(t, int64(100), req.ShardInfo.TransferAckLevel)
+				assert.Equal(t, map[string][]*types.ProcessingQueueState{
+					"cluster-a": {{
+						Level:        common.Int32Ptr(0),
+						AckLevel:     common.Int64Ptr(50),
+						MaxLevel:     common.Int64Ptr(math.MaxInt64),
+						DomainFilter: &types.DomainFilter{ReverseMatch: true},
+					}},
+					"cluster-b": {{Level: common.Int32Ptr(0), AckLevel: common.Int64Ptr(300), MaxLevel: common.Int64Ptr(400)}},
+				}, req.ShardInfo.TransferProcessingQueueStates.StatesByCluster)
+			},
+			outputContains: []string{
+				"Shard 1234 transfer ack level of cluster cluster-a: 100\n",
+				"Shard 1234 transfer ack level of cluster cluster-a: 50, rangeID updated from 10 to 11\n",
+			},
+		},
+		{
+			name: "timer ack level confirmed",
+			args: []clitest.CliArgument{
+				clitest.StringArgument(FlagTimerAckLevel, "2024-01-02T03:04:05Z"),
+				clitest.BoolArgument(FlagCurrentCluster, true),
+			},
+			input: "y\n",
+			update: func(t *testing.T, req *persistence.UpdateShardRequest) {
+				ackLevel := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+				assert.Equal(t, map[string]time.Time{"cluster-a": ackLevel}, req.ShardInfo.ClusterTimerAckLevel)
+				assert.Equal(t, ackLevel.UnixNano(), req.ShardInfo.TimerProcessingQueueStates.StatesByCluster["cluster-a"][0].GetAckLevel())
+				assert.Equal(t, ackLevel, req.ShardInfo.TimerAckLevel)
+			},
+			outputContains: []string{
+				"Shard 1234 timer ack level of cluster cluster-a: 0001-01-01T00:00:00Z\n",
+				"Shard 1234 timer ack level of cluster cluster-a: 2024-01-02T03:04:05Z, rangeID updated from 10 to 11\n",
+			},
+		},
+		{
+			name: "cancelled",
+			args: []clitest.CliArgument{
+				clitest.Int64Argument(FlagTransferAckLevel, 50),
+			},
+			input:       "n\n",
+			errContains: "Ack level reset cancelled",
+		},
+		{
+			name: "both ack levels",
+			args: []clitest.CliArgument{
+				clitest.Int64Argument(FlagTransferAckLevel, 50),
+				clitest.StringArgument(FlagTimerAckLevel, "2024-01-02T03:04:05Z"),
+			},
+			errContains: "exactly one of --transfer_ack_level and --timer_ack_level is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newCLITestData(t)
+			td.ioHandler.input = strings.NewReader(tt.input)
+			if !strings.HasPrefix(tt.errContains, "exactly one") {
+				mockShardManager := persistence.NewMockShardManager(td.ctrl)
+				mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: testShardID}).Return(shard(), nil)
+				if tt.update != nil {
+					mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).
+						Do(func(_ context.Context, req *persistence.UpdateShardRequest) { tt.update(t, req) }).
+						Return(nil)
+				}
+				td.mockManagerFactory.EXPECT().initializeShardManager(gomock.Any()).Return(mockShardManager, nil)
+			}
+			args := append([]clitest.CliArgument{
+				clitest.IntArgument(FlagShardID, testShardID),
+				clitest.StringArgument(FlagCluster, "cluster-a"),
+			}, tt.args...)
+			err := AdminResetShardAckLevel(clitest.NewCLIContext(t, td.app, args...))
+			if tt.errContains != "" {
+				assert.ErrorContains(t, err, tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			for _, out := range tt.outputContains {
+				assert.Contains(t, td.consoleOutput(), out)
+			}
+		})
+	}
+}
+
+func TestAdminSetShardRangeID_Range(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		td := newCLITestData(t)
+		mockShardManager := persistence.NewMockShardManager(td.ctrl)
+		for _, shardID := range []int{3, 4} {
+			mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: shardID}).
+				Return(&persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{ShardID: shardID, RangeID: 10}}, nil)
+		}
+		mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		td.mockManagerFactory.EXPECT().initializeShardManager(gomock.Any()).Return(mockShardManager, nil)
+
+		cliCtx := clitest.NewCLIContext(t, td.app,
+			clitest.IntArgument(FlagLowerShardBound, 3),
+			clitest.IntArgument(FlagUpperShardBound, 4),
+			clitest.Int64Argument(FlagRangeID, 20),
+			clitest.IntArgument(FlagMaxShards, 10),
+			clitest.BoolArgument(FlagYes, true),
+		)
+		require.NoError(t, AdminSetShardRangeID(cliCtx))
+		assert.Equal(t, "Successfully updated rangeID from 10 to 20 for shard 3.\n"+
+			"Successfully updated rangeID from 10 to 20 for shard 4.\n"+
+			"Updated 2 of 2 shards, 0 failed.\n", td.consoleOutput())
+	})
+	t.Run("past the first context", func(t *testing.T) {
+		td := newCLITestData(t)
+		mockShardManager := persistence.NewMockShardManager(td.ctrl)
+		var firstCtx context.Context
+		mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: 3}).
+			DoAndReturn(func(ctx context.Context, _ *persistence.GetShardRequest) (*persistence.GetShardResponse, error) {
+				firstCtx = ctx
+				return &persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{ShardID: 3, RangeID: 10}}, nil
+			})
+		mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: 4}).
+			DoAndReturn(func(ctx context.Context, _ *persistence.GetShardRequest) (*persistence.GetShardResponse, error) {
+				// the context of the first shard is over, the second shard must not depend on it
+				assert.Error(t, firstCtx.Err())
+				assert.NoError(t, ctx.Err())
+				return &persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{ShardID: 4, RangeID: 10}}, nil
+			})
+		mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		td.mockManagerFactory.EXPECT().initializeShardManager(gomock.Any()).Return(mockShardManager, nil)
+
+		cliCtx := clitest.NewCLIContext(t, td.app,
+			clitest.IntArgument(FlagLowerShardBound, 3),
+			clitest.IntArgument(FlagUpperShardBound, 4),
+			clitest.Int64Argument(FlagRangeID, 20),
+			clitest.IntArgument(FlagMaxShards, 10),
+			clitest.BoolArgument(FlagYes, true),
+		)
+		require.NoError(t, AdminSetShardRangeID(cliCtx))
+		assert.Contains(t, td.consoleOutput(), "Updated 2 of 2 shards, 0 failed.\n")
+	})
+	t.Run("without --yes", func(t *testing.T) {
+		td := newCLITestData(t)
+		cliCtx := clitest.NewCLIContext(t, td.app,
+			clitest.IntArgument(FlagLowerShardBound, 3),
+			clitest.IntArgument(FlagUpperShardBound, 4),
+			clitest.Int64Argument(FlagRangeID, 20),
+			clitest.IntArgument(FlagMaxShards, 10),
+		)
+		assert.ErrorContains(t, AdminSetShardRangeID(cliCtx), "requires --yes")
+	})
+	t.Run("more than max shards", func(t *testing.T) {
+		td := newCLITestData(t)
+		cliCtx := clitest.NewCLIContext(t, td.app,
+			clitest.IntArgument(FlagLowerShardBound, 0),
+			clitest.IntArgument(FlagUpperShardBound, 10),
+			clitest.Int64Argument(FlagRangeID, 20),
+			clitest.IntArgument(FlagMaxShards, 10),
+			clitest.BoolArgument(FlagYes, true),
+		)
+		assert.ErrorContains(t, AdminSetShardRangeID(cliCtx), "Shard range contains 11 shards, more than --max_shards 10")
+	})
+	t.Run("missing upper bound", func(t *testing.T) {
+		td := newCLITestData(t)
+		cliCtx := clitest.NewCLIContext(t, td.app,
+			clitest.IntArgument(FlagLowerShardBound, 3),
+			clitest.Int64Argument(FlagRangeID, 20),
+		)
+		assert.ErrorContains(t, AdminSetShardRangeID(cliCtx), "both --lower_shard_bound and --upper_shard_bound are required")
+	})
+}
+
+func TestAdminListShards(t *testing.T) {
+	td := newCLITestData(t)
+	mockShardManager := persistence.NewMockShardManager(td.ctrl)
+	for shardID, owner := range []string{"host-a", "host-b", "host-a"} {
+		mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: shardID}).
+			Return(&persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{ShardID: shardID, Owner: owner, RangeID: int64(shardID + 10)}}, nil)
+	}
+	td.mockManagerFactory.EXPECT().initializeShardManager(gomock.Any()).Return(mockShardManager, nil)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.IntArgument(FlagNumberOfShards, 3),
+		clitest.StringArgument(FlagFilterOwner, "host-a"),
+		clitest.StringArgument(FlagFormat, formatJSON),
+	)
+	require.NoError(t, AdminListShards(cliCtx))
+
+	var rows []ShardInfoRow
+	require.NoError(t, json.Unmarshal([]byte(td.consoleOutput()), &rows))
+	require.Len(t, rows, 2)
+	assert.Equal(t, 0, rows[0].ShardID)
+	assert.Equal(t, 2, rows[1].ShardID)
+	assert.Equal(t, int64(12), rows[1].RangeID)
+
+	td = newCLITestData(t)
+	mockShardManager = persistence.NewMockShardManager(td.ctrl)
+	mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: 0}).Return(nil, errors.New("timeout"))
+	mockShardManager.EXPECT().GetShard(gomock.Any(), &persistence.GetShardRequest{ShardID: 1}).
+		Return(&persistence.GetShardResponse{ShardInfo: &persistence.ShardInfo{ShardID: 1, Owner: "host-b"}}, nil)
+	td.mockManagerFactory.EXPECT().initializeShardManager(gomock.Any()).Return(mockShardManager, nil)
+
+	cliCtx = clitest.NewCLIContext(t, td.app, clitest.IntArgument(FlagNumberOfShards, 2), clitest.StringArgument(FlagFormat, formatJSON))
+	assert.ErrorContains(t, AdminListShards(cliCtx), "1 of 2 shards failed")
+	require.NoError(t, json.Unmarshal([]byte(td.consoleOutput()), &rows))
+	require.Len(t, rows, 1)
+	assert.Equal(t, 1, rows[0].ShardID)
+}
+
+func TestEventCategory(t *testing.T) {
+	assert.Equal(t, eventCategoryWorkflow, eventCategory(types.EventTypeWorkflowExecutionContinuedAsNew))
+	assert.Equal(t, eventCategoryDecision, eventCategory(types.EventTypeDecisionTaskTimedOut))
+	assert.Equal(t, eventCategoryActivity, eventCategory(types.EventTypeRequestCancelActivityTaskFailed))
+	assert.Equal(t, eventCategoryTimer, eventCategory(types.EventTypeCancelTimerFailed))
+	assert.Equal(t, eventCategorySignal, eventCategory(types.EventTypeExternalWorkflowExecutionSignaled))
+	assert.Equal(t, eventCategoryChildWorkflow, eventCategory(types.EventTypeStartChildWorkflowExecutionFailed))
+	assert.Equal(t, "", eventCategory(types.EventTypeMarkerRecorded))
+}
+
+func TestBuildShardAckLevels(t *testing.T) {
+	timerAckLevel := time.Unix(1700000000, 0)
+	rows := buildShardAckLevels(&persistence.ShardInfo{
+		ClusterTransferAckLevel: map[string]int64{"cluster-b": 10, "cluster-a": 20},
+		ClusterTimerAckLevel:    map[string]time.Time{"cluster-a": timerAckLevel},
+		ClusterReplicationLevel: map[string]int64{"cluster-b": 5},
+		ReplicationDLQAckLevel:  map[string]int64{"cluster-c": 7},
+	})
+	assert.Equal(t, []ShardAckLevelRow{
+		{Cluster: "cluster-a", TransferAckLevel: 20, TimerAckLevel: timerAckLevel},
+		{Cluster: "cluster-b", TransferAckLevel: 10, ReplicationLevel: 5},
+		{Cluster: "cluster-c", ReplicationDLQAckLevel: 7},
+	}, rows)
+}
+
+func TestAdminDeleteWorkflow_Scoped(t *testing.T) {
+	branchToken, err := codec.NewThriftRWEncoder().Encode(&shared.HistoryBranch{TreeID: common.StringPtr("tree-id"), BranchID: common.StringPtr("branch-id")})
+	require.NoError(t, err)
+	msStr, err := json.Marshal(persistence.WorkflowMutableState{
+		ExecutionInfo: &persistence.WorkflowExecutionInfo{DomainID: testDomainID, WorkflowID: testWorkflowID, RunID: testRunID, BranchToken: branchToken},
+	})
+	require.NoError(t, err)
+	expectDescribe := func(td *cliTestData) {
+		td.mockAdminClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(&types.AdminDescribeWorkflowExecutionResponse{
+			ShardID:                "1",
+			MutableStateInDatabase: string(msStr),
+		}, nil)
+	}
+
+	t.Run("history only", func(t *testing.T) {
+		td := newCLITestData(t)
+		expectDescribe(td)
+		mockHistoryManager := persistence.NewMockHistoryManager(td.ctrl)
+		mockHistoryManager.EXPECT().DeleteHistoryBranch(gomock.Any(), gomock.Any()).Return(nil)
+		mockHistoryManager.EXPECT().Close()
+		td.mockManagerFactory.EXPECT().initializeHistoryManager(gomock.Any()).Return(mockHistoryManager, nil)
+
+		assert.NoError(t, AdminDeleteWorkflow(clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagDomain, testDomain),
+			clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+			clitest.StringArgument(FlagRunID, testRunID),
+			clitest.BoolArgument(FlagHistoryOnly, true),
+		)))
+	})
+
+	t.Run("mutable state only", func(t *testing.T) {
+		td := newCLITestData(t)
+		expectDescribe(td)
+		mockExecutionManager := persistence.NewMockExecutionManager(td.ctrl)
+		mockExecutionManager.EXPECT().DeleteWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil)
+		mockExecutionManager.EXPECT().GetCurrentExecution(gomock.Any(), gomock.Any()).Return(&persistence.GetCurrentExecutionResponse{RunID: testRunID}, nil)
+		mockExecutionManager.EXPECT().DeleteCurrentWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil)
+		td.mockManagerFactory.EXPECT().initializeExecutionManager(gomock.Any(), 1).Return(mockExecutionManager, nil)
+
+		assert.NoError(t, AdminDeleteWorkflow(clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagDomain, testDomain),
+			clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+			clitest.StringArgument(FlagRunID, testRunID),
+			clitest.BoolArgument(FlagMutableStateOnly, true),
+		)))
+	})
+
+	t.Run("both set", func(t *testing.T) {
+		td := newCLITestData(t)
+		err := AdminDeleteWorkflow(clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagDomain, testDomain),
+			clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+			clitest.BoolArgument(FlagHistoryOnly, true),
+			clitest.BoolArgument(FlagMutableStateOnly, true),
+		))
+		assert.ErrorContains(t, err, "only one of --history_only, --mutable_state_only and --current_row_only can be used")
+	})
+
+	t.Run("history only with a branch token", func(t *testing.T) {
+		td := newCLITestData(t)
+		mockHistoryManager := persistence.NewMockHistoryManager(td.ctrl)
+		mockHistoryManager.EXPECT().DeleteHistoryBranch(gomock.Any(), &persistence.DeleteHistoryBranchRequest{
+			BranchToken: branchToken,
+			ShardID:     common.IntPtr(1),
+			DomainName:  testDomain,
+		}).Return(nil)
+		mockHistoryManager.EXPECT().Close()
+		td.mockManagerFactory.EXPECT().initializeHistoryManager(gomock.Any()).Return(mockHistoryManager, nil)
+
+		assert.NoError(t, AdminDeleteWorkflow(clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagDomain, testDomain),
+			clitest.StringArgument(FlagBranchToken, base64.StdEncoding.EncodeToString(branchToken)),
+			clitest.IntArgument(FlagShardID, 1),
+			clitest.BoolArgument(FlagHistoryOnly, true),
+		)))
+	})
+
+	t.Run("branch token without shard", func(t *testing.T) {
+		td := newCLITestData(t)
+		err := AdminDeleteWorkflow(clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagDomain, testDomain),
+			clitest.StringArgument(FlagTreeID, "tree-id"),
+			clitest.StringArgument(FlagBranchID, "branch-id"),
+			clitest.BoolArgument(FlagHistoryOnly, true),
+		))
+		assert.ErrorContains(t, err, "--shard_id is required")
+	})
+
+	t.Run("branch token without history only", func(t *testing.T) {
+		td := newCLITestData(t)
+		err := AdminDeleteWorkflow(clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagDomain, testDomain),
+			clitest.StringArgument(FlagTreeID, "tree-id"),
+			clitest.StringArgument(FlagBranchID, "branch-id"),
+			clitest.IntArgument(FlagShardID, 1),
+		))
+		assert.ErrorContains(t, err, "can only be used with --history_only")
+	})
+
+	t.Run("current row only", func(t *testing.T) {
+		td := newCLITestData(t)
+		td.mockFrontendClient.EXPECT().DescribeDomain(gomock.Any(), &types.DescribeDomainRequest{Name: common.StringPtr(testDomain)}).
+			Return(&types.DescribeDomainResponse{DomainInfo: &types.DomainInfo{UUID: testDomainID}}, nil)
+		mockExecutionManager := persistence.NewMockExecutionManager(td.ctrl)
+		mockExecutionManager.EXPECT().GetCurrentExecution(gomock.Any(), gomock.Any()).Return(&persistence.GetCurrentExecutionResponse{RunID: testRunID}, nil)
+		mockExecutionManager.EXPECT().DeleteCurrentWorkflowExecution(gomock.Any(), &persistence.DeleteCurrentWorkflowExecutionRequest{
+			DomainID:   testDomainID,
+			WorkflowID: testWorkflowID,
+			RunID:      testRunID,
+		}).Return(nil)
+		td.mockManagerFactory.EXPECT().initializeExecutionManager(gomock.Any(), common.WorkflowIDToHistoryShard(testWorkflowID, 16)).Return(mockExecutionManager, nil)
+
+		assert.NoError(t, AdminDeleteWorkflow(clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagDomain, testDomain),
+			clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+			clitest.StringArgument(FlagRunID, testRunID),
+			clitest.IntArgument(FlagNumberOfShards, 16),
+			clitest.BoolArgument(FlagCurrentRowOnly, true),
+		)))
+	})
+
+	t.Run("current row only without shard", func(t *testing.T) {
+		td := newCLITestData(t)
+		err := AdminDeleteWorkflow(clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagDomain, testDomain),
+			clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+			clitest.StringArgument(FlagRunID, testRunID),
+			clitest.BoolArgument(FlagCurrentRowOnly, true),
+		))
+		assert.ErrorContains(t, err, "--shard_id or --number_of_shards is required")
+	})
+
+	t.Run("with remote", func(t *testing.T) {
+		td := newCLITestData(t)
+		err := AdminDeleteWorkflow(clitest.NewCLIContext(t, td.app,
+			clitest.StringArgument(FlagDomain, testDomain),
+			clitest.StringArgument(FlagWorkflowID, testWorkflowID),
+			clitest.BoolArgument(FlagHistoryOnly, true),
+			clitest.BoolArgument(FlagRemote, true),
+		))
+		assert.ErrorContains(t, err, "are not supported with --remote")
+	})
+}
+
+func TestDeleteCurrentExecution(t *testing.T) {
+	currentRequest := &persistence.GetCurrentExecutionRequest{DomainID: testDomainID, WorkflowID: testWorkflowID, DomainName: testDomain}
+	deleteRequest := &persistence.DeleteCurrentWorkflowExecutionRequest{DomainID: testDomainID, WorkflowID: testWorkflowID, RunID: testRunID}
+	tests := []struct {
+		name        string
+		force       bool
+		mockFn      func(m *persistence.MockExecutionManager)
+		errContains string
+	}{
+		{
+			name: "current row points to the run",
+			mockFn: func(m *persistence.MockExecutionManager) {
+				m.EXPECT().GetCurrentExecution(gomock.Any(), currentRequest).Return(&persistence.GetCurrentExecutionResponse{RunID: testRunID}, nil)
+				m.EXPECT().DeleteCurrentWorkflowExecution(gomock.Any(), deleteRequest).Return(nil)
+			},
+		},
+		{
+			name: "current row points to another run",
+			mockFn: func(m *persistence.MockExecutionManager) {
+				m.EXPECT().GetCurrentExecution(gomock.Any(), currentRequest).Return(&persistence.GetCurrentExecutionResponse{RunID: "newer-run-id"}, nil)
+			},
+		},
+		{
+			name:  "current row points to another run with force",
+			force: true,
+			mockFn: func(m *persistence.MockExecutionManager) {
+				m.EXPECT().GetCurrentExecution(gomock.Any(), currentRequest).Return(&persistence.GetCurrentExecutionResponse{RunID: "newer-run-id"}, nil)
+				m.EXPECT().DeleteCurrentWorkflowExecution(gomock.Any(), deleteRequest).Return(nil)
+			},
+		},
+		{
+			name: "current row not found",
+			mockFn: func(m *persistence.MockExecutionManager) {
+				m.EXPECT().GetCurrentExecution(gomock.Any(), currentRequest).Return(nil, &types.EntityNotExistsError{})
+			},
+		},
+		{
+			name: "get current row fails",
+			mockFn: func(m *persistence.MockExecutionManager) {
+				m.EXPECT().GetCurrentExecution(gomock.Any(), currentRequest).Return(nil, errors.New("critical error"))
+			},
+			errContains: "get current row failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exeStore := persistence.NewMockExecutionManager(gomock.NewController(t))
+			tt.mockFn(exeStore)
+
+			err := deleteCurrentExecution(context.Background(), exeStore, testDomainID, testDomain, testWorkflowID, testRunID, tt.force, false)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestAdminCountHistoryEvents(t *testing.T) {
+	td := newCLITestData(t)
+	serializer := persistence.NewPayloadSerializer()
+	var blobs []*persistence.DataBlob
+	for _, batch := range [][]*types.HistoryEvent{
+		{{ID: 1, EventType: types.EventTypeWorkflowExecutionStarted.Ptr()}, {ID: 2, EventType: types.EventTypeDecisionTaskScheduled.Ptr()}},
+		{{ID: 3, EventType: types.EventTypeDecisionTaskStarted.Ptr()}},
+	} {
+		blob, err := serializer.SerializeBatchEvents(batch, common.EncodingTypeThriftRW)
+		require.NoError(t, err)
+		blobs = append(blobs, blob)
+	}
+
+	mockHistoryManager := persistence.NewMockHistoryManager(td.ctrl)
+	mockHistoryManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *persistence.ReadHistoryBranchRequest) (*persistence.ReadRawHistoryBranchResponse, error) {
+			assert.Nil(t, req.NextPageToken)
+			return &persistence.ReadRawHistoryBranchResponse{HistoryEventBlobs: blobs[:1], NextPageToken: []byte("next")}, nil
+		})
+	mockHistoryManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *persistence.ReadHistoryBranchRequest) (*persistence.ReadRawHistoryBranchResponse, error) {
+			assert.Equal(t, []byte("next"), req.NextPageToken)
+			return &persistence.ReadRawHistoryBranchResponse{HistoryEventBlobs: blobs[1:]}, nil
+		})
+	mockHistoryManager.EXPECT().Close()
+	td.mockManagerFactory.EXPECT().initializeHistoryManager(gomock.Any()).Return(mockHistoryManager, nil)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagTreeID, "tree-id"),
+		clitest.StringArgument(FlagBranchID, "branch-id"),
+	)
+	require.NoError(t, AdminCountHistoryEvents(cliCtx))
+	assert.Equal(t, fmt.Sprintf("batches: 2\nevents: 3\nblob bytes: %v\nmin event ID: 1\nmax event ID: 3\n", len(blobs[0].Data)+len(blobs[1].Data)), td.consoleOutput())
+}
+
+func TestFilterEventsByType(t *testing.T) {
+	eventTypes, err := parseEventTypes([]string{"DecisionTaskFailed", "ActivityTaskTimedOut"})
+	require.NoError(t, err)
+	events := filterEventsByType([]*types.HistoryEvent{
+		{ID: 1, EventType: types.EventTypeWorkflowExecutionStarted.Ptr()},
+		{ID: 2, EventType: types.EventTypeDecisionTaskFailed.Ptr()},
+		{ID: 3, EventType: types.EventTypeActivityTaskTimedOut.Ptr()},
+		{ID: 4, EventType: types.EventTypeActivityTaskCompleted.Ptr()},
+	}, eventTypes)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(2), events[0].ID)
+	assert.Equal(t, int64(3), events[1].ID)
+
+	_, err = parseEventTypes([]string{"NotAnEventType"})
+	assert.Error(t, err)
+}
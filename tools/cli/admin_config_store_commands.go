@@ -93,7 +93,7 @@ func AdminGetDynamicConfig(c *cli.Context) error {
 	if umVal == nil {
 		fmt.Printf("No values stored for specified dynamic config.\n")
 	} else {
-		prettyPrintJSONObject(getDeps(c).Output(), umVal)
+		printObject(c, umVal)
 	}
 
 	return nil
@@ -220,7 +220,7 @@ func AdminListDynamicConfig(c *cli.Context) error {
 			}
 			cliEntries = append(cliEntries, cliEntry)
 		}
-		prettyPrintJSONObject(getDeps(c).Output(), cliEntries)
+		printObject(c, cliEntries)
 	}
 	return nil
 }
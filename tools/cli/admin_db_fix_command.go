@@ -0,0 +1,234 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/reconciliation/entity"
+	"github.com/uber/cadence/common/reconciliation/invariant"
+	"github.com/uber/cadence/common/reconciliation/store"
+	"github.com/uber/cadence/service/worker/scanner/executions"
+	"github.com/uber/cadence/tools/common/commoncli"
+)
+
+// AdminDBFix is the command to fix the corrupted executions found by `admin db scan`.
+// Input is the JSON stream of scan results provided via STDIN or a file, healthy executions are skipped.
+func AdminDBFix(c *cli.Context) (err error) {
+	scanType, err := executions.ScanTypeString(c.String(FlagScanType))
+	if err != nil {
+		return commoncli.Problem(fmt.Sprintf("unknown scan type, valid scan types are %q", executions.ScanTypeStrings()), err)
+	}
+	collectionSlice := c.StringSlice(FlagInvariantCollection)
+
+	var collections []invariant.Collection
+	for _, v := range collectionSlice {
+		collection, err := invariant.CollectionString(v)
+		if err != nil {
+			return commoncli.Problem(fmt.Sprintf("unknown invariant collection, valid collections are %q", invariant.CollectionStrings()), err)
+		}
+		collections = append(collections, collection)
+	}
+
+	logger := zap.NewNop()
+	if c.Bool(FlagVerbose) {
+		logger, err = zap.NewDevelopment()
+		if err != nil {
+			// probably impossible with default config
+			return commoncli.Problem("could not construct logger", err)
+		}
+	}
+
+	if names := c.StringSlice(FlagInvariant); len(names) > 0 {
+		collections, err = filterCollections(scanType, collections, names)
+		if err != nil {
+			return commoncli.Problem("invalid invariant", err)
+		}
+	}
+	invariants := scanType.ToInvariants(collections, logger)
+	if len(invariants) < 1 {
+		return noInvariantsProblem(scanType, collectionSlice)
+	}
+
+	input, err := openScanInput(c)
+	if err != nil {
+		return commoncli.Problem("Input file not found", err)
+	}
+	defer input.Close()
+
+	blob := scanType.ToBlobstoreEntity()
+	dec := json.NewDecoder(input)
+	var data []*store.ScanOutputEntity
+	for {
+		soe := &store.ScanOutputEntity{
+			Execution: blob.Clone(),
+		}
+		if err := dec.Decode(soe); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return commoncli.Problem("Error decoding input file", err)
+		}
+		data = append(data, soe)
+	}
+
+	output, err := openScanOutput(c)
+	if err != nil {
+		return commoncli.Problem("Failed to open output", err)
+	}
+	defer func() {
+		if closeErr := output.Close(); closeErr != nil && err == nil {
+			err = commoncli.Problem("Failed to write output", closeErr)
+		}
+	}()
+
+	retryers := newShardRetryers(c)
+	defer retryers.Close()
+
+	stop := cancelOnInterrupt(c)
+	defer stop()
+
+	dryRun := c.Bool(FlagDryRun)
+	for i, e := range data {
+		if c.Context.Err() != nil {
+			return executionsInterrupted(c, "Fix", i, len(data))
+		}
+		if e.Result.CheckResultType != invariant.CheckResultTypeCorrupted {
+			continue
+		}
+		var result invariant.ManagerFixResult
+		if dryRun {
+			result = dryRunFixResult(invariantNames(scanType, collections), e.Result)
+		} else {
+			result, err = fixCorruptedExecution(c, retryers, invariants, e)
+			if err != nil {
+				if c.Context.Err() != nil {
+					return executionsInterrupted(c, "Fix", i, len(data))
+				}
+				return commoncli.Problem("Error in fix execution", err)
+			}
+		}
+		out, err := json.Marshal(store.FixOutputEntity{
+			Execution: e.Execution,
+			Input:     *e,
+			Result:    result,
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			continue
+		}
+		output.Write(append(out, '\n'))
+	}
+	return nil
+}
+
+func fixCorruptedExecution(
+	c *cli.Context,
+	retryers *shardRetryers,
+	invariants []executions.InvariantFactory,
+	execution *store.ScanOutputEntity,
+) (invariant.ManagerFixResult, error) {
+	pr, err := retryers.Get(execution.Execution.(entity.Entity).GetShardID())
+	if err != nil {
+		return invariant.ManagerFixResult{}, err
+	}
+
+	var ivs []invariant.Invariant
+	for _, fn := range invariants {
+		ivs = append(ivs, fn(pr, cache.NewNoOpDomainCache()))
+	}
+	invariantManager, err := getDeps(c).initializeInvariantManager(ivs)
+	if err != nil {
+		return invariant.ManagerFixResult{}, err
+	}
+
+	ctx, cancel, err := newContext(c)
+	if err != nil {
+		return invariant.ManagerFixResult{}, err
+	}
+	defer cancel()
+	return invariantManager.RunFixes(ctx, execution.Execution), nil
+}
+
+// dryRunFixResult reports the fixes which would run, without touching the execution. Only the invariants
+// whose check failed in the scan are reported, as the fix of the others finds the execution healthy.
+func dryRunFixResult(names []invariant.Name, scanResult invariant.ManagerCheckResult) invariant.ManagerFixResult {
+	result := invariant.ManagerFixResult{
+		FixResultType: invariant.FixResultTypeSkipped,
+	}
+	checks := make(map[invariant.Name]invariant.CheckResult, len(scanResult.CheckResults))
+	for _, check := range scanResult.CheckResults {
+		checks[check.InvariantName] = check
+	}
+	for _, name := range names {
+		check, ok := checks[name]
+		if !ok || check.CheckResultType != invariant.CheckResultTypeCorrupted {
+			continue
+		}
+		result.FixResults = append(result.FixResults, invariant.FixResult{
+			FixResultType: invariant.FixResultTypeSkipped,
+			InvariantName: name,
+			CheckResult:   check,
+			Info:          "dry run, fix not applied",
+		})
+	}
+	return result
+}
+
+// invariantNames returns the names of the invariants run by the scan type for the collections.
+func invariantNames(scanType executions.ScanType, collections []invariant.Collection) []invariant.Name {
+	var names []invariant.Name
+	for _, collection := range collections {
+		if name, ok := scanType.InvariantName(collection); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// filterCollections keeps only the collections of the named invariants, each name must be the invariant
+// of one of the given collections.
+func filterCollections(scanType executions.ScanType, collections []invariant.Collection, names []string) ([]invariant.Collection, error) {
+	wanted := make(map[invariant.Name]bool, len(names))
+	for _, name := range names {
+		wanted[invariant.Name(name)] = true
+	}
+	var filtered []invariant.Collection
+	for _, collection := range collections {
+		if name, ok := scanType.InvariantName(collection); ok && wanted[name] {
+			filtered = append(filtered, collection)
+			delete(wanted, name)
+		}
+	}
+	for name := range wanted {
+		return nil, fmt.Errorf("%q is not one of the invariants for this scan type and collections", name)
+	}
+	return filtered, nil
+}
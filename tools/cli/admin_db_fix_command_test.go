@@ -0,0 +1,142 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/reconciliation/invariant"
+	"github.com/uber/cadence/service/worker/scanner/executions"
+	"github.com/uber/cadence/tools/cli/clitest"
+)
+
+const testFixInput = `{"Execution": {"ShardID": 1, "WorkflowID": "healthy"}, "Result": {"CheckResultType": "healthy"}}
+{"Execution": {"ShardID": 1, "WorkflowID": "corrupted"}, "Result": {"CheckResultType": "corrupted", "CheckResults": [{"CheckResultType": "corrupted", "InvariantName": "history_exists"}]}}
+`
+
+func TestAdminDBFix(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(td *cliTestData)
+		args           []clitest.CliArgument
+		expectedError  string
+		expectedOutput []string
+	}{
+		{
+			name: "fixes only corrupted executions",
+			mockSetup: func(td *cliTestData) {
+				mockExecManager := persistence.NewMockExecutionManager(td.ctrl)
+				mockExecManager.EXPECT().Close().Times(1)
+				mockHistoryManager := persistence.NewMockHistoryManager(td.ctrl)
+				mockHistoryManager.EXPECT().Close().Times(1)
+				mockInvariantManager := invariant.NewMockManager(td.ctrl)
+				mockInvariantManager.EXPECT().RunFixes(gomock.Any(), gomock.Any()).
+					Return(invariant.ManagerFixResult{FixResultType: invariant.FixResultTypeFixed}).Times(1)
+
+				td.mockManagerFactory.EXPECT().initializeExecutionManager(gomock.Any(), 1).Return(mockExecManager, nil).Times(1)
+				td.mockManagerFactory.EXPECT().initializeHistoryManager(gomock.Any()).Return(mockHistoryManager, nil).Times(1)
+				td.mockManagerFactory.EXPECT().initializeInvariantManager(gomock.Any()).Return(mockInvariantManager, nil).Times(1)
+			},
+			expectedOutput: []string{`"WorkflowID":"corrupted"`, `"FixResultType":"fixed"`},
+		},
+		{
+			name: "dry run does not fix",
+			args: []clitest.CliArgument{
+				clitest.BoolArgument(FlagDryRun, true),
+				clitest.StringSliceArgument(FlagInvariant, string(invariant.HistoryExists)),
+			},
+			expectedOutput: []string{`"WorkflowID":"corrupted"`, `"InvariantName":"history_exists"`, `"Info":"dry run, fix not applied"`},
+		},
+		{
+			name: "unknown invariant",
+			args: []clitest.CliArgument{
+				clitest.StringSliceArgument(FlagInvariant, "unknown"),
+			},
+			expectedError: `invalid invariant: "unknown" is not one of the invariants`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newCLITestData(t)
+			td.ioHandler.input = strings.NewReader(testFixInput)
+			if tt.mockSetup != nil {
+				tt.mockSetup(td)
+			}
+
+			args := append([]clitest.CliArgument{
+				clitest.StringArgument(FlagScanType, "ConcreteExecutionType"),
+				clitest.StringSliceArgument(FlagInvariantCollection, "CollectionHistory"),
+			}, tt.args...)
+			err := AdminDBFix(clitest.NewCLIContext(t, td.app, args...))
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			output := td.ioHandler.outputBytes.String()
+			assert.Equal(t, 1, strings.Count(output, "\n"), "only the corrupted execution is output")
+			for _, expected := range tt.expectedOutput {
+				assert.Contains(t, output, expected)
+			}
+		})
+	}
+}
+
+func TestFilterCollections(t *testing.T) {
+	collections := []invariant.Collection{invariant.CollectionHistory, invariant.CollectionDomain}
+
+	filtered, err := filterCollections(executions.ConcreteExecutionType, collections, []string{string(invariant.InactiveDomainExists)})
+	require.NoError(t, err)
+	assert.Equal(t, []invariant.Collection{invariant.CollectionDomain}, filtered)
+
+	_, err = filterCollections(executions.ConcreteExecutionType, collections, []string{string(invariant.StaleWorkflow)})
+	assert.Error(t, err)
+}
+
+func TestDryRunFixResult(t *testing.T) {
+	names := []invariant.Name{invariant.HistoryExists, invariant.InactiveDomainExists, invariant.StaleWorkflow}
+	result := dryRunFixResult(names, invariant.ManagerCheckResult{
+		CheckResultType: invariant.CheckResultTypeCorrupted,
+		CheckResults: []invariant.CheckResult{
+			{CheckResultType: invariant.CheckResultTypeHealthy, InvariantName: invariant.HistoryExists},
+			{CheckResultType: invariant.CheckResultTypeCorrupted, InvariantName: invariant.InactiveDomainExists, Info: "domain is deprecated"},
+		},
+	})
+	assert.Equal(t, invariant.ManagerFixResult{
+		FixResultType: invariant.FixResultTypeSkipped,
+		FixResults: []invariant.FixResult{{
+			FixResultType: invariant.FixResultTypeSkipped,
+			InvariantName: invariant.InactiveDomainExists,
+			CheckResult:   invariant.CheckResult{CheckResultType: invariant.CheckResultTypeCorrupted, InvariantName: invariant.InactiveDomainExists, Info: "domain is deprecated"},
+			Info:          "dry run, fix not applied",
+		}},
+	}, result)
+}
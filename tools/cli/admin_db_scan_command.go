@@ -23,11 +23,15 @@
 package cli
 
 import (
-	"context"
+	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"plugin"
+	"strings"
 	"time"
 
 	"github.com/urfave/cli/v2"
@@ -40,65 +44,51 @@ import (
 	"github.com/uber/cadence/common/reconciliation/fetcher"
 	"github.com/uber/cadence/common/reconciliation/invariant"
 	"github.com/uber/cadence/common/reconciliation/store"
+	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/worker/scanner/executions"
 	"github.com/uber/cadence/tools/common/commoncli"
 )
 
 const (
 	listContextTimeout = time.Minute
+	// stdinInputFile is the --input_file value which reads the input from stdin
+	stdinInputFile = "-"
+	// gzipExtension is the file name extension of gzip-compressed scan inputs and outputs
+	gzipExtension = ".gz"
+	// defaultScanPageSize is the default number of executions listed per page when scanning whole shards
+	defaultScanPageSize = 1000
+	// maxScanPageSize is the page size above which listing is likely to strain the database and the CLI memory
+	maxScanPageSize = 10000
 )
 
 // AdminDBScan is used to scan over executions in database and detect corruptions.
-func AdminDBScan(c *cli.Context) error {
+func AdminDBScan(c *cli.Context) (err error) {
 	scanType, err := executions.ScanTypeString(c.String(FlagScanType))
 
 	if err != nil {
-		return commoncli.Problem("unknown scan type", err)
+		return commoncli.Problem(fmt.Sprintf("unknown scan type, valid scan types are %q", executions.ScanTypeStrings()), err)
 	}
 
 	numberOfShards, err := getRequiredIntOption(c, FlagNumberOfShards)
 	if err != nil {
 		return commoncli.Problem("Required flag not found", err)
 	}
-	collectionSlice := c.StringSlice(FlagInvariantCollection)
-
-	var collections []invariant.Collection
-	for _, v := range collectionSlice {
-		collection, err := invariant.CollectionString(v)
-		if err != nil {
-			return commoncli.Problem("unknown invariant collection", err)
-		}
-		collections = append(collections, collection)
-	}
-
-	logger := zap.NewNop()
-	if c.Bool(FlagVerbose) {
-		logger, err = zap.NewDevelopment()
-		if err != nil {
-			// probably impossible with default config
-			return commoncli.Problem("could not construct logger", err)
-		}
-	}
-
-	invariants := scanType.ToInvariants(collections, logger)
-	if len(invariants) < 1 {
-		return commoncli.Problem(
-			fmt.Sprintf("no invariants for scan type %q and collections %q",
-				scanType.String(),
-				collectionSlice),
-			nil,
-		)
+	invariantsFn, logger, err := getScanInvariants(c, scanType)
+	if err != nil {
+		return err
 	}
 	ef := scanType.ToExecutionFetcher()
+	timeRange, err := getStartTimeRange(c)
+	if err != nil {
+		return commoncli.Problem("Invalid start time range", err)
+	}
 
-	input, err := getInputFile(c.String(FlagInputFile))
+	input, err := openScanInputs(c)
 	if err != nil {
 		return commoncli.Problem("Input file not found", err)
 	}
+	defer input.Close()
 	dec := json.NewDecoder(input)
-	if err != nil {
-		return commoncli.Problem("", err)
-	}
 	var data []fetcher.ExecutionRequest
 
 	for {
@@ -116,11 +106,57 @@ func AdminDBScan(c *cli.Context) error {
 		return commoncli.Problem("Input file contained no data to scan", nil)
 	}
 
-	for _, e := range data {
-		execution, result, err := checkExecution(c, numberOfShards, e, invariants, ef)
+	output, err := openScanOutput(c)
+	if err != nil {
+		return commoncli.Problem("Failed to open output", err)
+	}
+	defer func() {
+		if closeErr := output.Close(); closeErr != nil && err == nil {
+			err = commoncli.Problem("Failed to write output", closeErr)
+		}
+	}()
+
+	retryers := newShardRetryers(c)
+	defer retryers.Close()
+
+	stop := cancelOnInterrupt(c)
+	defer stop()
+
+	onlyCorrupted := c.Bool(FlagOnlyCorrupted)
+	skipped := 0
+	defer func() {
+		if skipped > 0 {
+			fmt.Fprintf(getDeps(c).Progress(), "Skipped %d of %d executions started outside of the --%s and --%s range.\n", skipped, len(data), FlagStartTime, FlagEndTime)
+		}
+	}()
+	for i, e := range data {
+		if c.Context.Err() != nil {
+			return executionsInterrupted(c, "Scan", i, len(data))
+		}
+		if timeRange.isSet() {
+			inRange, err := executionStartedInRange(c, retryers, numberOfShards, e, timeRange)
+			if err != nil {
+				if c.Context.Err() != nil {
+					return executionsInterrupted(c, "Scan", i, len(data))
+				}
+				return commoncli.Problem("Execution start time check failed", err)
+			}
+			if !inRange {
+				skipped++
+				continue
+			}
+		}
+		shardID := common.WorkflowIDToHistoryShard(e.WorkflowID, numberOfShards)
+		execution, result, err := checkExecution(c, retryers, shardID, e, invariantsFn, logger, ef)
 		if err != nil {
+			if c.Context.Err() != nil {
+				return executionsInterrupted(c, "Scan", i, len(data))
+			}
 			return commoncli.Problem("Execution check failed", err)
 		}
+		if onlyCorrupted && result.CheckResultType == invariant.CheckResultTypeHealthy {
+			continue
+		}
 		out := store.ScanOutputEntity{
 			Execution: execution,
 			Result:    result,
@@ -131,35 +167,399 @@ func AdminDBScan(c *cli.Context) error {
 			continue
 		}
 
-		getDeps(c).Output().Write(data)
+		output.Write(data)
 	}
 	return nil
 }
 
-func checkExecution(
-	c *cli.Context,
-	numberOfShards int,
-	req fetcher.ExecutionRequest,
-	invariants []executions.InvariantFactory,
-	fetcher executions.ExecutionFetcher,
-) (interface{}, invariant.ManagerCheckResult, error) {
-	execManager, err := getDeps(c).initializeExecutionManager(c, common.WorkflowIDToHistoryShard(req.WorkflowID, numberOfShards))
+// AdminDBValidateShard runs the scan invariants on every execution of a shard, without an input of executions.
+func AdminDBValidateShard(c *cli.Context) (err error) {
+	scanType, err := executions.ScanTypeString(c.String(FlagScanType))
+	if err != nil {
+		return commoncli.Problem(fmt.Sprintf("unknown scan type, valid scan types are %q", executions.ScanTypeStrings()), err)
+	}
+	shardID, err := getRequiredIntOption(c, FlagShardID)
+	if err != nil {
+		return commoncli.Problem("Required flag not found", err)
+	}
+	invariantsFn, logger, err := getScanInvariants(c, scanType)
+	if err != nil {
+		return err
+	}
+	ef := scanType.ToExecutionFetcher()
+	pageSize, err := getScanPageSize(c)
+	if err != nil {
+		return err
+	}
+
+	output, err := openScanOutput(c)
+	if err != nil {
+		return commoncli.Problem("Failed to open output", err)
+	}
+	defer func() {
+		if closeErr := output.Close(); closeErr != nil && err == nil {
+			err = commoncli.Problem("Failed to write output", closeErr)
+		}
+	}()
+
+	retryers := newShardRetryers(c)
+	defer retryers.Close()
+	pr, err := retryers.Get(shardID)
+	if err != nil {
+		return commoncli.Problem(fmt.Sprintf("Failed to open shard %d", shardID), err)
+	}
+
+	stop := cancelOnInterrupt(c)
+	defer stop()
+
+	paginationFunc := func(paginationToken []byte) ([]interface{}, []byte, error) {
+		ctx, cancel, err := newTimedContext(c, listContextTimeout)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer cancel()
+
+		resp, err := pr.ListConcreteExecutions(ctx, &persistence.ListConcreteExecutionsRequest{
+			PageSize:  pageSize,
+			PageToken: paginationToken,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		var items []interface{}
+		for _, execution := range resp.Executions {
+			items = append(items, execution)
+		}
+		return items, resp.PageToken, nil
+	}
+
+	onlyCorrupted := c.Bool(FlagOnlyCorrupted)
+	validated, unhealthy := 0, 0
+	interrupted := func() error {
+		fmt.Fprintf(getDeps(c).Progress(), "Interrupted after validating %d executions of shard %d.\n", validated, shardID)
+		return commoncli.Problem("Validation interrupted", c.Context.Err())
+	}
+	it := collection.NewPagingIterator(paginationFunc)
+	for it.HasNext() {
+		if c.Context.Err() != nil {
+			return interrupted()
+		}
+		item, err := it.Next()
+		if err != nil {
+			if c.Context.Err() != nil {
+				return interrupted()
+			}
+			return commoncli.Problem(fmt.Sprintf("Failed to list the executions of shard %d", shardID), err)
+		}
+		info := item.(*persistence.ListConcreteExecutionsEntity).ExecutionInfo
+		if info == nil {
+			continue
+		}
+		req := fetcher.ExecutionRequest{
+			DomainID:   info.DomainID,
+			WorkflowID: info.WorkflowID,
+			RunID:      info.RunID,
+		}
+		execution, result, err := checkExecution(c, retryers, shardID, req, invariantsFn, logger, ef)
+		if err != nil {
+			if c.Context.Err() != nil {
+				return interrupted()
+			}
+			return commoncli.Problem("Execution check failed", err)
+		}
+		validated++
+		if result.CheckResultType != invariant.CheckResultTypeHealthy {
+			unhealthy++
+		} else if onlyCorrupted {
+			continue
+		}
+		data, err := json.Marshal(store.ScanOutputEntity{
+			Execution: execution,
+			Result:    result,
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			continue
+		}
+		output.Write(data)
+	}
+	fmt.Fprintf(getDeps(c).Progress(), "Validated %d executions of shard %d, %d not healthy.\n", validated, shardID, unhealthy)
+	return nil
+}
+
+// getScanInvariants returns the invariants to run for scanType, from the collection, plugin and skipped invariant flags,
+// and the logger to run them with.
+func getScanInvariants(c *cli.Context, scanType executions.ScanType) (func(*zap.Logger) []executions.InvariantFactory, *zap.Logger, error) {
+	collectionSlice := c.StringSlice(FlagInvariantCollection)
+
+	var collections []invariant.Collection
+	for _, v := range collectionSlice {
+		collection, err := invariant.CollectionString(v)
+		if err != nil {
+			return nil, nil, commoncli.Problem(fmt.Sprintf("unknown invariant collection, valid collections are %q", invariant.CollectionStrings()), err)
+		}
+		collections = append(collections, collection)
+	}
+
+	logger := zap.NewNop()
+	var err error
+	if c.Bool(FlagVerbose) {
+		logger, err = zap.NewDevelopment()
+		if err != nil {
+			// probably impossible with default config
+			return nil, nil, commoncli.Problem("could not construct logger", err)
+		}
+	}
+
+	plugins, err := loadInvariantPlugins(c.StringSlice(FlagPlugin))
 	if err != nil {
-		return nil, invariant.ManagerCheckResult{}, fmt.Errorf("initialize execution manager: %w", err)
+		return nil, nil, commoncli.Problem("could not load invariant plugin", err)
+	}
+	if len(scanType.ToInvariants(collections, logger))+len(plugins) < 1 {
+		return nil, nil, noInvariantsProblem(scanType, collectionSlice)
+	}
+	var pluginInvariants []executions.InvariantFactory
+	for _, p := range plugins {
+		pluginInvariants = append(pluginInvariants, p.factory)
+	}
+	if skipped := c.StringSlice(FlagSkipInvariant); len(skipped) > 0 {
+		collections, pluginInvariants, err = skipInvariants(scanType, collections, plugins, skipped)
+		if err != nil {
+			return nil, nil, commoncli.Problem("invalid invariant to skip", err)
+		}
+	}
+	// invariants are created per execution so that their logs carry the execution being checked
+	invariantsFn := func(logger *zap.Logger) []executions.InvariantFactory {
+		return append(scanType.ToInvariants(collections, logger), pluginInvariants...)
+	}
+	return invariantsFn, logger, nil
+}
+
+// noInvariantsProblem lists the collections which have invariants for the scan type,
+// and the scan types to pick another one from.
+func noInvariantsProblem(scanType executions.ScanType, collections []string) error {
+	var valid []string
+	for _, collection := range invariant.CollectionValues() {
+		if len(scanType.ToInvariants([]invariant.Collection{collection}, zap.NewNop())) > 0 {
+			valid = append(valid, collection.String())
+		}
+	}
+	return commoncli.Problem(
+		fmt.Sprintf("no invariants for scan type %q and collections %q. Collections with invariants for %q are %q, valid scan types are %q",
+			scanType.String(),
+			collections,
+			scanType.String(),
+			valid,
+			executions.ScanTypeStrings()),
+		nil,
+	)
+}
+
+// invariantPluginSymbol is the symbol looked up in invariant plugins passed to `admin db scan --plugin`.
+//
+// A plugin is a `package main` built with `go build -buildmode=plugin` against the same cadence
+// version as the CLI, exporting a function with the executions.InvariantFactory signature:
+//
+//	func NewInvariant(retryer persistence.Retryer, domainCache cache.DomainCache) invariant.Invariant
+//
+// or a variable of type executions.InvariantFactory or []executions.InvariantFactory with that name.
+const invariantPluginSymbol = "NewInvariant"
+
+// invariantPluginNamesSymbol is the optional symbol naming the invariants of a plugin, so that they can be skipped
+// with --skip_invariant. It is a []string variable with the name of the invariant built by each factory of the
+// plugin, in the order of the factories:
+//
+//	var InvariantNames = []string{"my_invariant"}
+//
+// The factories are not called to learn the names, they only get a persistence.Retryer once a shard is scanned.
+const invariantPluginNamesSymbol = "InvariantNames"
+
+// invariantPlugin is an invariant factory loaded from a plugin, with the name of its invariant if the plugin exports it.
+type invariantPlugin struct {
+	name    invariant.Name
+	factory executions.InvariantFactory
+}
+
+// loadInvariantPlugins opens each plugin and returns the invariant factories they export.
+func loadInvariantPlugins(paths []string) ([]invariantPlugin, error) {
+	var plugins []invariantPlugin
+	for _, path := range paths {
+		p, err := plugin.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening plugin %v: %w", path, err)
+		}
+		sym, err := p.Lookup(invariantPluginSymbol)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %v: %w", path, err)
+		}
+		fns, err := invariantFactoriesFromSymbol(sym)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %v: %w", path, err)
+		}
+		var names []string
+		if sym, err := p.Lookup(invariantPluginNamesSymbol); err == nil {
+			if names, err = invariantNamesFromSymbol(sym, len(fns)); err != nil {
+				return nil, fmt.Errorf("plugin %v: %w", path, err)
+			}
+		}
+		for i, fn := range fns {
+			loaded := invariantPlugin{factory: fn}
+			if names != nil {
+				loaded.name = invariant.Name(names[i])
+			}
+			plugins = append(plugins, loaded)
+		}
+	}
+	return plugins, nil
+}
+
+// invariantNamesFromSymbol returns the names exported by a plugin, one for each of its count factories.
+func invariantNamesFromSymbol(sym plugin.Symbol, count int) ([]string, error) {
+	names, ok := sym.(*[]string)
+	if !ok {
+		return nil, fmt.Errorf("symbol %v has unsupported type %T, expected []string", invariantPluginNamesSymbol, sym)
+	}
+	if len(*names) != count {
+		return nil, fmt.Errorf("symbol %v has %d names for %d invariants", invariantPluginNamesSymbol, len(*names), count)
+	}
+	return *names, nil
+}
+
+func invariantFactoriesFromSymbol(sym plugin.Symbol) ([]executions.InvariantFactory, error) {
+	switch v := sym.(type) {
+	case func(persistence.Retryer, cache.DomainCache) invariant.Invariant:
+		return []executions.InvariantFactory{v}, nil
+	case *executions.InvariantFactory:
+		return []executions.InvariantFactory{*v}, nil
+	case *[]executions.InvariantFactory:
+		return *v, nil
+	default:
+		return nil, fmt.Errorf("symbol %v has unsupported type %T, expected executions.InvariantFactory", invariantPluginSymbol, sym)
+	}
+}
+
+// skipInvariants removes the named invariants from the collections and plugin invariants to run. The names of
+// the collections' invariants are known statically, plugins name their invariants with invariantPluginNamesSymbol.
+// Plugin invariants without a name cannot be skipped.
+func skipInvariants(
+	scanType executions.ScanType,
+	collections []invariant.Collection,
+	plugins []invariantPlugin,
+	names []string,
+) ([]invariant.Collection, []executions.InvariantFactory, error) {
+	skipped := make(map[invariant.Name]bool, len(names))
+	for _, name := range names {
+		skipped[invariant.Name(name)] = true
+	}
+	known := make(map[invariant.Name]bool)
+	var keptCollections []invariant.Collection
+	for _, collection := range collections {
+		name, ok := scanType.InvariantName(collection)
+		if !ok {
+			continue
+		}
+		known[name] = true
+		if !skipped[name] {
+			keptCollections = append(keptCollections, collection)
+		}
+	}
+	var keptPlugins []executions.InvariantFactory
+	unnamedPlugins := false
+	for _, p := range plugins {
+		if p.name == "" {
+			unnamedPlugins = true
+		}
+		known[p.name] = true
+		if p.name == "" || !skipped[p.name] {
+			keptPlugins = append(keptPlugins, p.factory)
+		}
+	}
+	for name := range skipped {
+		if !known[name] {
+			if unnamedPlugins {
+				return nil, nil, fmt.Errorf("%q is not one of the invariants for this scan, plugin invariants can only be skipped when the plugin exports their %v", name, invariantPluginNamesSymbol)
+			}
+			return nil, nil, fmt.Errorf("%q is not one of the invariants for this scan", name)
+		}
+	}
+	if len(keptCollections)+len(keptPlugins) == 0 {
+		return nil, nil, fmt.Errorf("all invariants are skipped")
+	}
+	return keptCollections, keptPlugins, nil
+}
+
+// shardRetryers lazily creates a persistence retryer per shard, so the executions of a shard
+// share its execution store instead of opening one per execution. All shards share the history manager.
+type shardRetryers struct {
+	c              *cli.Context
+	historyManager persistence.HistoryManager
+	execManagers   []persistence.ExecutionManager
+	retryers       map[int]persistence.Retryer
+}
+
+func newShardRetryers(c *cli.Context) *shardRetryers {
+	return &shardRetryers{
+		c:        c,
+		retryers: make(map[int]persistence.Retryer),
 	}
-	defer execManager.Close()
+}
 
-	historyV2Mgr, err := getDeps(c).initializeHistoryManager(c)
+// Get returns the retryer of shardID, creating it on first use.
+func (s *shardRetryers) Get(shardID int) (persistence.Retryer, error) {
+	if pr, ok := s.retryers[shardID]; ok {
+		return pr, nil
+	}
+	execManager, err := getDeps(s.c).initializeExecutionManager(s.c, shardID)
 	if err != nil {
-		return nil, invariant.ManagerCheckResult{}, fmt.Errorf("initialize history manager: %w", err)
+		return nil, fmt.Errorf("initialize execution manager: %w", err)
+	}
+	s.execManagers = append(s.execManagers, execManager)
+	if s.historyManager == nil {
+		historyManager, err := getDeps(s.c).initializeHistoryManager(s.c)
+		if err != nil {
+			return nil, fmt.Errorf("initialize history manager: %w", err)
+		}
+		s.historyManager = historyManager
 	}
-	defer historyV2Mgr.Close()
 
 	pr := persistence.NewPersistenceRetryer(
 		execManager,
-		historyV2Mgr,
+		s.historyManager,
 		common.CreatePersistenceRetryPolicy(),
 	)
+	s.retryers[shardID] = pr
+	return pr, nil
+}
+
+// Close closes the stores of all the retryers created so far.
+func (s *shardRetryers) Close() {
+	for _, execManager := range s.execManagers {
+		execManager.Close()
+	}
+	if s.historyManager != nil {
+		s.historyManager.Close()
+	}
+}
+
+func checkExecution(
+	c *cli.Context,
+	retryers *shardRetryers,
+	shardID int,
+	req fetcher.ExecutionRequest,
+	invariantsFn func(*zap.Logger) []executions.InvariantFactory,
+	logger *zap.Logger,
+	fetcher executions.ExecutionFetcher,
+) (interface{}, invariant.ManagerCheckResult, error) {
+	logger = logger.With(
+		zap.String("DomainID", req.DomainID),
+		zap.String("WorkflowID", req.WorkflowID),
+		zap.String("RunID", req.RunID),
+		zap.Int("ShardID", shardID),
+	)
+	pr, err := retryers.Get(shardID)
+	if err != nil {
+		return nil, invariant.ManagerCheckResult{}, err
+	}
 
 	ctx, cancel, err := newContext(c)
 	if err != nil {
@@ -172,53 +572,153 @@ func checkExecution(
 		return nil, invariant.ManagerCheckResult{}, fmt.Errorf("fetching execution: %w", err)
 	}
 
+	// invariants are cheap to create, they are built per execution so their logs carry its fields
 	var ivs []invariant.Invariant
 
-	for _, fn := range invariants {
+	for _, fn := range invariantsFn(logger) {
 		ivs = append(ivs, fn(pr, cache.NewNoOpDomainCache()))
 	}
 
-	return execution, invariant.NewInvariantManager(ivs).RunChecks(ctx, execution), nil
+	result := invariant.NewInvariantManager(ivs).RunChecks(ctx, execution)
+	logger.Debug("execution checked", zap.String("CheckResultType", string(result.CheckResultType)))
+	return execution, result, nil
+}
+
+// startTimeRange bounds the start time of the executions to scan, a zero bound is unbounded
+type startTimeRange struct {
+	earliest time.Time
+	latest   time.Time
+}
+
+func getStartTimeRange(c *cli.Context) (startTimeRange, error) {
+	var r startTimeRange
+	if c.String(FlagStartTime) != "" {
+		earliest, err := parseTime(c.String(FlagStartTime), 0)
+		if err != nil {
+			return r, err
+		}
+		r.earliest = time.Unix(0, earliest)
+	}
+	if c.String(FlagEndTime) != "" {
+		latest, err := parseTime(c.String(FlagEndTime), 0)
+		if err != nil {
+			return r, err
+		}
+		r.latest = time.Unix(0, latest)
+	}
+	if !r.earliest.IsZero() && !r.latest.IsZero() && r.latest.Before(r.earliest) {
+		return r, fmt.Errorf("--%s %v is before --%s %v", FlagEndTime, r.latest, FlagStartTime, r.earliest)
+	}
+	return r, nil
+}
+
+func (r startTimeRange) isSet() bool {
+	return !r.earliest.IsZero() || !r.latest.IsZero()
+}
+
+func (r startTimeRange) contains(t time.Time) bool {
+	return (r.earliest.IsZero() || !t.Before(r.earliest)) && (r.latest.IsZero() || !t.After(r.latest))
+}
+
+// executionStartedInRange reads the start time of the execution from its mutable state, as the execution fetchers
+// can't filter by it. Executions without mutable state are kept, so that the invariants report them.
+func executionStartedInRange(
+	c *cli.Context,
+	retryers *shardRetryers,
+	numberOfShards int,
+	req fetcher.ExecutionRequest,
+	timeRange startTimeRange,
+) (bool, error) {
+	pr, err := retryers.Get(common.WorkflowIDToHistoryShard(req.WorkflowID, numberOfShards))
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel, err := newContext(c)
+	if err != nil {
+		return false, fmt.Errorf("Error in creating context: %w", err)
+	}
+	defer cancel()
+	resp, err := pr.GetWorkflowExecution(ctx, &persistence.GetWorkflowExecutionRequest{
+		DomainID:   req.DomainID,
+		DomainName: req.DomainName,
+		Execution:  types.WorkflowExecution{WorkflowID: req.WorkflowID, RunID: req.RunID},
+	})
+	var notExists *types.EntityNotExistsError
+	if errors.As(err, &notExists) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading mutable state: %w", err)
+	}
+	return timeRange.contains(resp.State.ExecutionInfo.StartTimestamp), nil
 }
 
 // AdminDBScanUnsupportedWorkflow is to scan DB for unsupported workflow for a new release
-func AdminDBScanUnsupportedWorkflow(c *cli.Context) error {
-	outputFile, err := getOutputFile(c.String(FlagOutputFilename))
+func AdminDBScanUnsupportedWorkflow(c *cli.Context) (err error) {
+	pageSize, err := getScanPageSize(c)
+	if err != nil {
+		return err
+	}
+	outputFile, err := openScanOutput(c)
 	if err != nil {
 		return commoncli.Problem("Error in admin db scan unsupported wf: ", err)
 	}
 	startShardID := c.Int(FlagLowerShardBound)
 	endShardID := c.Int(FlagUpperShardBound)
+	if resumeShardID := c.Int(FlagResumeFromShard); resumeShardID > startShardID {
+		startShardID = resumeShardID
+	}
+
+	defer func() {
+		if closeErr := outputFile.Close(); closeErr != nil && err == nil {
+			err = commoncli.Problem("Failed to write data to file", closeErr)
+		}
+	}()
+	stop := cancelOnInterrupt(c)
+	defer stop()
 
-	defer outputFile.Close()
 	for i := startShardID; i <= endShardID; i++ {
-		if err := listExecutionsByShardID(c, i, outputFile); err != nil {
+		if c.Context.Err() != nil {
+			return shardsInterrupted(c, startShardID, i)
+		}
+		if err := listExecutionsByShardID(c, i, pageSize, outputFile); err != nil {
+			if c.Context.Err() != nil {
+				return shardsInterrupted(c, startShardID, i)
+			}
 			return err
 		}
 		fmt.Printf("Shard %v scan operation is completed.\n", i)
 	}
+	if startShardID <= endShardID {
+		fmt.Fprintf(getDeps(c).Progress(), "Last completed shard: %d\n", endShardID)
+	}
 	return nil
 }
 
 func listExecutionsByShardID(
 	c *cli.Context,
 	shardID int,
-	outputFile *os.File,
+	pageSize int,
+	outputFile *scanOutput,
 ) error {
 
 	client, err := getDeps(c).initializeExecutionManager(c, shardID)
 	if err != nil {
-		commoncli.Problem("initialize execution manager:", err)
+		return commoncli.Problem("initialize execution manager:", err)
 	}
 	defer client.Close()
 	paginationFunc := func(paginationToken []byte) ([]interface{}, []byte, error) {
-		ctx, cancel := context.WithTimeout(c.Context, listContextTimeout)
+		// every page is bounded by the configured timeout and stops with the command on interrupt
+		ctx, cancel, err := newTimedContext(c, listContextTimeout)
+		if err != nil {
+			return nil, nil, err
+		}
 		defer cancel()
 
 		resp, err := client.ListConcreteExecutions(
 			ctx,
 			&persistence.ListConcreteExecutionsRequest{
-				PageSize:  1000,
+				PageSize:  pageSize,
 				PageToken: paginationToken,
 			},
 		)
@@ -247,7 +747,18 @@ func listExecutionsByShardID(
 				executionInfo.WorkflowID,
 				executionInfo.RunID,
 			)
-			if _, err = outputFile.WriteString(outStr); err != nil {
+			if c.Bool(FlagOutputJSONL) {
+				data, err := json.Marshal(fetcher.ExecutionRequest{
+					DomainID:   executionInfo.DomainID,
+					WorkflowID: executionInfo.WorkflowID,
+					RunID:      executionInfo.RunID,
+				})
+				if err != nil {
+					return commoncli.Problem("Failed to serialize execution", err)
+				}
+				outStr = string(data) + "\n"
+			}
+			if _, err = io.WriteString(outputFile, outStr); err != nil {
 				return commoncli.Problem("Failed to write data to file", err)
 			}
 			if err = outputFile.Sync(); err != nil {
@@ -257,3 +768,186 @@ func listExecutionsByShardID(
 	}
 	return nil
 }
+
+// getScanPageSize returns the --pagesize to list executions with, warning when it is large enough to strain the database.
+func getScanPageSize(c *cli.Context) (int, error) {
+	if !c.IsSet(FlagPageSize) {
+		return defaultScanPageSize, nil
+	}
+	pageSize := c.Int(FlagPageSize)
+	if pageSize <= 0 {
+		return 0, commoncli.Problem(fmt.Sprintf("--%s must be positive, got %d", FlagPageSize, pageSize), nil)
+	}
+	if pageSize > maxScanPageSize {
+		fmt.Fprintf(getDeps(c).Progress(), "%s --%s %d is above %d, listing may put a lot of pressure on the database and use a lot of memory\n",
+			colorRed("Warning:"), FlagPageSize, pageSize, maxScanPageSize)
+	}
+	return pageSize, nil
+}
+
+// cancelOnInterrupt cancels the context of the command on SIGINT, so that a long scan stops
+// between two items with everything written so far complete in its output.
+// The returned func restores the default SIGINT handling.
+func cancelOnInterrupt(c *cli.Context) func() {
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt)
+	c.Context = ctx
+	return stop
+}
+
+// executionsInterrupted reports how many input executions were processed before the interruption,
+// which is the number of input lines to skip to resume.
+func executionsInterrupted(c *cli.Context, operation string, processed, total int) error {
+	fmt.Fprintf(getDeps(c).Progress(), "Interrupted after processing %d of %d executions, skip the first %d input lines to resume.\n", processed, total, processed)
+	return commoncli.Problem(operation+" interrupted", c.Context.Err())
+}
+
+// shardsInterrupted reports the last shard completed before the interruption, and the shard to resume from.
+func shardsInterrupted(c *cli.Context, startShardID, shardID int) error {
+	if shardID > startShardID {
+		fmt.Fprintf(getDeps(c).Progress(), "Interrupted, last completed shard: %d\n", shardID-1)
+	}
+	fmt.Fprintf(getDeps(c).Progress(), "Resume with --%s %d.\n", FlagResumeFromShard, shardID)
+	return commoncli.Problem("Scan interrupted", c.Context.Err())
+}
+
+// openScanInput opens --input_file, or the command input when the flag is unset or "-"
+// so executions can be piped into the scan.
+// The input is decompressed when the file name ends with .gz or --input_gzip is set.
+func openScanInput(c *cli.Context) (io.ReadCloser, error) {
+	return openInput(c, c.String(FlagInputFile))
+}
+
+// openScanInputs is openScanInput for commands where --input_file can be repeated,
+// reading the files one after the other as a single input.
+func openScanInputs(c *cli.Context) (io.ReadCloser, error) {
+	inputFiles := c.StringSlice(FlagInputFile)
+	if len(inputFiles) <= 1 {
+		return openInput(c, strings.Join(inputFiles, ""))
+	}
+	var inputs multiInput
+	for _, inputFile := range inputFiles {
+		input, err := openInput(c, inputFile)
+		if err != nil {
+			inputs.Close()
+			return nil, fmt.Errorf("%v: %w", inputFile, err)
+		}
+		inputs.inputs = append(inputs.inputs, input)
+	}
+	readers := make([]io.Reader, len(inputs.inputs))
+	for i, input := range inputs.inputs {
+		readers[i] = input
+	}
+	inputs.Reader = io.MultiReader(readers...)
+	return &inputs, nil
+}
+
+// multiInput reads its inputs one after the other, and closes all of them
+type multiInput struct {
+	io.Reader
+	inputs []io.ReadCloser
+}
+
+func (m *multiInput) Close() error {
+	var err error
+	for _, input := range m.inputs {
+		if closeErr := input.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// openInput opens inputFile, or the command input when it is empty or "-"
+func openInput(c *cli.Context, inputFile string) (io.ReadCloser, error) {
+	var input io.ReadCloser
+	if inputFile != "" && inputFile != stdinInputFile {
+		f, err := getInputFile(inputFile)
+		if err != nil {
+			return nil, err
+		}
+		input = f
+	} else {
+		in := getDeps(c).Input()
+		if f, ok := in.(*os.File); ok {
+			if info, err := f.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+				return nil, fmt.Errorf("stdin is a terminal, provide --%s or pipe executions to stdin", FlagInputFile)
+			}
+		}
+		input = io.NopCloser(in)
+	}
+	if !c.Bool(FlagInputGzip) && !strings.HasSuffix(inputFile, gzipExtension) {
+		return input, nil
+	}
+	gz, err := gzip.NewReader(input)
+	if err != nil {
+		input.Close()
+		return nil, fmt.Errorf("failed to read gzip input: %w", err)
+	}
+	return &gzipInput{Reader: gz, input: input}, nil
+}
+
+// gzipInput decompresses a scan input and closes it with the decompressor
+type gzipInput struct {
+	*gzip.Reader
+	input io.Closer
+}
+
+func (i *gzipInput) Close() error {
+	err := i.Reader.Close()
+	if closeErr := i.input.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// scanOutput is where scan results are written: --output_filename if the command has it and it is set,
+// or else the command output. It is compressed when the file name ends with .gz or --output_gzip is set.
+type scanOutput struct {
+	io.Writer
+	gz   *gzip.Writer
+	file *os.File
+}
+
+func openScanOutput(c *cli.Context) (*scanOutput, error) {
+	out := &scanOutput{Writer: getDeps(c).Output()}
+	outputFile := c.String(FlagOutputFilename)
+	if outputFile != "" {
+		f, err := getOutputFile(outputFile, c.Bool(FlagAppend))
+		if err != nil {
+			return nil, err
+		}
+		out.Writer, out.file = f, f
+	}
+	if c.Bool(FlagOutputGzip) || strings.HasSuffix(outputFile, gzipExtension) {
+		out.gz = gzip.NewWriter(out.Writer)
+		out.Writer = out.gz
+	}
+	return out, nil
+}
+
+// Sync flushes the compressed data written so far and commits the output file to storage,
+// so that what was written stays readable if the scan is interrupted.
+func (o *scanOutput) Sync() error {
+	if o.gz != nil {
+		if err := o.gz.Flush(); err != nil {
+			return err
+		}
+	}
+	if o.file != nil {
+		return o.file.Sync()
+	}
+	return nil
+}
+
+func (o *scanOutput) Close() error {
+	var err error
+	if o.gz != nil {
+		err = o.gz.Close()
+	}
+	if o.file != nil {
+		if closeErr := o.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
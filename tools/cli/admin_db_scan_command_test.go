@@ -23,18 +23,33 @@
 package cli
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/reconciliation/fetcher"
+	"github.com/uber/cadence/common/reconciliation/invariant"
+	"github.com/uber/cadence/common/reconciliation/store"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/worker/scanner/executions"
 	"github.com/uber/cadence/tools/cli/clitest"
 )
 
@@ -58,7 +73,7 @@ func TestAdminDBScanErrorCases(t *testing.T) {
 					clitest.StringArgument("scan_type", "some_unknown_scan_type"),
 				)
 			},
-			errContains: "unknown scan type",
+			errContains: `unknown scan type, valid scan types are ["ConcreteExecutionType" "CurrentExecutionType"]`,
 		},
 		{
 			name: "number of shards not provided",
@@ -77,7 +92,20 @@ func TestAdminDBScanErrorCases(t *testing.T) {
 					clitest.IntArgument("number_of_shards", 16384),
 				)
 			},
-			errContains: "no invariants for scan type \"ConcreteExecutionType\" and collections []",
+			errContains: `no invariants for scan type "ConcreteExecutionType" and collections []. ` +
+				`Collections with invariants for "ConcreteExecutionType" are ["CollectionMutableState" "CollectionHistory" "CollectionDomain" "CollectionStale"]`,
+		},
+		{
+			name: "invariant collection without invariants for the scan type",
+			testSetup: func(td *cliTestData) *cli.Context {
+				return clitest.NewCLIContext(t, td.app,
+					clitest.StringArgument("scan_type", "CurrentExecutionType"),
+					clitest.IntArgument("number_of_shards", 16384),
+					clitest.StringSliceArgument("invariant_collection", "CollectionHistory"),
+				)
+			},
+			errContains: `Collections with invariants for "CurrentExecutionType" are ["CollectionMutableState"], ` +
+				`valid scan types are ["ConcreteExecutionType" "CurrentExecutionType"]`,
 		},
 		{
 			name: "invalid invariant collection provided",
@@ -88,7 +116,19 @@ func TestAdminDBScanErrorCases(t *testing.T) {
 					clitest.StringSliceArgument("invariant_collection", "some_unknown_invariant_collection"),
 				)
 			},
-			errContains: "unknown invariant collection: some_unknown_invariant_collection",
+			errContains: `unknown invariant collection, valid collections are ["CollectionMutableState" "CollectionHistory" "CollectionDomain" "CollectionStale"]: some_unknown_invariant_collection`,
+		},
+		{
+			name: "invariant plugin not found",
+			testSetup: func(td *cliTestData) *cli.Context {
+				return clitest.NewCLIContext(t, td.app,
+					clitest.StringArgument("scan_type", "ConcreteExecutionType"),
+					clitest.IntArgument("number_of_shards", 16384),
+					clitest.StringSliceArgument("invariant_collection", "CollectionHistory"),
+					clitest.StringSliceArgument("plugin", "testdata/non-existant-plugin.so"),
+				)
+			},
+			errContains: "could not load invariant plugin",
 		},
 		{
 			name: "input file not found",
@@ -97,7 +137,7 @@ func TestAdminDBScanErrorCases(t *testing.T) {
 					clitest.StringArgument("scan_type", "ConcreteExecutionType"),
 					clitest.IntArgument("number_of_shards", 16384),
 					clitest.StringSliceArgument("invariant_collection", "CollectionHistory"),
-					clitest.StringArgument("input_file", "testdata/non-existant-file.json"),
+					clitest.StringSliceArgument("input_file", "testdata/non-existant-file.json"),
 				)
 			},
 			errContains: "Input file not found",
@@ -109,7 +149,7 @@ func TestAdminDBScanErrorCases(t *testing.T) {
 					clitest.StringArgument("scan_type", "ConcreteExecutionType"),
 					clitest.IntArgument("number_of_shards", 16384),
 					clitest.StringSliceArgument("invariant_collection", "CollectionHistory"),
-					clitest.StringArgument("input_file", "testdata/scan_input_empty.json"),
+					clitest.StringSliceArgument("input_file", "testdata/scan_input_empty.json"),
 				)
 			},
 			errContains: "Input file contained no data to scan",
@@ -121,7 +161,7 @@ func TestAdminDBScanErrorCases(t *testing.T) {
 					clitest.StringArgument("scan_type", "ConcreteExecutionType"),
 					clitest.IntArgument("number_of_shards", 16384),
 					clitest.StringSliceArgument("invariant_collection", "CollectionHistory"),
-					clitest.StringArgument("input_file", "testdata/scan_input_bad_data.json"),
+					clitest.StringSliceArgument("input_file", "testdata/scan_input_bad_data.json"),
 				)
 			},
 			errContains: "Error decoding input file",
@@ -137,7 +177,7 @@ func TestAdminDBScanErrorCases(t *testing.T) {
 					clitest.StringArgument("scan_type", "ConcreteExecutionType"),
 					clitest.IntArgument("number_of_shards", 16384),
 					clitest.StringSliceArgument("invariant_collection", "CollectionHistory"),
-					clitest.StringArgument("input_file", "testdata/scan_input.json"),
+					clitest.StringSliceArgument("input_file", "testdata/scan_input.json"),
 				)
 			},
 			errContains: "Execution check failed: initialize execution manager: assert.AnError general error for testing",
@@ -160,7 +200,7 @@ func TestAdminDBScanErrorCases(t *testing.T) {
 					clitest.StringArgument("scan_type", "ConcreteExecutionType"),
 					clitest.IntArgument("number_of_shards", 16384),
 					clitest.StringSliceArgument("invariant_collection", "CollectionHistory"),
-					clitest.StringArgument("input_file", "testdata/scan_input.json"),
+					clitest.StringSliceArgument("input_file", "testdata/scan_input.json"),
 				)
 			},
 			errContains: "Execution check failed: initialize history manager: assert.AnError general error for testing",
@@ -188,7 +228,7 @@ func TestAdminDBScanErrorCases(t *testing.T) {
 					clitest.StringArgument("scan_type", "ConcreteExecutionType"),
 					clitest.IntArgument("number_of_shards", 16384),
 					clitest.StringSliceArgument("invariant_collection", "CollectionHistory"),
-					clitest.StringArgument("input_file", "testdata/scan_input.json"),
+					clitest.StringSliceArgument("input_file", "testdata/scan_input.json"),
 				)
 			},
 			errContains: "Execution check failed: fetching execution: assert.AnError general error for testing",
@@ -213,6 +253,7 @@ func TestAdminDBScanErrorCases(t *testing.T) {
 func TestAdminDBScan(t *testing.T) {
 	td := newCLITestData(t)
 
+	expectHistoryManager(td)
 	expectWorkFlow(td, "test-workflow-id1")
 	expectWorkFlow(td, "test-workflow-id2")
 	expectWorkFlow(td, "test-workflow-id3")
@@ -221,7 +262,7 @@ func TestAdminDBScan(t *testing.T) {
 		clitest.StringArgument("scan_type", "CurrentExecutionType"),
 		clitest.IntArgument("number_of_shards", 16384),
 		clitest.StringSliceArgument("invariant_collection", "CollectionMutableState"),
-		clitest.StringArgument("input_file", "testdata/scan_input.json"),
+		clitest.StringSliceArgument("input_file", "testdata/scan_input.json"),
 	)
 
 	err := AdminDBScan(cliCtx)
@@ -230,6 +271,169 @@ func TestAdminDBScan(t *testing.T) {
 	assert.Equal(t, expectedAdminDBScanOutput, td.ioHandler.outputBytes.String())
 }
 
+func TestAdminDBScanFromStdin(t *testing.T) {
+	input, err := os.ReadFile("testdata/scan_input.json")
+	require.NoError(t, err)
+
+	for _, inputFile := range []string{"", "-"} {
+		t.Run(fmt.Sprintf("input_file=%q", inputFile), func(t *testing.T) {
+			td := newCLITestData(t)
+			td.ioHandler.input = bytes.NewReader(input)
+
+			expectHistoryManager(td)
+			expectWorkFlow(td, "test-workflow-id1")
+			expectWorkFlow(td, "test-workflow-id2")
+			expectWorkFlow(td, "test-workflow-id3")
+
+			cliCtx := clitest.NewCLIContext(t, td.app,
+				clitest.StringArgument("scan_type", "CurrentExecutionType"),
+				clitest.IntArgument("number_of_shards", 16384),
+				clitest.StringSliceArgument("invariant_collection", "CollectionMutableState"),
+				clitest.StringSliceArgument("input_file", inputFile),
+			)
+
+			err := AdminDBScan(cliCtx)
+			assert.NoError(t, err)
+			assert.Equal(t, expectedAdminDBScanOutput, td.ioHandler.outputBytes.String())
+		})
+	}
+}
+
+func TestAdminDBScanGzip(t *testing.T) {
+	input, err := os.ReadFile("testdata/scan_input.json")
+	require.NoError(t, err)
+	inputFile := filepath.Join(t.TempDir(), "scan_input.json.gz")
+	require.NoError(t, os.WriteFile(inputFile, gzipBytes(t, input), 0644))
+
+	td := newCLITestData(t)
+	expectHistoryManager(td)
+	expectWorkFlow(td, "test-workflow-id1")
+	expectWorkFlow(td, "test-workflow-id2")
+	expectWorkFlow(td, "test-workflow-id3")
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument("scan_type", "CurrentExecutionType"),
+		clitest.IntArgument("number_of_shards", 16384),
+		clitest.StringSliceArgument("invariant_collection", "CollectionMutableState"),
+		clitest.StringSliceArgument("input_file", inputFile),
+		clitest.BoolArgument(FlagOutputGzip, true),
+	)
+
+	require.NoError(t, AdminDBScan(cliCtx))
+	assert.Equal(t, expectedAdminDBScanOutput, gunzipString(t, td.ioHandler.outputBytes.Bytes()))
+}
+
+func TestAdminDBScanMultipleInputs(t *testing.T) {
+	input, err := os.ReadFile("testdata/scan_input.json")
+	require.NoError(t, err)
+	lines := bytes.SplitAfter(input, []byte("\n"))
+	dir := t.TempDir()
+	// the first file has no trailing newline and the second one is compressed
+	firstFile := filepath.Join(dir, "first.json")
+	require.NoError(t, os.WriteFile(firstFile, bytes.TrimSpace(lines[0]), 0644))
+	secondFile := filepath.Join(dir, "second.json.gz")
+	require.NoError(t, os.WriteFile(secondFile, gzipBytes(t, bytes.Join(lines[1:], nil)), 0644))
+
+	td := newCLITestData(t)
+	expectHistoryManager(td)
+	expectWorkFlow(td, "test-workflow-id1")
+	expectWorkFlow(td, "test-workflow-id2")
+	expectWorkFlow(td, "test-workflow-id3")
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument("scan_type", "CurrentExecutionType"),
+		clitest.IntArgument("number_of_shards", 16384),
+		clitest.StringSliceArgument("invariant_collection", "CollectionMutableState"),
+		clitest.StringSliceArgument("input_file", firstFile, secondFile),
+	)
+
+	require.NoError(t, AdminDBScan(cliCtx))
+	assert.Equal(t, expectedAdminDBScanOutput, td.ioHandler.outputBytes.String())
+
+	td = newCLITestData(t)
+	cliCtx = clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument("scan_type", "CurrentExecutionType"),
+		clitest.IntArgument("number_of_shards", 16384),
+		clitest.StringSliceArgument("invariant_collection", "CollectionMutableState"),
+		clitest.StringSliceArgument("input_file", firstFile, filepath.Join(dir, "missing.json")),
+	)
+	assert.ErrorContains(t, AdminDBScan(cliCtx), "missing.json")
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func gunzipString(t *testing.T, data []byte) string {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(decompressed)
+}
+
+func TestAdminDBScanOnlyCorrupted(t *testing.T) {
+	td := newCLITestData(t)
+
+	expectHistoryManager(td)
+	expectWorkFlow(td, "test-workflow-id1")
+	expectWorkFlowExists(td, "test-workflow-id2", false)
+	expectWorkFlow(td, "test-workflow-id3")
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument("scan_type", "CurrentExecutionType"),
+		clitest.IntArgument("number_of_shards", 16384),
+		clitest.StringSliceArgument("invariant_collection", "CollectionMutableState"),
+		clitest.StringSliceArgument("input_file", "testdata/scan_input.json"),
+		clitest.BoolArgument(FlagOnlyCorrupted, true),
+	)
+
+	err := AdminDBScan(cliCtx)
+	assert.NoError(t, err)
+
+	var out store.ScanOutputEntity
+	require.NoError(t, json.Unmarshal(td.ioHandler.outputBytes.Bytes(), &out))
+	assert.Equal(t, "test-workflow-id2", out.Execution.(map[string]interface{})["WorkflowID"])
+	assert.Equal(t, invariant.CheckResultTypeCorrupted, out.Result.CheckResultType)
+}
+
+func TestAdminDBScanSharesShardPersistence(t *testing.T) {
+	td := newCLITestData(t)
+	expectHistoryManager(td)
+
+	// with a single shard all the executions of the input share its execution manager
+	mockExecutionManager := persistence.NewMockExecutionManager(td.ctrl)
+	mockExecutionManager.EXPECT().Close().Times(1)
+	td.mockManagerFactory.EXPECT().
+		initializeExecutionManager(gomock.Any(), 0).
+		Return(mockExecutionManager, nil).
+		Times(1)
+	mockExecutionManager.EXPECT().GetCurrentExecution(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetCurrentExecutionResponse{
+			RunID: "test-run-id1",
+			State: persistence.WorkflowStateCompleted,
+		}, nil).
+		Times(3)
+	mockExecutionManager.EXPECT().GetShardID().Return(0).Times(3)
+	mockExecutionManager.EXPECT().IsWorkflowExecutionExists(gomock.Any(), gomock.Any()).
+		Return(&persistence.IsWorkflowExecutionExistsResponse{Exists: true}, nil).
+		Times(3)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument("scan_type", "CurrentExecutionType"),
+		clitest.IntArgument("number_of_shards", 1),
+		clitest.StringSliceArgument("invariant_collection", "CollectionMutableState"),
+		clitest.StringSliceArgument("input_file", "testdata/scan_input.json"),
+	)
+
+	assert.NoError(t, AdminDBScan(cliCtx))
+}
+
 // The expected output does not have any newlines or tabs
 // so we use strings.Join(strings.Fields()) to remove them
 var expectedAdminDBScanOutput = strings.Join(strings.Fields(`
@@ -277,7 +481,21 @@ var expectedAdminDBScanOutput = strings.Join(strings.Fields(`
 }`,
 ), "")
 
-func expectWorkFlow(td *cliTestData, workflowID string) {
+// expectHistoryManager expects the history manager shared by all the shards of a scan
+func expectHistoryManager(td *cliTestData) {
+	mockHistoryManager := persistence.NewMockHistoryManager(td.ctrl)
+	mockHistoryManager.EXPECT().Close().Times(1)
+	td.mockManagerFactory.EXPECT().
+		initializeHistoryManager(gomock.Any()).
+		Return(mockHistoryManager, nil).
+		Times(1)
+}
+
+func expectWorkFlow(td *cliTestData, workflowID string) *persistence.MockExecutionManager {
+	return expectWorkFlowExists(td, workflowID, true)
+}
+
+func expectWorkFlowExists(td *cliTestData, workflowID string, exists bool) *persistence.MockExecutionManager {
 	shardID1 := common.WorkflowIDToHistoryShard(workflowID, 16384)
 	mockExecutionManager := persistence.NewMockExecutionManager(td.ctrl)
 	mockExecutionManager.EXPECT().Close().Times(1)
@@ -286,25 +504,135 @@ func expectWorkFlow(td *cliTestData, workflowID string) {
 		Return(mockExecutionManager, nil).
 		Times(1)
 
-	mockHistoryManager := persistence.NewMockHistoryManager(td.ctrl)
-	mockHistoryManager.EXPECT().Close().Times(1)
-	td.mockManagerFactory.EXPECT().
-		initializeHistoryManager(gomock.Any()).
-		Return(mockHistoryManager, nil).
-		Times(1)
-
+	// a missing concrete execution is verified by reading the current execution again
+	currentExecutionReads := 1
+	if !exists {
+		currentExecutionReads = 2
+	}
 	mockExecutionManager.EXPECT().GetCurrentExecution(gomock.Any(), gomock.Any()).
 		Return(&persistence.GetCurrentExecutionResponse{
 			RunID: "test-run-id1",
 			State: persistence.WorkflowStateCompleted,
 		}, nil).
-		Times(1)
+		Times(currentExecutionReads)
 	mockExecutionManager.EXPECT().GetShardID().Return(shardID1).Times(1)
 	mockExecutionManager.EXPECT().IsWorkflowExecutionExists(gomock.Any(), gomock.Any()).
 		Return(&persistence.IsWorkflowExecutionExistsResponse{
-			Exists: true,
+			Exists: exists,
 		}, nil).
 		Times(1)
+	return mockExecutionManager
+}
+
+func TestAdminDBScanStartTimeRange(t *testing.T) {
+	td := newCLITestData(t)
+	started := func(startTime time.Time) *persistence.GetWorkflowExecutionResponse {
+		return &persistence.GetWorkflowExecutionResponse{State: &persistence.WorkflowMutableState{
+			ExecutionInfo: &persistence.WorkflowExecutionInfo{StartTimestamp: startTime},
+		}}
+	}
+
+	expectHistoryManager(td)
+	// started in the range
+	expectWorkFlow(td, "test-workflow-id1").EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(started(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)), nil)
+	// started before the range
+	skippedExecutionManager := persistence.NewMockExecutionManager(td.ctrl)
+	skippedExecutionManager.EXPECT().Close()
+	skippedExecutionManager.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(started(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)), nil)
+	td.mockManagerFactory.EXPECT().
+		initializeExecutionManager(gomock.Any(), common.WorkflowIDToHistoryShard("test-workflow-id2", 16384)).
+		Return(skippedExecutionManager, nil)
+	// without mutable state, kept for the invariants to report
+	expectWorkFlow(td, "test-workflow-id3").EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(nil, &types.EntityNotExistsError{})
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument("scan_type", "CurrentExecutionType"),
+		clitest.IntArgument("number_of_shards", 16384),
+		clitest.StringSliceArgument("invariant_collection", "CollectionMutableState"),
+		clitest.StringSliceArgument("input_file", "testdata/scan_input.json"),
+		clitest.StringArgument(FlagStartTime, "2024-01-01T00:00:00Z"),
+		clitest.StringArgument(FlagEndTime, "2024-02-01T00:00:00Z"),
+	)
+
+	require.NoError(t, AdminDBScan(cliCtx))
+	output := td.ioHandler.outputBytes.String()
+	assert.Contains(t, output, `"WorkflowID":"test-workflow-id1"`)
+	assert.NotContains(t, output, `"WorkflowID":"test-workflow-id2"`)
+	assert.Contains(t, output, `"WorkflowID":"test-workflow-id3"`)
+}
+
+func TestAdminDBValidateShard(t *testing.T) {
+	td := newCLITestData(t)
+	expectHistoryManager(td)
+
+	mockExecutionManager := persistence.NewMockExecutionManager(td.ctrl)
+	mockExecutionManager.EXPECT().Close().Times(1)
+	td.mockManagerFactory.EXPECT().
+		initializeExecutionManager(gomock.Any(), 3).
+		Return(mockExecutionManager, nil).
+		Times(1)
+	listed := func(workflowID string) *persistence.ListConcreteExecutionsEntity {
+		return &persistence.ListConcreteExecutionsEntity{ExecutionInfo: &persistence.WorkflowExecutionInfo{
+			DomainID:   "test-domain-id1",
+			WorkflowID: workflowID,
+			RunID:      "test-run-id1",
+		}}
+	}
+	gomock.InOrder(
+		mockExecutionManager.EXPECT().ListConcreteExecutions(gomock.Any(), &persistence.ListConcreteExecutionsRequest{PageSize: 1000}).
+			Return(&persistence.ListConcreteExecutionsResponse{
+				Executions: []*persistence.ListConcreteExecutionsEntity{listed("test-workflow-id1")},
+				PageToken:  []byte("next"),
+			}, nil),
+		mockExecutionManager.EXPECT().ListConcreteExecutions(gomock.Any(), &persistence.ListConcreteExecutionsRequest{PageSize: 1000, PageToken: []byte("next")}).
+			Return(&persistence.ListConcreteExecutionsResponse{
+				Executions: []*persistence.ListConcreteExecutionsEntity{listed("test-workflow-id2")},
+			}, nil),
+	)
+	mockExecutionManager.EXPECT().GetCurrentExecution(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetCurrentExecutionResponse{
+			RunID: "test-run-id1",
+			State: persistence.WorkflowStateCompleted,
+		}, nil).
+		AnyTimes()
+	mockExecutionManager.EXPECT().GetShardID().Return(3).AnyTimes()
+	gomock.InOrder(
+		mockExecutionManager.EXPECT().IsWorkflowExecutionExists(gomock.Any(), gomock.Any()).
+			Return(&persistence.IsWorkflowExecutionExistsResponse{Exists: true}, nil),
+		mockExecutionManager.EXPECT().IsWorkflowExecutionExists(gomock.Any(), gomock.Any()).
+			Return(&persistence.IsWorkflowExecutionExistsResponse{Exists: false}, nil),
+	)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.IntArgument(FlagShardID, 3),
+		clitest.StringArgument("scan_type", "CurrentExecutionType"),
+		clitest.StringSliceArgument("invariant_collection", "CollectionMutableState"),
+		clitest.BoolArgument(FlagOnlyCorrupted, true),
+	)
+	require.NoError(t, AdminDBValidateShard(cliCtx))
+
+	var out store.ScanOutputEntity
+	require.NoError(t, json.Unmarshal(td.ioHandler.outputBytes.Bytes(), &out))
+	assert.Equal(t, "test-workflow-id2", out.Execution.(map[string]interface{})["WorkflowID"])
+	assert.Equal(t, invariant.CheckResultTypeCorrupted, out.Result.CheckResultType)
+}
+
+func TestGetStartTimeRange(t *testing.T) {
+	td := newCLITestData(t)
+	_, err := getStartTimeRange(clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagStartTime, "2024-02-01T00:00:00Z"),
+		clitest.StringArgument(FlagEndTime, "2024-01-01T00:00:00Z"),
+	))
+	assert.ErrorContains(t, err, "--end_time 2024-01-01 00:00:00 +0000 UTC is before --start_time")
+
+	r, err := getStartTimeRange(clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagStartTime, "2024-01-01T00:00:00Z")))
+	require.NoError(t, err)
+	assert.True(t, r.isSet())
+	assert.True(t, r.contains(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, r.contains(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)))
 }
 
 func TestAdminDBScanUnsupportedWorkflow(t *testing.T) {
@@ -330,6 +658,137 @@ func TestAdminDBScanUnsupportedWorkflow(t *testing.T) {
 	assert.Equal(t, expectedAdminDBScanUnsupportedOutput, string(actual))
 }
 
+func TestAdminDBScanUnsupportedWorkflowGzip(t *testing.T) {
+	td := newCLITestData(t)
+
+	outPutFile := filepath.Join(t.TempDir(), "unsupported.txt.gz")
+
+	expectShard(td, 123)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument("output_filename", outPutFile),
+		clitest.IntArgument("lower_shard_bound", 123),
+		clitest.IntArgument("upper_shard_bound", 123),
+	)
+
+	require.NoError(t, AdminDBScanUnsupportedWorkflow(cliCtx))
+
+	actual, err := os.ReadFile(outPutFile)
+	require.NoError(t, err)
+	lines := strings.SplitAfter(expectedAdminDBScanUnsupportedOutput, "\n")
+	assert.Equal(t, strings.Join(lines[:3], ""), gunzipString(t, actual))
+}
+
+func TestAdminDBScanUnsupportedWorkflowResume(t *testing.T) {
+	td := newCLITestData(t)
+
+	outPutFile := createTempFileWithContent(t, "")
+
+	// shards 123 and 124 were completed by the interrupted run
+	expectShard(td, 125)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument("output_filename", outPutFile),
+		clitest.IntArgument("lower_shard_bound", 123),
+		clitest.IntArgument("upper_shard_bound", 125),
+		clitest.IntArgument(FlagResumeFromShard, 125),
+	)
+
+	require.NoError(t, AdminDBScanUnsupportedWorkflow(cliCtx))
+
+	actual, err := os.ReadFile(outPutFile)
+	require.NoError(t, err)
+	lines := strings.SplitAfter(expectedAdminDBScanUnsupportedOutput, "\n")
+	assert.Equal(t, strings.Join(lines[6:], ""), string(actual))
+}
+
+func TestAdminDBScanUnsupportedWorkflowPageSize(t *testing.T) {
+	td := newCLITestData(t)
+	mockExecutionManager := persistence.NewMockExecutionManager(td.ctrl)
+	mockExecutionManager.EXPECT().Close()
+	mockExecutionManager.EXPECT().ListConcreteExecutions(gomock.Any(), &persistence.ListConcreteExecutionsRequest{PageSize: 20000}).
+		Return(&persistence.ListConcreteExecutionsResponse{}, nil)
+	td.mockManagerFactory.EXPECT().initializeExecutionManager(gomock.Any(), 123).Return(mockExecutionManager, nil)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.IntArgument("lower_shard_bound", 123),
+		clitest.IntArgument("upper_shard_bound", 123),
+		clitest.IntArgument(FlagPageSize, 20000),
+	)
+	require.NoError(t, AdminDBScanUnsupportedWorkflow(cliCtx))
+
+	cliCtx = clitest.NewCLIContext(t, td.app,
+		clitest.IntArgument("lower_shard_bound", 123),
+		clitest.IntArgument("upper_shard_bound", 123),
+		clitest.IntArgument(FlagPageSize, 0),
+	)
+	assert.ErrorContains(t, AdminDBScanUnsupportedWorkflow(cliCtx), "--pagesize must be positive")
+}
+
+func TestAdminDBScanInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	td := newCLITestData(t)
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument("scan_type", "CurrentExecutionType"),
+		clitest.IntArgument("number_of_shards", 16384),
+		clitest.StringSliceArgument("invariant_collection", "CollectionMutableState"),
+		clitest.StringSliceArgument("input_file", "testdata/scan_input.json"),
+	)
+	cliCtx.Context = ctx
+
+	// no execution is checked once the command is interrupted
+	assert.ErrorContains(t, AdminDBScan(cliCtx), "Scan interrupted")
+	assert.Empty(t, td.consoleOutput())
+
+	td = newCLITestData(t)
+	cliCtx = clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument("output_filename", createTempFileWithContent(t, "")),
+		clitest.IntArgument("lower_shard_bound", 123),
+		clitest.IntArgument("upper_shard_bound", 125),
+	)
+	cliCtx.Context = ctx
+
+	assert.ErrorContains(t, AdminDBScanUnsupportedWorkflow(cliCtx), "Scan interrupted")
+}
+
+func TestAdminDBScanUnsupportedWorkflowPageContext(t *testing.T) {
+	td := newCLITestData(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockExecutionManager := persistence.NewMockExecutionManager(td.ctrl)
+	mockExecutionManager.EXPECT().Close().Times(1)
+	mockExecutionManager.EXPECT().ListConcreteExecutions(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(pageCtx context.Context, _ *persistence.ListConcreteExecutionsRequest) (*persistence.ListConcreteExecutionsResponse, error) {
+			deadline, ok := pageCtx.Deadline()
+			require.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(5*time.Second), deadline, time.Second)
+			// the command is interrupted while the shard is being listed
+			cancel()
+			return &persistence.ListConcreteExecutionsResponse{
+				Executions: []*persistence.ListConcreteExecutionsEntity{createListConcreteExecutionsEntity(1, 123)},
+				PageToken:  []byte("some-next-page-token"),
+			}, nil
+		})
+	mockExecutionManager.EXPECT().ListConcreteExecutions(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(pageCtx context.Context, _ *persistence.ListConcreteExecutionsRequest) (*persistence.ListConcreteExecutionsResponse, error) {
+			return nil, pageCtx.Err()
+		})
+	td.mockManagerFactory.EXPECT().initializeExecutionManager(gomock.Any(), 123).Return(mockExecutionManager, nil)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument("output_filename", createTempFileWithContent(t, "")),
+		clitest.IntArgument("lower_shard_bound", 123),
+		clitest.IntArgument("upper_shard_bound", 125),
+		clitest.IntArgument(FlagContextTimeout, 5),
+	)
+	cliCtx.Context = ctx
+
+	assert.ErrorContains(t, AdminDBScanUnsupportedWorkflow(cliCtx), "Scan interrupted")
+}
+
 func expectShard(td *cliTestData, shardID int) {
 	mockExecutionManager := persistence.NewMockExecutionManager(td.ctrl)
 	mockExecutionManager.EXPECT().Close().Times(1)
@@ -388,3 +847,97 @@ cadence --address <host>:<port> --domain <125-test-domain-id1> workflow reset --
 cadence --address <host>:<port> --domain <125-test-domain-id2> workflow reset --wid 125-test-workflow-id2 --rid 125-test-run-id2 --reset_type LastDecisionCompleted --reason 'release 0.16 upgrade'
 cadence --address <host>:<port> --domain <125-test-domain-id3> workflow reset --wid 125-test-workflow-id3 --rid 125-test-run-id3 --reset_type LastDecisionCompleted --reason 'release 0.16 upgrade'
 `
+
+func TestSkipInvariants(t *testing.T) {
+	collections := []invariant.Collection{invariant.CollectionHistory, invariant.CollectionMutableState}
+	factory := func(persistence.Retryer, cache.DomainCache) invariant.Invariant {
+		panic("plugin invariants must not be built to be skipped")
+	}
+	plugin := invariantPlugin{name: "plugin_invariant", factory: factory}
+	unnamedPlugin := invariantPlugin{factory: factory}
+
+	kept, plugins, err := skipInvariants(executions.ConcreteExecutionType, collections, nil, []string{string(invariant.HistoryExists)})
+	require.NoError(t, err)
+	assert.Equal(t, []invariant.Collection{invariant.CollectionMutableState}, kept)
+	assert.Empty(t, plugins)
+
+	kept, plugins, err = skipInvariants(executions.ConcreteExecutionType, collections, []invariantPlugin{plugin, unnamedPlugin}, []string{"plugin_invariant"})
+	require.NoError(t, err)
+	assert.Equal(t, collections, kept)
+	assert.Len(t, plugins, 1, "plugin invariants are skipped by name too, unnamed ones are kept")
+
+	_, _, err = skipInvariants(executions.ConcreteExecutionType, collections, nil, []string{"unknown"})
+	assert.ErrorContains(t, err, `"unknown" is not one of the invariants for this scan`)
+
+	_, _, err = skipInvariants(executions.ConcreteExecutionType, collections, []invariantPlugin{unnamedPlugin}, []string{"unknown"})
+	assert.ErrorContains(t, err, "plugin invariants can only be skipped when the plugin exports their InvariantNames")
+
+	_, _, err = skipInvariants(executions.ConcreteExecutionType, collections, nil, []string{string(invariant.HistoryExists), string(invariant.OpenCurrentExecution)})
+	assert.ErrorContains(t, err, "all invariants are skipped")
+}
+
+func TestInvariantFactoriesFromSymbol(t *testing.T) {
+	var fn executions.InvariantFactory = func(persistence.Retryer, cache.DomainCache) invariant.Invariant { return nil }
+	fns := []executions.InvariantFactory{fn, fn}
+	plainFn := func(persistence.Retryer, cache.DomainCache) invariant.Invariant { return nil }
+
+	got, err := invariantFactoriesFromSymbol(plainFn)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	got, err = invariantFactoriesFromSymbol(&fn)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	got, err = invariantFactoriesFromSymbol(&fns)
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	_, err = invariantFactoriesFromSymbol("not a factory")
+	assert.ErrorContains(t, err, "unsupported type string")
+}
+
+func TestInvariantNamesFromSymbol(t *testing.T) {
+	names := []string{"a", "b"}
+	got, err := invariantNamesFromSymbol(&names, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, names, got)
+
+	_, err = invariantNamesFromSymbol(&names, 1)
+	assert.ErrorContains(t, err, "symbol InvariantNames has 2 names for 1 invariants")
+
+	_, err = invariantNamesFromSymbol("a", 1)
+	assert.ErrorContains(t, err, "unsupported type string")
+}
+
+func TestCheckExecutionLogsExecutionFields(t *testing.T) {
+	td := newCLITestData(t)
+	expectHistoryManager(td)
+	expectWorkFlow(td, "test-workflow-id1")
+
+	core, logs := observer.New(zap.DebugLevel)
+	var invariantLogger *zap.Logger
+	invariantsFn := func(logger *zap.Logger) []executions.InvariantFactory {
+		invariantLogger = logger
+		return executions.CurrentExecutionType.ToInvariants([]invariant.Collection{invariant.CollectionMutableState}, logger)
+	}
+
+	cliCtx := clitest.NewCLIContext(t, td.app)
+	retryers := newShardRetryers(cliCtx)
+	defer retryers.Close()
+	_, _, err := checkExecution(cliCtx, retryers, common.WorkflowIDToHistoryShard("test-workflow-id1", 16384), fetcher.ExecutionRequest{
+		DomainID:   "test-domain-id1",
+		WorkflowID: "test-workflow-id1",
+		RunID:      "test-run-id1",
+	}, invariantsFn, zap.New(core), executions.CurrentExecutionType.ToExecutionFetcher())
+	require.NoError(t, err)
+
+	invariantLogger.Info("from invariant")
+	require.Equal(t, 2, logs.Len())
+	for _, entry := range logs.All() {
+		fields := entry.ContextMap()
+		assert.Equal(t, "test-workflow-id1", fields["WorkflowID"])
+		assert.Equal(t, "test-run-id1", fields["RunID"])
+		assert.Equal(t, int64(common.WorkflowIDToHistoryShard("test-workflow-id1", 16384)), fields["ShardID"])
+	}
+}
@@ -313,7 +313,7 @@ func generateESDoc(msg *indexer.Message) (map[string]interface{}, error) {
 		case indexer.FieldTypeBinary:
 			doc[k] = v.GetBinaryData()
 		default:
-			return nil, fmt.Errorf("Unknown field type %v", nil)
+			return nil, fmt.Errorf("Unknown field type %v", v.GetType())
 		}
 	}
 	return doc, nil
@@ -23,8 +23,12 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"os/user"
+	"strings"
 	"time"
 
 	"github.com/pborman/uuid"
@@ -42,11 +46,20 @@ const (
 	defaultBatchFailoverSize                = 20
 	defaultBatchFailoverWaitTimeInSeconds   = 30
 	defaultFailoverWorkflowTimeoutInSeconds = 1200
+
+	failoverMemoKeyForTag = "tag"
+
+	failoverDomainsPageSize = 200
 )
 
 var (
 	uuidFn        = uuid.New
 	getOperatorFn = getOperator
+
+	// pause and resume are signals handled asynchronously by the failover workflow,
+	// so its state is queried a few times before reporting the result
+	failoverStateCheckAttempts = 5
+	failoverStateCheckInterval = time.Second
 )
 
 type startParams struct {
@@ -54,11 +67,43 @@ type startParams struct {
 	sourceCluster                  string
 	batchFailoverSize              int
 	batchFailoverWaitTimeInSeconds int
+	batchFailoverWaitJitter        int
 	failoverWorkflowTimeout        int
 	failoverTimeout                int
 	domains                        []string
 	drillWaitTime                  int
 	cron                           string
+	outputFile                     string
+	tag                            string
+	requestID                      string
+	// checkTargetCluster refuses to start when the target cluster is not known
+	checkTargetCluster bool
+}
+
+// failoverQueryOutput is the query result of a failover workflow with the tag it was started with
+type failoverQueryOutput struct {
+	*failovermanager.QueryResult
+	Tag string `json:",omitempty"`
+}
+
+// FailoverStatusRow is the state of the failover or drill workflow in `admin cluster failover status`
+type FailoverStatusRow struct {
+	Workflow      string `header:"Workflow"`
+	State         string `header:"State"`
+	SourceCluster string `header:"Source"`
+	TargetCluster string `header:"Target"`
+	Progress      string `header:"Progress (success/failed/total)"`
+	Operator      string `header:"Operator"`
+}
+
+// failoverStartOutput is written to --output_filename when a failover workflow starts,
+// so that automation can drive follow-up pause/query/abort calls without scraping stdout.
+type failoverStartOutput struct {
+	WorkflowID    string   `json:"workflowID"`
+	RunID         string   `json:"runID"`
+	TargetCluster string   `json:"targetCluster"`
+	SourceCluster string   `json:"sourceCluster"`
+	Domains       []string `json:"domains"`
 }
 
 // AdminFailoverStart start failover workflow
@@ -71,16 +116,46 @@ func AdminFailoverStart(c *cli.Context) error {
 	if err != nil {
 		return commoncli.Problem("Required flag not found: ", err)
 	}
+	domains := c.StringSlice(FlagFailoverDomains)
+	if path := c.String(FlagFailoverDomainsFile); path != "" {
+		fileDomains, err := readFailoverDomainsFile(path)
+		if err != nil {
+			return commoncli.Problem("Failed to read domains file", err)
+		}
+		if len(domains) == 1 && strings.TrimSpace(domains[0]) == "" {
+			domains = nil
+		}
+		domains = append(domains, fileDomains...)
+	}
+	if c.Bool(FlagFailoverAllDomains) {
+		if c.IsSet(FlagFailoverDomains) || c.IsSet(FlagFailoverDomainsFile) {
+			return commoncli.Problem(fmt.Sprintf("--%s cannot be used with --%s or --%s",
+				FlagFailoverAllDomains, FlagFailoverDomains, FlagFailoverDomainsFile), nil)
+		}
+		domains, err = listFailoverDomains(c, sc)
+		if err != nil {
+			return commoncli.Problem("Failed to list domains", err)
+		}
+		if len(domains) == 0 {
+			return commoncli.Problem("No global domains to failover are active in "+sc, nil)
+		}
+		fmt.Printf("Resolved %d domain(s) active in %s: %s\n", len(domains), sc, strings.Join(domains, ", "))
+	}
 	params := &startParams{
 		targetCluster:                  tc,
 		sourceCluster:                  sc,
 		batchFailoverSize:              c.Int(FlagFailoverBatchSize),
 		batchFailoverWaitTimeInSeconds: c.Int(FlagFailoverWaitTime),
+		batchFailoverWaitJitter:        c.Int(FlagFailoverWaitJitter),
 		failoverTimeout:                c.Int(FlagFailoverTimeout),
 		failoverWorkflowTimeout:        c.Int(FlagExecutionTimeout),
-		domains:                        c.StringSlice(FlagFailoverDomains),
+		domains:                        domains,
 		drillWaitTime:                  c.Int(FlagFailoverDrillWaitTime),
 		cron:                           c.String(FlagCronSchedule),
+		outputFile:                     c.String(FlagOutputFilename),
+		tag:                            c.String(FlagTag),
+		checkTargetCluster:             !c.Bool(FlagSkipClusterCheck),
+		requestID:                      c.String(FlagRequestID),
 	}
 	return failoverStart(c, params)
 }
@@ -91,7 +166,11 @@ func AdminFailoverPause(c *cli.Context) error {
 	if err != nil {
 		return commoncli.Problem("Failed to pause failover workflow", err)
 	}
-	fmt.Println("Failover paused on " + getFailoverWorkflowID(c))
+	state, ok := confirmFailoverState(c, getFailoverWorkflowID(c), failovermanager.WorkflowPaused)
+	if !ok {
+		return nil
+	}
+	fmt.Printf("Failover paused on %v, workflow state: %v\n", getFailoverWorkflowID(c), state)
 	return nil
 }
 
@@ -101,7 +180,11 @@ func AdminFailoverResume(c *cli.Context) error {
 	if err != nil {
 		return commoncli.Problem("Failed to resume failover workflow", err)
 	}
-	fmt.Println("Failover resumed on " + getFailoverWorkflowID(c))
+	state, ok := confirmFailoverState(c, getFailoverWorkflowID(c), failovermanager.WorkflowRunning)
+	if !ok {
+		return nil
+	}
+	fmt.Printf("Failover resumed on %v, workflow state: %v\n", getFailoverWorkflowID(c), state)
 	return nil
 }
 
@@ -111,16 +194,32 @@ func AdminFailoverQuery(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	if c.Bool(FlagWatch) {
+		return watchFailover(c, client)
+	}
+	output, err := queryFailover(c, client)
+	if err != nil {
+		return err
+	}
+	printObject(c, output)
+	return nil
+}
+
+func queryFailover(c *cli.Context, client frontend.Client) (*failoverQueryOutput, error) {
 	tcCtx, cancel, err := newContext(c)
 	defer cancel()
 	if err != nil {
-		return commoncli.Problem("Error in creating context: ", err)
+		return nil, commoncli.Problem("Error in creating context: ", err)
 	}
 	workflowID := getFailoverWorkflowID(c)
 	runID := getRunID(c)
 	result, err := query(tcCtx, client, workflowID, runID)
 	if err != nil {
-		return err
+		var notExistsErr *types.EntityNotExistsError
+		if errors.As(err, &notExistsErr) {
+			return nil, commoncli.ProblemWithExitCode(exitCodeNotFound, "No failover workflow found (never run or expired from retention)", nil)
+		}
+		return nil, err
 	}
 	request := &types.DescribeWorkflowExecutionRequest{
 		Domain: common.SystemLocalDomainName,
@@ -132,12 +231,146 @@ func AdminFailoverQuery(c *cli.Context) error {
 
 	descResp, err := client.DescribeWorkflowExecution(tcCtx, request)
 	if err != nil {
-		return commoncli.Problem("Failed to describe workflow", err)
+		return nil, commoncli.Problem("Failed to describe workflow", err)
 	}
 	if isWorkflowTerminated(descResp) {
 		result.State = failovermanager.WorkflowAborted
 	}
-	prettyPrintJSONObject(getDeps(c).Output(), result)
+	output := &failoverQueryOutput{QueryResult: result}
+	if info := descResp.GetWorkflowExecutionInfo(); info != nil {
+		if tag, ok := info.Memo.GetFields()[failoverMemoKeyForTag]; ok {
+			if err := json.Unmarshal(tag, &output.Tag); err != nil {
+				return nil, commoncli.Problem("Failed to deserialize failover tag", err)
+			}
+		}
+	}
+	return output, nil
+}
+
+// watchFailover polls the failover workflow every --interval until it completes or is aborted,
+// printing the domains that succeeded or failed since the previous poll.
+func watchFailover(c *cli.Context, client frontend.Client) error {
+	interval := c.Duration(FlagInterval)
+	if interval <= 0 {
+		return commoncli.Problem(fmt.Sprintf("--%s must be positive", FlagInterval), nil)
+	}
+	stop := cancelOnInterrupt(c)
+	defer stop()
+
+	out := getDeps(c).Output()
+	var previous *failovermanager.QueryResult
+	for {
+		current, err := queryFailover(c, client)
+		if err != nil {
+			return err
+		}
+		printFailoverDiff(out, previous, current.QueryResult)
+		if current.State == failovermanager.WorkflowCompleted || current.State == failovermanager.WorkflowAborted {
+			return nil
+		}
+		previous = current.QueryResult
+
+		select {
+		case <-time.After(interval):
+		case <-c.Context.Done():
+			return commoncli.Problem("Interrupted", c.Context.Err())
+		}
+	}
+}
+
+// printFailoverDiff prints the domains that newly succeeded in green and newly failed in red since previous,
+// followed by the overall progress. The first poll has no previous result, so all domains are new.
+func printFailoverDiff(w io.Writer, previous, current *failovermanager.QueryResult) {
+	var seenSuccess, seenFailed map[string]bool
+	if previous != nil {
+		seenSuccess = domainSet(previous.SuccessDomains)
+		seenFailed = domainSet(previous.FailedDomains)
+	}
+	unchanged := 0
+	for _, domain := range current.SuccessDomains {
+		if seenSuccess[domain] {
+			unchanged++
+			continue
+		}
+		fmt.Fprintln(w, colorGreen("+ "+domain+" succeeded"))
+	}
+	for _, domain := range current.FailedDomains {
+		if seenFailed[domain] {
+			unchanged++
+			continue
+		}
+		fmt.Fprintln(w, colorRed("- "+domain+" failed"))
+	}
+	if unchanged > 0 {
+		fmt.Fprintln(w, colorFaint(fmt.Sprintf("  %d domains unchanged", unchanged)))
+	}
+
+	percent := 100.0
+	if current.TotalDomains > 0 {
+		percent = float64(current.Success+current.Failed) / float64(current.TotalDomains) * 100
+	}
+	fmt.Fprintf(w, "[%s] %d/%d domains processed (%.1f%%), %d succeeded, %d failed\n",
+		current.State, current.Success+current.Failed, current.TotalDomains, percent, current.Success, current.Failed)
+}
+
+func domainSet(domains []string) map[string]bool {
+	set := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		set[domain] = true
+	}
+	return set
+}
+
+// AdminFailoverStatus reports the state of both the failover and the drill workflows
+func AdminFailoverStatus(c *cli.Context) error {
+	client, err := getCadenceClient(c)
+	if err != nil {
+		return err
+	}
+	tcCtx, cancel, err := newContext(c)
+	if err != nil {
+		return commoncli.Problem("Error in creating context: ", err)
+	}
+	defer cancel()
+
+	var rows []FailoverStatusRow
+	inProgress := false
+	for _, workflowID := range []string{failovermanager.FailoverWorkflowID, failovermanager.DrillWorkflowID} {
+		row := FailoverStatusRow{Workflow: workflowID}
+		result, err := query(tcCtx, client, workflowID, "")
+		if err != nil {
+			var notExistsErr *types.EntityNotExistsError
+			if !errors.As(err, &notExistsErr) {
+				return err
+			}
+			row.State = "not found"
+			rows = append(rows, row)
+			continue
+		}
+		descResp, err := client.DescribeWorkflowExecution(tcCtx, &types.DescribeWorkflowExecutionRequest{
+			Domain:    common.SystemLocalDomainName,
+			Execution: &types.WorkflowExecution{WorkflowID: workflowID},
+		})
+		if err != nil {
+			return commoncli.Problem("Failed to describe workflow", err)
+		}
+		if isWorkflowTerminated(descResp) {
+			result.State = failovermanager.WorkflowAborted
+		}
+		inProgress = inProgress || isWorkflowRunning(result)
+		row.State = result.State
+		row.SourceCluster = result.SourceCluster
+		row.TargetCluster = result.TargetCluster
+		row.Progress = fmt.Sprintf("%d/%d/%d", result.Success, result.Failed, result.TotalDomains)
+		row.Operator = result.Operator
+		rows = append(rows, row)
+	}
+	if err := Render(c, rows, RenderOptions{DefaultTemplate: templateTable, Color: true}); err != nil {
+		return err
+	}
+	if !inProgress {
+		fmt.Fprintln(getDeps(c).Progress(), "No failover or drill is in progress")
+	}
 	return nil
 }
 
@@ -171,11 +404,51 @@ func AdminFailoverAbort(c *cli.Context) error {
 	if err != nil {
 		return commoncli.Problem("Failed to abort failover workflow", err)
 	}
+	if c.Bool(FlagWait) {
+		if err := waitForFailoverTerminated(c, client, workflowID, runID); err != nil {
+			return err
+		}
+	}
 
 	fmt.Println("Failover aborted")
 	return nil
 }
 
+// waitForFailoverTerminated describes the failover workflow until it is closed, since termination is asynchronous,
+// and fails if it is not terminated within --wait_timeout.
+func waitForFailoverTerminated(c *cli.Context, client frontend.Client, workflowID, runID string) error {
+	timeout := c.Duration(FlagWaitTimeout)
+	deadline := time.Now().Add(timeout)
+	for {
+		tcCtx, cancel, err := newContext(c)
+		if err != nil {
+			cancel()
+			return commoncli.Problem("Error in creating context: ", err)
+		}
+		descResp, err := client.DescribeWorkflowExecution(tcCtx, &types.DescribeWorkflowExecutionRequest{
+			Domain: common.SystemLocalDomainName,
+			Execution: &types.WorkflowExecution{
+				WorkflowID: workflowID,
+				RunID:      runID,
+			},
+		})
+		cancel()
+		if err != nil {
+			return commoncli.Problem("Failed to describe failover workflow", err)
+		}
+		if info := descResp.GetWorkflowExecutionInfo(); info != nil && info.CloseStatus != nil {
+			if !isWorkflowTerminated(descResp) {
+				return commoncli.Problem(fmt.Sprintf("Failover workflow closed as %v instead of terminated", info.GetCloseStatus()), nil)
+			}
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return commoncli.Problem(fmt.Sprintf("Failover workflow not terminated after --%s %v", FlagWaitTimeout, timeout), nil)
+		}
+		time.Sleep(failoverStateCheckInterval)
+	}
+}
+
 // AdminFailoverRollback rollback a failover run
 func AdminFailoverRollback(c *cli.Context) error {
 	client, err := getCadenceClient(c)
@@ -225,8 +498,10 @@ func AdminFailoverRollback(c *cli.Context) error {
 		domains:                        rollbackDomains,
 		batchFailoverSize:              c.Int(FlagFailoverBatchSize),
 		batchFailoverWaitTimeInSeconds: c.Int(FlagFailoverWaitTime),
+		batchFailoverWaitJitter:        c.Int(FlagFailoverWaitJitter),
 		failoverTimeout:                c.Int(FlagFailoverTimeout),
 		failoverWorkflowTimeout:        c.Int(FlagExecutionTimeout),
+		tag:                            c.String(FlagTag),
 	}
 	return failoverStart(c, params)
 }
@@ -298,6 +573,11 @@ func failoverStart(c *cli.Context, params *startParams) error {
 	if err := validateStartParams(params); err != nil {
 		return commoncli.Problem("Invalid input parameters", err)
 	}
+	if params.checkTargetCluster {
+		if err := checkFailoverTargetCluster(c, params.targetCluster); err != nil {
+			return err
+		}
+	}
 
 	workflowID := failovermanager.FailoverWorkflowID
 	targetCluster := params.targetCluster
@@ -321,19 +601,30 @@ func failoverStart(c *cli.Context, params *startParams) error {
 	if err != nil {
 		return commoncli.Problem("Error in creating context: ", err)
 	}
-	op, err := getOperatorFn()
-	if err != nil {
-		return commoncli.Problem("Error in getting operator: ", err)
+	op := c.String(FlagOperator)
+	if op == "" {
+		op, err = getOperatorFn()
+		if err != nil {
+			return commoncli.Problem("Error in getting operator: ", err)
+		}
 	}
-	memo, err := getWorkflowMemo(map[string]interface{}{
+	memoFields := map[string]interface{}{
 		common.MemoKeyForOperator: op,
-	})
+	}
+	if params.tag != "" {
+		memoFields[failoverMemoKeyForTag] = params.tag
+	}
+	memo, err := getWorkflowMemo(memoFields)
 	if err != nil {
 		return commoncli.Problem("Failed to serialize memo", err)
 	}
+	requestID := params.requestID
+	if requestID == "" {
+		requestID = uuidFn()
+	}
 	request := &types.StartWorkflowExecutionRequest{
 		Domain:                              common.SystemLocalDomainName,
-		RequestID:                           uuidFn(),
+		RequestID:                           requestID,
 		WorkflowID:                          workflowID,
 		WorkflowIDReusePolicy:               types.WorkflowIDReusePolicyAllowDuplicate.Ptr(),
 		TaskList:                            &types.TaskList{Name: failovermanager.TaskListName},
@@ -368,6 +659,7 @@ func failoverStart(c *cli.Context, params *startParams) error {
 		SourceCluster:                    sourceCluster,
 		BatchFailoverSize:                batchFailoverSize,
 		BatchFailoverWaitTimeInSeconds:   batchFailoverWaitTimeInSeconds,
+		BatchFailoverWaitJitterInSeconds: params.batchFailoverWaitJitter,
 		Domains:                          domains,
 		DrillWaitTime:                    drillWaitTime,
 		GracefulFailoverTimeoutInSeconds: gracefulFailoverTimeoutInSeconds,
@@ -384,6 +676,22 @@ func failoverStart(c *cli.Context, params *startParams) error {
 	fmt.Println("Failover workflow started")
 	fmt.Println("wid: " + workflowID)
 	fmt.Println("rid: " + wf.GetRunID())
+
+	if params.outputFile != "" {
+		data, err := json.MarshalIndent(failoverStartOutput{
+			WorkflowID:    request.WorkflowID,
+			RunID:         wf.GetRunID(),
+			TargetCluster: targetCluster,
+			SourceCluster: sourceCluster,
+			Domains:       domains,
+		}, "", "  ")
+		if err != nil {
+			return commoncli.Problem("Failed to serialize failover workflow info", err)
+		}
+		if err := os.WriteFile(params.outputFile, data, 0644); err != nil {
+			return commoncli.Problem("Failed to write failover workflow info to "+params.outputFile, err)
+		}
+	}
 	return nil
 }
 
@@ -400,7 +708,7 @@ func getOperator() (string, error) {
 		return "", fmt.Errorf("failed to get operator info %w", err)
 	}
 
-	return fmt.Sprintf("%s (username: %s)", user.Name, user.Username), nil
+	return fmt.Sprintf("%s (username: %s, host: %s)", user.Name, user.Username, getHostName()), nil
 }
 
 func isWorkflowTerminated(descResp *types.DescribeWorkflowExecutionResponse) bool {
@@ -438,22 +746,172 @@ func executePauseOrResume(c *cli.Context, workflowID string, isPause bool) error
 	return client.SignalWorkflowExecution(tcCtx, request)
 }
 
+// confirmFailoverState queries the failover workflow until it reports wantState, and returns the last reported state.
+// It prints a warning and returns false if the state is not reached after all attempts,
+// or as soon as the workflow reports it is complete or aborted since it will not change state anymore.
+func confirmFailoverState(c *cli.Context, workflowID string, wantState string) (string, bool) {
+	client, err := getCadenceClient(c)
+	if err != nil {
+		fmt.Printf("%s unable to verify failover workflow state: %v\n", colorRed("Warning:"), err)
+		return "", false
+	}
+	runID := getRunID(c)
+	lastState := "unknown"
+	for attempt := 0; attempt < failoverStateCheckAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(failoverStateCheckInterval)
+		}
+		tcCtx, cancel, err := newContext(c)
+		if err != nil {
+			fmt.Printf("%s unable to verify failover workflow state: %v\n", colorRed("Warning:"), err)
+			return "", false
+		}
+		result, err := query(tcCtx, client, workflowID, runID)
+		cancel()
+		if err != nil {
+			continue
+		}
+		lastState = result.State
+		if result.State == wantState {
+			return result.State, true
+		}
+		if result.State == failovermanager.WorkflowCompleted || result.State == failovermanager.WorkflowAborted {
+			fmt.Printf("%s signal was sent but failover workflow %v is already in state %v, the signal has no effect\n",
+				colorRed("Warning:"), workflowID, result.State)
+			return result.State, false
+		}
+	}
+	fmt.Printf("%s signal was sent but failover workflow %v did not report state %q after %v checks (last state: %v)\n",
+		colorRed("Warning:"), workflowID, wantState, failoverStateCheckAttempts, lastState)
+	return lastState, false
+}
+
+// listFailoverDomains lists the global domains active in sourceCluster which the failover workflow
+// would failover, i.e. the ones with cadence managed failover.
+func listFailoverDomains(c *cli.Context, sourceCluster string) ([]string, error) {
+	client, err := getCadenceClient(c)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel, err := newContext(c)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	var domains []string
+	var token []byte
+	for more := true; more; more = len(token) > 0 {
+		resp, err := client.ListDomains(ctx, &types.ListDomainsRequest{
+			PageSize:      failoverDomainsPageSize,
+			NextPageToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, domain := range resp.GetDomains() {
+			if !domain.GetIsGlobalDomain() ||
+				domain.ReplicationConfiguration.GetActiveClusterName() != sourceCluster ||
+				!strings.EqualFold(strings.TrimSpace(domain.GetDomainInfo().GetData()[common.DomainDataKeyForManagedFailover]), "true") {
+				continue
+			}
+			domains = append(domains, domain.GetDomainInfo().GetName())
+		}
+		token = resp.GetNextPageToken()
+	}
+	return domains, nil
+}
+
+// checkFailoverTargetCluster refuses a failover to a cluster which is not in the replication configuration
+// of any global domain, e.g. a misspelled one, and lists the known clusters.
+func checkFailoverTargetCluster(c *cli.Context, targetCluster string) error {
+	rows, err := listReplicationClusters(c)
+	if err != nil {
+		return commoncli.Problem(fmt.Sprintf("Failed to list the known clusters, use --%s to skip this check", FlagSkipClusterCheck), err)
+	}
+	clusters := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.Cluster == targetCluster {
+			return nil
+		}
+		clusters = append(clusters, row.Cluster)
+	}
+	return commoncli.Problem(fmt.Sprintf("Target cluster %q is not in the replication configuration of any global domain, known clusters are %q. Use --%s to skip this check",
+		targetCluster, clusters, FlagSkipClusterCheck), nil)
+}
+
+// dedupeFailoverDomains removes duplicate domains, keeping the first occurrence, and returns how many were removed.
+// Empty or whitespace-only names are rejected, except for a single empty value (e.g. --domains "") which means no domains.
+func dedupeFailoverDomains(domains []string) ([]string, int, error) {
+	if len(domains) == 1 && strings.TrimSpace(domains[0]) == "" {
+		return nil, 0, nil
+	}
+	var invalid []string
+	seen := make(map[string]struct{}, len(domains))
+	result := make([]string, 0, len(domains))
+	for i, domain := range domains {
+		domain = strings.TrimSpace(domain)
+		if domain == "" {
+			invalid = append(invalid, fmt.Sprintf("#%d %q", i+1, domains[i]))
+			continue
+		}
+		if _, ok := seen[domain]; ok {
+			continue
+		}
+		seen[domain] = struct{}{}
+		result = append(result, domain)
+	}
+	if len(invalid) > 0 {
+		return nil, 0, fmt.Errorf("empty domain names are not allowed, got %s", strings.Join(invalid, ", "))
+	}
+	return result, len(domains) - len(result), nil
+}
+
+// readFailoverDomainsFile reads one domain per line, skipping blank lines and # comments
+func readFailoverDomainsFile(path string) ([]string, error) {
+	// #nosec
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var domains []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains = append(domains, line)
+	}
+	return domains, nil
+}
+
 func validateStartParams(params *startParams) error {
 	if len(params.targetCluster) == 0 {
-		return fmt.Errorf("targetCluster is not provided: %v", nil)
+		return errors.New("targetCluster is not provided")
 	}
 	if len(params.sourceCluster) == 0 {
-		return fmt.Errorf("sourceCluster is not provided: %v", nil)
+		return errors.New("sourceCluster is not provided")
 	}
 	if params.targetCluster == params.sourceCluster {
-		return fmt.Errorf("targetCluster is same as sourceCluster: %v", nil)
+		return errors.New("targetCluster is same as sourceCluster")
 	}
+	domains, duplicates, err := dedupeFailoverDomains(params.domains)
+	if err != nil {
+		return err
+	}
+	if duplicates > 0 {
+		fmt.Printf("Removed %d duplicate domain(s) from the failover domains\n", duplicates)
+	}
+	params.domains = domains
 	if params.batchFailoverSize <= 0 {
 		params.batchFailoverSize = defaultBatchFailoverSize
 	}
 	if params.batchFailoverWaitTimeInSeconds <= 0 {
 		params.batchFailoverWaitTimeInSeconds = defaultBatchFailoverWaitTimeInSeconds
 	}
+	if params.batchFailoverWaitJitter < 0 {
+		return fmt.Errorf("batch wait jitter must not be negative, got %d", params.batchFailoverWaitJitter)
+	}
 	if params.failoverWorkflowTimeout <= 0 {
 		params.failoverWorkflowTimeout = defaultFailoverWorkflowTimeoutInSeconds
 	}
@@ -24,11 +24,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/yarpc"
 
@@ -36,6 +41,7 @@ import (
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/worker/failovermanager"
+	"github.com/uber/cadence/tools/cli/clitest"
 )
 
 func TestAdminFailoverStart(t *testing.T) {
@@ -192,6 +198,7 @@ func TestAdminFailoverStart(t *testing.T) {
 
 			// Set up mocks for the current test case
 			tc.mockFn(t, frontendCl)
+			expectReplicationClusters(frontendCl, "cluster1", "cluster2")
 
 			// Create mock app with clientFactoryMock, including any deps errors
 			app := NewCliApp(&clientFactoryMock{
@@ -218,6 +225,341 @@ func TestAdminFailoverStart(t *testing.T) {
 	}
 }
 
+func TestAdminFailoverStart_OutputFile(t *testing.T) {
+	oldUUIDFn := uuidFn
+	uuidFn = func() string { return "test-uuid" }
+	oldGetOperatorFn := getOperatorFn
+	getOperatorFn = func() (string, error) { return "test-user", nil }
+	defer func() {
+		uuidFn = oldUUIDFn
+		getOperatorFn = oldGetOperatorFn
+	}()
+
+	ctrl := gomock.NewController(t)
+	frontendCl := frontend.NewMockClient(ctrl)
+	frontendCl.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	frontendCl.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(&types.StartWorkflowExecutionResponse{RunID: "test-run-id"}, nil).Times(1)
+	expectReplicationClusters(frontendCl, "cluster1", "cluster2")
+
+	app := NewCliApp(&clientFactoryMock{
+		serverFrontendClient: frontendCl,
+	})
+
+	outputFile := filepath.Join(t.TempDir(), "failover.json")
+	err := app.Run([]string{"", "admin", "cluster", "failover", "start",
+		"--sc", "cluster1",
+		"--tc", "cluster2",
+		"--domains", "domain1,domain2",
+		"--output-file", outputFile,
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	var got failoverStartOutput
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, failoverStartOutput{
+		WorkflowID:    failovermanager.FailoverWorkflowID,
+		RunID:         "test-run-id",
+		TargetCluster: "cluster2",
+		SourceCluster: "cluster1",
+		Domains:       []string{"domain1", "domain2"},
+	}, got)
+}
+
+func TestAdminFailoverStart_DomainsFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	frontendCl := frontend.NewMockClient(ctrl)
+	frontendCl.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	frontendCl.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(&types.StartWorkflowExecutionResponse{RunID: "test-run-id"}, nil).Times(1)
+	expectReplicationClusters(frontendCl, "cluster1", "cluster2")
+
+	app := NewCliApp(&clientFactoryMock{
+		serverFrontendClient: frontendCl,
+	})
+
+	dir := t.TempDir()
+	domainsFile := filepath.Join(dir, "domains.txt")
+	require.NoError(t, os.WriteFile(domainsFile, []byte("# payments\ndomain2\n\n  domain1  \ndomain3\n"), 0600))
+	outputFile := filepath.Join(dir, "failover.json")
+	err := app.Run([]string{"", "admin", "cluster", "failover", "start",
+		"--sc", "cluster1",
+		"--tc", "cluster2",
+		"--domains", "domain1",
+		"--domains-file", domainsFile,
+		"--output-file", outputFile,
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	var got failoverStartOutput
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, []string{"domain1", "domain2", "domain3"}, got.Domains)
+
+	err = app.Run([]string{"", "admin", "cluster", "failover", "start",
+		"--sc", "cluster1",
+		"--tc", "cluster2",
+		"--domains-file", filepath.Join(dir, "missing.txt"),
+	})
+	assert.ErrorContains(t, err, "Failed to read domains file")
+}
+
+func TestAdminFailoverStart_AllDomains(t *testing.T) {
+	domain := func(name string, global bool, activeCluster string, managed bool) *types.DescribeDomainResponse {
+		return &types.DescribeDomainResponse{
+			DomainInfo: &types.DomainInfo{
+				Name: name,
+				Data: map[string]string{common.DomainDataKeyForManagedFailover: fmt.Sprint(managed)},
+			},
+			ReplicationConfiguration: &types.DomainReplicationConfiguration{ActiveClusterName: activeCluster},
+			IsGlobalDomain:           global,
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	frontendCl := frontend.NewMockClient(ctrl)
+	gomock.InOrder(
+		frontendCl.EXPECT().ListDomains(gomock.Any(), &types.ListDomainsRequest{PageSize: failoverDomainsPageSize}).
+			Return(&types.ListDomainsResponse{
+				Domains: []*types.DescribeDomainResponse{
+					domain("domain1", true, "cluster1", true),
+					domain("local", false, "cluster1", true),
+				},
+				NextPageToken: []byte("next"),
+			}, nil),
+		frontendCl.EXPECT().ListDomains(gomock.Any(), &types.ListDomainsRequest{PageSize: failoverDomainsPageSize, NextPageToken: []byte("next")}).
+			Return(&types.ListDomainsResponse{
+				Domains: []*types.DescribeDomainResponse{
+					domain("other-cluster", true, "cluster2", true),
+					domain("unmanaged", true, "cluster1", false),
+					domain("domain2", true, "cluster1", true),
+				},
+			}, nil),
+	)
+	frontendCl.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	frontendCl.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(&types.StartWorkflowExecutionResponse{RunID: "test-run-id"}, nil).Times(1)
+	expectReplicationClusters(frontendCl, "cluster1", "cluster2")
+
+	app := NewCliApp(&clientFactoryMock{
+		serverFrontendClient: frontendCl,
+	})
+
+	outputFile := filepath.Join(t.TempDir(), "failover.json")
+	err := app.Run([]string{"", "admin", "cluster", "failover", "start",
+		"--sc", "cluster1",
+		"--tc", "cluster2",
+		"--all-domains",
+		"--output-file", outputFile,
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	var got failoverStartOutput
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, []string{"domain1", "domain2"}, got.Domains)
+
+	err = app.Run([]string{"", "admin", "cluster", "failover", "start",
+		"--sc", "cluster1",
+		"--tc", "cluster2",
+		"--all-domains",
+		"--domains", "domain1",
+	})
+	assert.ErrorContains(t, err, "--all_domains cannot be used with --domains or --domains_file")
+}
+
+func TestAdminFailoverStart_RequestID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	frontendCl := frontend.NewMockClient(ctrl)
+	expectReplicationClusters(frontendCl, "cluster1", "cluster2")
+	frontendCl.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	frontendCl.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, gotReq *types.StartWorkflowExecutionRequest, opts ...yarpc.CallOption) (*types.StartWorkflowExecutionResponse, error) {
+			assert.Equal(t, "retry-safe-id", gotReq.RequestID)
+			return &types.StartWorkflowExecutionResponse{}, nil
+		}).Times(1)
+
+	app := NewCliApp(&clientFactoryMock{
+		serverFrontendClient: frontendCl,
+	})
+	err := app.Run([]string{"", "admin", "cluster", "failover", "start",
+		"--sc", "cluster1",
+		"--tc", "cluster2",
+		"--request-id", "retry-safe-id",
+	})
+	require.NoError(t, err)
+}
+
+func TestAdminFailoverStart_BatchWaitJitter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	frontendCl := frontend.NewMockClient(ctrl)
+	expectReplicationClusters(frontendCl, "cluster1", "cluster2")
+	frontendCl.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	frontendCl.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, gotReq *types.StartWorkflowExecutionRequest, opts ...yarpc.CallOption) (*types.StartWorkflowExecutionResponse, error) {
+			var params failovermanager.FailoverParams
+			require.NoError(t, json.Unmarshal(gotReq.Input, &params))
+			assert.Equal(t, 30, params.BatchFailoverWaitTimeInSeconds)
+			assert.Equal(t, 15, params.BatchFailoverWaitJitterInSeconds)
+			return &types.StartWorkflowExecutionResponse{}, nil
+		}).Times(1)
+
+	app := NewCliApp(&clientFactoryMock{
+		serverFrontendClient: frontendCl,
+	})
+	err := app.Run([]string{"", "admin", "cluster", "failover", "start",
+		"--sc", "cluster1",
+		"--tc", "cluster2",
+		"--batch-wait-jitter", "15",
+	})
+	require.NoError(t, err)
+
+	err = app.Run([]string{"", "admin", "cluster", "failover", "start",
+		"--sc", "cluster1",
+		"--tc", "cluster2",
+		"--batch-wait-jitter", "-1",
+	})
+	assert.ErrorContains(t, err, "batch wait jitter must not be negative")
+}
+
+func TestAdminFailoverStart_OperatorOverride(t *testing.T) {
+	oldGetOperatorFn := getOperatorFn
+	getOperatorFn = func() (string, error) { return "", fmt.Errorf("should not be called") }
+	defer func() {
+		getOperatorFn = oldGetOperatorFn
+	}()
+
+	ctrl := gomock.NewController(t)
+	frontendCl := frontend.NewMockClient(ctrl)
+	expectReplicationClusters(frontendCl, "cluster1", "cluster2")
+	frontendCl.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	frontendCl.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, gotReq *types.StartWorkflowExecutionRequest, opts ...yarpc.CallOption) (*types.StartWorkflowExecutionResponse, error) {
+			assert.Equal(t, mustGetWorkflowMemo(t, map[string]interface{}{
+				common.MemoKeyForOperator: "jane@ci-pipeline",
+				failoverMemoKeyForTag:     "INC-1234",
+			}), gotReq.Memo)
+			return &types.StartWorkflowExecutionResponse{}, nil
+		}).Times(1)
+
+	app := NewCliApp(&clientFactoryMock{
+		serverFrontendClient: frontendCl,
+	})
+	err := app.Run([]string{"", "admin", "cluster", "failover", "start",
+		"--sc", "cluster1",
+		"--tc", "cluster2",
+		"--operator", "jane@ci-pipeline",
+		"--tag", "INC-1234",
+	})
+	require.NoError(t, err)
+}
+
+func TestAdminFailoverStart_UnknownTargetCluster(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	frontendCl := frontend.NewMockClient(ctrl)
+	expectReplicationClusters(frontendCl, "cluster1", "cluster2")
+	app := NewCliApp(&clientFactoryMock{
+		serverFrontendClient: frontendCl,
+	})
+
+	err := app.Run([]string{"", "admin", "cluster", "failover", "start",
+		"--sc", "cluster1",
+		"--tc", "clsuter2",
+	})
+	assert.ErrorContains(t, err, `Target cluster "clsuter2" is not in the replication configuration of any global domain, known clusters are ["cluster1" "cluster2"]`)
+
+	frontendCl.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	frontendCl.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any()).Return(&types.StartWorkflowExecutionResponse{}, nil).Times(1)
+	err = app.Run([]string{"", "admin", "cluster", "failover", "start",
+		"--sc", "cluster1",
+		"--tc", "clsuter2",
+		"--skip-cluster-check",
+	})
+	assert.NoError(t, err)
+}
+
+// expectReplicationClusters returns a global domain replicated to the clusters when the domains are listed
+func expectReplicationClusters(m *frontend.MockClient, clusters ...string) {
+	replicationClusters := make([]*types.ClusterReplicationConfiguration, 0, len(clusters))
+	for _, cluster := range clusters {
+		replicationClusters = append(replicationClusters, &types.ClusterReplicationConfiguration{ClusterName: cluster})
+	}
+	m.EXPECT().ListDomains(gomock.Any(), gomock.Any()).Return(&types.ListDomainsResponse{
+		Domains: []*types.DescribeDomainResponse{{
+			DomainInfo:               &types.DomainInfo{Name: "global-domain"},
+			ReplicationConfiguration: &types.DomainReplicationConfiguration{Clusters: replicationClusters},
+			IsGlobalDomain:           true,
+		}},
+	}, nil).AnyTimes()
+}
+
+func TestAdminFailoverQuery_Tag(t *testing.T) {
+	td := newCLITestData(t)
+	td.mockFrontendClient.EXPECT().QueryWorkflow(gomock.Any(), gomock.Any()).
+		Return(&types.QueryWorkflowResponse{
+			QueryResult: mustMarshalQueryResult(t, failovermanager.QueryResult{State: failovermanager.WorkflowRunning}),
+		}, nil)
+	td.mockFrontendClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(&types.DescribeWorkflowExecutionResponse{
+			WorkflowExecutionInfo: &types.WorkflowExecutionInfo{
+				Memo: mustGetWorkflowMemo(t, map[string]interface{}{
+					common.MemoKeyForOperator: "jane",
+					failoverMemoKeyForTag:     "INC-1234",
+				}),
+			},
+		}, nil)
+
+	err := td.app.Run([]string{"", "admin", "cluster", "failover", "query"})
+	require.NoError(t, err)
+	assert.Contains(t, td.consoleOutput(), `"Tag": "INC-1234"`)
+	assert.Contains(t, td.consoleOutput(), `"State": "running"`)
+}
+
+func TestAdminFailoverQuery_Watch(t *testing.T) {
+	td := newCLITestData(t)
+	gomock.InOrder(
+		td.mockFrontendClient.EXPECT().QueryWorkflow(gomock.Any(), gomock.Any()).
+			Return(&types.QueryWorkflowResponse{QueryResult: mustMarshalQueryResult(t, failovermanager.QueryResult{
+				State:          failovermanager.WorkflowRunning,
+				TotalDomains:   4,
+				Success:        1,
+				SuccessDomains: []string{"d1"},
+			})}, nil),
+		td.mockFrontendClient.EXPECT().QueryWorkflow(gomock.Any(), gomock.Any()).
+			Return(&types.QueryWorkflowResponse{QueryResult: mustMarshalQueryResult(t, failovermanager.QueryResult{
+				State:          failovermanager.WorkflowCompleted,
+				TotalDomains:   4,
+				Success:        3,
+				Failed:         1,
+				SuccessDomains: []string{"d1", "d2", "d3"},
+				FailedDomains:  []string{"d4"},
+			})}, nil),
+	)
+	td.mockFrontendClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(&types.DescribeWorkflowExecutionResponse{}, nil).Times(2)
+
+	err := td.app.Run([]string{"", "admin", "cluster", "failover", "query", "--watch", "--interval", "1ms"})
+	require.NoError(t, err)
+	assert.Equal(t, `+ d1 succeeded
+[running] 1/4 domains processed (25.0%), 1 succeeded, 0 failed
++ d2 succeeded
++ d3 succeeded
+- d4 failed
+  1 domains unchanged
+[complete] 4/4 domains processed (100.0%), 3 succeeded, 1 failed
+`, td.consoleOutput())
+}
+
+func TestGetOperator(t *testing.T) {
+	op, err := getOperator()
+	require.NoError(t, err)
+	assert.Contains(t, op, "host: "+getHostName())
+}
+
 func TestAdminFailoverPauseResume(t *testing.T) {
 	tests := []struct {
 		desc          string
@@ -247,6 +589,24 @@ func TestAdminFailoverPauseResume(t *testing.T) {
 						}
 						return nil
 					}).Times(1)
+				expectFailoverStateQueries(m, failovermanager.WorkflowRunning, failovermanager.WorkflowPaused)
+			},
+		},
+		{
+			desc:          "pause not confirmed",
+			pauseOrResume: "pause",
+			mockFn: func(t *testing.T, m *frontend.MockClient) {
+				m.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				expectFailoverStateQueries(m, failovermanager.WorkflowRunning, failovermanager.WorkflowRunning)
+			},
+		},
+		{
+			desc:          "pause after the workflow completed",
+			pauseOrResume: "pause",
+			mockFn: func(t *testing.T, m *frontend.MockClient) {
+				m.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				// no more queries once the workflow reports a final state
+				expectFailoverStateQueries(m, failovermanager.WorkflowCompleted)
 			},
 		},
 		{
@@ -281,6 +641,7 @@ func TestAdminFailoverPauseResume(t *testing.T) {
 						}
 						return nil
 					}).Times(1)
+				expectFailoverStateQueries(m, failovermanager.WorkflowRunning)
 			},
 		},
 		{
@@ -296,6 +657,10 @@ func TestAdminFailoverPauseResume(t *testing.T) {
 		},
 	}
 
+	oldAttempts, oldInterval := failoverStateCheckAttempts, failoverStateCheckInterval
+	failoverStateCheckAttempts, failoverStateCheckInterval = 2, 0
+	defer func() { failoverStateCheckAttempts, failoverStateCheckInterval = oldAttempts, oldInterval }()
+
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
@@ -322,6 +687,58 @@ func TestAdminFailoverPauseResume(t *testing.T) {
 	}
 }
 
+// expectFailoverStateQueries makes the failover workflow report the given states to consecutive queries
+func expectFailoverStateQueries(m *frontend.MockClient, states ...string) {
+	for _, state := range states {
+		result, _ := json.Marshal(failovermanager.QueryResult{State: state})
+		m.EXPECT().QueryWorkflow(gomock.Any(), gomock.Any()).
+			Return(&types.QueryWorkflowResponse{QueryResult: result}, nil).Times(1)
+	}
+}
+
+func TestAdminFailoverStatus(t *testing.T) {
+	td := newCLITestData(t)
+	td.mockFrontendClient.EXPECT().QueryWorkflow(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, req *types.QueryWorkflowRequest, opts ...yarpc.CallOption) (*types.QueryWorkflowResponse, error) {
+			if req.Execution.WorkflowID == failovermanager.DrillWorkflowID {
+				return nil, &types.EntityNotExistsError{}
+			}
+			return &types.QueryWorkflowResponse{
+				QueryResult: mustMarshalQueryResult(t, failovermanager.QueryResult{
+					TotalDomains:  10,
+					Success:       2,
+					Failed:        1,
+					State:         failovermanager.WorkflowPaused,
+					SourceCluster: "cluster1",
+					TargetCluster: "cluster2",
+					Operator:      "test-user",
+				}),
+			}, nil
+		}).Times(2)
+	td.mockFrontendClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(&types.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: &types.WorkflowExecutionInfo{}}, nil).Times(1)
+
+	err := AdminFailoverStatus(clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagFormat, formatJSON)))
+	require.NoError(t, err)
+
+	var rows []FailoverStatusRow
+	require.NoError(t, json.Unmarshal([]byte(td.consoleOutput()), &rows))
+	assert.Equal(t, []FailoverStatusRow{
+		{
+			Workflow:      failovermanager.FailoverWorkflowID,
+			State:         failovermanager.WorkflowPaused,
+			SourceCluster: "cluster1",
+			TargetCluster: "cluster2",
+			Progress:      "2/1/10",
+			Operator:      "test-user",
+		},
+		{
+			Workflow: failovermanager.DrillWorkflowID,
+			State:    "not found",
+		},
+	}, rows)
+}
+
 func TestAdminFailoverQuery(t *testing.T) {
 	queryResult := failovermanager.QueryResult{
 		TotalDomains: 10,
@@ -329,9 +746,10 @@ func TestAdminFailoverQuery(t *testing.T) {
 		Failed:       3,
 	}
 	tests := []struct {
-		desc    string
-		mockFn  func(*testing.T, *frontend.MockClient)
-		wantErr bool
+		desc            string
+		mockFn          func(*testing.T, *frontend.MockClient)
+		wantErr         bool
+		wantErrContains string
 	}{
 		{
 			desc: "success",
@@ -386,6 +804,15 @@ func TestAdminFailoverQuery(t *testing.T) {
 					}).Times(1)
 			},
 		},
+		{
+			desc:            "failover never run",
+			wantErr:         true,
+			wantErrContains: "No failover workflow found",
+			mockFn: func(t *testing.T, m *frontend.MockClient) {
+				m.EXPECT().QueryWorkflow(gomock.Any(), gomock.Any()).
+					Return(nil, &types.EntityNotExistsError{Message: "workflow not found"}).Times(1)
+			},
+		},
 		{
 			desc:    "describe failed",
 			wantErr: true,
@@ -438,15 +865,20 @@ func TestAdminFailoverQuery(t *testing.T) {
 			if (err != nil) != tc.wantErr {
 				t.Errorf("Got error: %v, wantErr?: %v", err, tc.wantErr)
 			}
+			if tc.wantErrContains != "" {
+				assert.ErrorContains(t, err, tc.wantErrContains)
+			}
 		})
 	}
 }
 
 func TestAdminFailoverAbort(t *testing.T) {
 	tests := []struct {
-		desc    string
-		mockFn  func(*testing.T, *frontend.MockClient)
-		wantErr bool
+		desc            string
+		args            []string
+		mockFn          func(*testing.T, *frontend.MockClient)
+		wantErr         bool
+		wantErrContains string
 	}{
 		{
 			desc: "success",
@@ -468,8 +900,51 @@ func TestAdminFailoverAbort(t *testing.T) {
 					}).Times(1)
 			},
 		},
+		{
+			desc: "wait until terminated",
+			args: []string{"--wait"},
+			mockFn: func(t *testing.T, m *frontend.MockClient) {
+				m.EXPECT().TerminateWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil)
+				gomock.InOrder(
+					m.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).
+						Return(&types.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: &types.WorkflowExecutionInfo{}}, nil),
+					m.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).
+						Return(&types.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: &types.WorkflowExecutionInfo{
+							CloseStatus: types.WorkflowExecutionCloseStatusTerminated.Ptr(),
+						}}, nil),
+				)
+			},
+		},
+		{
+			desc: "wait timeout",
+			args: []string{"--wait", "--wait_timeout", "10ms"},
+			mockFn: func(t *testing.T, m *frontend.MockClient) {
+				m.EXPECT().TerminateWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).
+					Return(&types.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: &types.WorkflowExecutionInfo{}}, nil).MinTimes(1)
+			},
+			wantErr:         true,
+			wantErrContains: "Failover workflow not terminated after --wait_timeout 10ms",
+		},
+		{
+			desc: "closed before termination",
+			args: []string{"--wait"},
+			mockFn: func(t *testing.T, m *frontend.MockClient) {
+				m.EXPECT().TerminateWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).
+					Return(&types.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: &types.WorkflowExecutionInfo{
+						CloseStatus: types.WorkflowExecutionCloseStatusCompleted.Ptr(),
+					}}, nil)
+			},
+			wantErr:         true,
+			wantErrContains: "Failover workflow closed as COMPLETED instead of terminated",
+		},
 	}
 
+	oldInterval := failoverStateCheckInterval
+	failoverStateCheckInterval = time.Millisecond
+	defer func() { failoverStateCheckInterval = oldInterval }()
+
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
@@ -484,12 +959,15 @@ func TestAdminFailoverAbort(t *testing.T) {
 				serverFrontendClient: frontendCl,
 			})
 
-			args := []string{"", "admin", "cluster", "failover", "abort"}
+			args := append([]string{"", "admin", "cluster", "failover", "abort"}, tc.args...)
 			err := app.Run(args)
 
 			if (err != nil) != tc.wantErr {
 				t.Errorf("Got error: %v, wantErr?: %v", err, tc.wantErr)
 			}
+			if tc.wantErrContains != "" {
+				assert.ErrorContains(t, err, tc.wantErrContains)
+			}
 		})
 	}
 }
@@ -664,3 +1142,18 @@ func mustMarshalFailoverParams(t *testing.T, p failovermanager.FailoverParams) [
 	}
 	return res
 }
+
+func TestDedupeFailoverDomains(t *testing.T) {
+	domains, duplicates, err := dedupeFailoverDomains([]string{"domain1", "domain2", "domain1", " domain2 ", "domain3"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"domain1", "domain2", "domain3"}, domains)
+	assert.Equal(t, 2, duplicates)
+
+	domains, duplicates, err = dedupeFailoverDomains([]string{""})
+	require.NoError(t, err)
+	assert.Empty(t, domains)
+	assert.Zero(t, duplicates)
+
+	_, _, err = dedupeFailoverDomains([]string{"domain1", "", "domain2", "  "})
+	assert.EqualError(t, err, `empty domain names are not allowed, got #2 "", #4 "  "`)
+}
@@ -0,0 +1,142 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/tools/common/commoncli"
+)
+
+// HistoryDiffRow is a row of the events of two branches after they diverge
+type HistoryDiffRow struct {
+	EventID        int64  `header:"EventID"`
+	EventType      string `header:"Event Type"`
+	Version        string `header:"Version"`
+	OtherEventType string `header:"Other Event Type"`
+	OtherVersion   string `header:"Other Version"`
+}
+
+// AdminHistoryDiff compares two history branches of a workflow and prints where they diverge
+func AdminHistoryDiff(c *cli.Context) error {
+	shardID, err := getRequiredIntOption(c, FlagShardID)
+	if err != nil {
+		return commoncli.Problem("Required flag not found", err)
+	}
+	branchToken, err := encodeBranchToken(FlagBranchToken, c.String(FlagBranchToken), c.String(FlagTreeID), c.String(FlagBranchID))
+	if err != nil {
+		return err
+	}
+	// the branches of a workflow share its tree, so the other tree ID defaults to the first one
+	otherTreeID := c.String(FlagOtherTreeID)
+	if otherTreeID == "" && c.String(FlagOtherBranchID) != "" {
+		otherTreeID = c.String(FlagTreeID)
+	}
+	otherBranchToken, err := encodeBranchToken(FlagOtherBranchToken, c.String(FlagOtherBranchToken), otherTreeID, c.String(FlagOtherBranchID))
+	if err != nil {
+		return err
+	}
+	if branchToken == nil || otherBranchToken == nil {
+		return commoncli.Problem(fmt.Sprintf("need to specify both branches, with --%s or --%s/--%s, and --%s or --%s/--%s",
+			FlagBranchToken, FlagTreeID, FlagBranchID, FlagOtherBranchToken, FlagOtherTreeID, FlagOtherBranchID), nil)
+	}
+
+	histV2, err := getDeps(c).initializeHistoryManager(c)
+	if err != nil {
+		return commoncli.Problem("Error in initializing history manager", err)
+	}
+	defer histV2.Close()
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return commoncli.Problem("Error in creating context", err)
+	}
+	events, err := readHistoryBranch(ctx, histV2, branchToken, shardID, c.String(FlagDomain))
+	if err != nil {
+		return commoncli.Problem("Failed to read the history of the branch", err)
+	}
+	otherEvents, err := readHistoryBranch(ctx, histV2, otherBranchToken, shardID, c.String(FlagDomain))
+	if err != nil {
+		return commoncli.Problem("Failed to read the history of the other branch", err)
+	}
+
+	output := getDeps(c).Output()
+	diverged := firstDivergingEvent(events, otherEvents)
+	if diverged < 0 {
+		fmt.Fprintf(output, "Branches are identical, %d events\n", len(events))
+		return nil
+	}
+	var table []HistoryDiffRow
+	for i := diverged; i < len(events) || i < len(otherEvents); i++ {
+		var row HistoryDiffRow
+		if i < len(events) {
+			row.EventID = events[i].ID
+			row.EventType, row.Version = events[i].GetEventType().String(), strconv.FormatInt(events[i].Version, 10)
+		}
+		if i < len(otherEvents) {
+			row.EventID = otherEvents[i].ID
+			row.OtherEventType, row.OtherVersion = otherEvents[i].GetEventType().String(), strconv.FormatInt(otherEvents[i].Version, 10)
+		}
+		table = append(table, row)
+	}
+	fmt.Fprintf(output, "First diverging event ID: %d\n", table[0].EventID)
+	return Render(c, table, RenderOptions{DefaultTemplate: templateTable, Color: true})
+}
+
+// readHistoryBranch reads all the events of a branch
+func readHistoryBranch(
+	ctx context.Context,
+	histV2 persistence.HistoryManager,
+	branchToken []byte,
+	shardID int,
+	domainName string,
+) ([]*types.HistoryEvent, error) {
+	var events []*types.HistoryEvent
+	err := readHistoryBranchBatches(ctx, histV2, branchToken, shardID, domainName, common.FirstEventID, common.EndEventID, func(_ int, batch []*types.HistoryEvent) error {
+		events = append(events, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// firstDivergingEvent returns the index of the first event which differs between the two histories,
+// or -1 when they are identical. When one history is a prefix of the other, they diverge after it.
+func firstDivergingEvent(events, otherEvents []*types.HistoryEvent) int {
+	for i := 0; i < len(events) || i < len(otherEvents); i++ {
+		if i >= len(events) || i >= len(otherEvents) || !reflect.DeepEqual(events[i], otherEvents[i]) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,126 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/tools/cli/clitest"
+)
+
+func TestAdminHistoryDiff(t *testing.T) {
+	event := func(id int64, eventType types.EventType, version int64) *types.HistoryEvent {
+		return &types.HistoryEvent{ID: id, EventType: eventType.Ptr(), Version: version}
+	}
+	serialize := func(events []*types.HistoryEvent) *persistence.DataBlob {
+		blob, err := persistence.NewPayloadSerializer().SerializeBatchEvents(events, common.EncodingTypeThriftRW)
+		require.NoError(t, err)
+		return blob
+	}
+	branch := base64.StdEncoding.EncodeToString([]byte("branch"))
+	other := base64.StdEncoding.EncodeToString([]byte("other"))
+	histories := map[string][][]*types.HistoryEvent{
+		"branch": {
+			{event(1, types.EventTypeWorkflowExecutionStarted, 1), event(2, types.EventTypeDecisionTaskScheduled, 1)},
+			{event(3, types.EventTypeDecisionTaskStarted, 1)},
+		},
+		"other": {
+			{event(1, types.EventTypeWorkflowExecutionStarted, 1), event(2, types.EventTypeDecisionTaskScheduled, 1)},
+			{event(3, types.EventTypeDecisionTaskFailed, 2), event(4, types.EventTypeWorkflowExecutionSignaled, 2)},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		args           []clitest.CliArgument
+		errContains    string
+		outputContains []string
+	}{
+		{
+			name: "diverging branches",
+			args: []clitest.CliArgument{
+				clitest.StringArgument(FlagBranchToken, branch),
+				clitest.StringArgument(FlagOtherBranchToken, other),
+			},
+			outputContains: []string{
+				"First diverging event ID: 3",
+				"DecisionTaskStarted",
+				"DecisionTaskFailed",
+				"WorkflowExecutionSignaled",
+			},
+		},
+		{
+			name: "identical branches",
+			args: []clitest.CliArgument{
+				clitest.StringArgument(FlagBranchToken, branch),
+				clitest.StringArgument(FlagOtherBranchToken, branch),
+			},
+			outputContains: []string{"Branches are identical, 3 events"},
+		},
+		{
+			name: "other branch missing",
+			args: []clitest.CliArgument{
+				clitest.StringArgument(FlagBranchToken, branch),
+			},
+			errContains: "need to specify both branches",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newCLITestData(t)
+			if tt.errContains == "" {
+				historyManager := persistence.NewMockHistoryManager(td.ctrl)
+				historyManager.EXPECT().Close()
+				historyManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, req *persistence.ReadHistoryBranchRequest) (*persistence.ReadRawHistoryBranchResponse, error) {
+						pages := histories[string(req.BranchToken)]
+						if req.NextPageToken == nil {
+							return &persistence.ReadRawHistoryBranchResponse{HistoryEventBlobs: []*persistence.DataBlob{serialize(pages[0])}, NextPageToken: []byte("next")}, nil
+						}
+						return &persistence.ReadRawHistoryBranchResponse{HistoryEventBlobs: []*persistence.DataBlob{serialize(pages[1])}}, nil
+					}).
+					Times(4)
+				td.mockManagerFactory.EXPECT().initializeHistoryManager(gomock.Any()).Return(historyManager, nil)
+			}
+			args := append([]clitest.CliArgument{clitest.IntArgument(FlagShardID, 1)}, tt.args...)
+			err := AdminHistoryDiff(clitest.NewCLIContext(t, td.app, args...))
+			if tt.errContains != "" {
+				assert.ErrorContains(t, err, tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			for _, s := range tt.outputContains {
+				assert.Contains(t, td.consoleOutput(), s)
+			}
+		})
+	}
+}
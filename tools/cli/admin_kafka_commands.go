@@ -114,7 +114,7 @@ func AdminKafkaParse(c *cli.Context) error {
 	if err != nil {
 		return commoncli.Problem("Error in Admin kafka parse: ", err)
 	}
-	outputFile, err := getOutputFile(c.String(FlagOutputFilename))
+	outputFile, err := getOutputFile(c.String(FlagOutputFilename), c.Bool(FlagAppend))
 	defer outputFile.Close()
 	if err != nil {
 		return commoncli.Problem("Error in Admin kafka parse: ", err)
@@ -168,11 +168,17 @@ func buildFilterFnForVisibility(workflowID, runID string) filterFnForVisibility
 	}
 }
 
-func getOutputFile(outputFile string) (*os.File, error) {
+// getOutputFile opens outputFile for writing, truncating it unless appendMode is set.
+// It returns stdout if outputFile is empty.
+func getOutputFile(outputFile string, appendMode bool) (*os.File, error) {
 	if len(outputFile) == 0 {
 		return os.Stdout, nil
 	}
-	f, err := os.Create(outputFile)
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendMode {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(outputFile, flag, 0666)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create output file %w", err)
 	}
@@ -180,6 +186,19 @@ func getOutputFile(outputFile string) (*os.File, error) {
 	return f, nil
 }
 
+// writeOutputFile writes data to outputFile, appending to it if appendMode is set.
+func writeOutputFile(outputFile string, data []byte, appendMode bool) error {
+	f, err := getOutputFile(outputFile, appendMode)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 func startReader(file io.Reader, readerCh chan<- []byte) error {
 	defer close(readerCh)
 	reader := bufio.NewReader(file)
@@ -400,7 +419,7 @@ func deserializeMessages(messages [][]byte, skipErrors bool) ([]*types.Replicati
 		err := decode(m, &task)
 		if err != nil {
 			if !skipErrors {
-				return nil, 0, fmt.Errorf(malformedMessage+"Error: %v", err)
+				return nil, 0, fmt.Errorf(malformedMessage+"Error: %w", err)
 			}
 			skipped++
 			continue
@@ -427,7 +446,7 @@ func deserializeVisibilityMessages(messages [][]byte, skipErrors bool) ([]*index
 		err := decodeVisibility(m, &msg)
 		if err != nil {
 			if !skipErrors {
-				return nil, 0, fmt.Errorf(malformedMessage+"Error: %v", err)
+				return nil, 0, fmt.Errorf(malformedMessage+"Error: %w", err)
 			}
 			skipped++
 			continue
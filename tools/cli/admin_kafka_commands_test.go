@@ -30,6 +30,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/thriftrw/protocol/binary"
 	"go.uber.org/thriftrw/ptr"
@@ -211,13 +212,13 @@ func TestBuildFilterFnForVisibility(t *testing.T) {
 
 func TestGetOutputFile(t *testing.T) {
 	t.Run("returns stdout if filename is empty", func(t *testing.T) {
-		file, err := getOutputFile("")
+		file, err := getOutputFile("", false)
 		assert.NoError(t, err)
 		assert.Equal(t, os.Stdout, file)
 	})
 
 	t.Run("creates a file", func(t *testing.T) {
-		file, err := getOutputFile("test.txt")
+		file, err := getOutputFile("test.txt", false)
 		defer func() {
 			if file != nil {
 				_ = file.Close()
@@ -233,8 +234,22 @@ func TestGetOutputFile(t *testing.T) {
 		assert.Equal(t, "test.txt", info.Name())
 	})
 
+	t.Run("appends to a file", func(t *testing.T) {
+		name := createTempFileWithContent(t, "first\n")
+		require.NoError(t, writeOutputFile(name, []byte("second\n"), true))
+
+		data, err := os.ReadFile(name)
+		require.NoError(t, err)
+		assert.Equal(t, "first\nsecond\n", string(data))
+
+		require.NoError(t, writeOutputFile(name, []byte("third\n"), false))
+		data, err = os.ReadFile(name)
+		require.NoError(t, err)
+		assert.Equal(t, "third\n", string(data))
+	})
+
 	t.Run("fails to create a file", func(t *testing.T) {
-		file, err := getOutputFile("/non/existent/directory/test.txt")
+		file, err := getOutputFile("/non/existent/directory/test.txt", false)
 
 		assert.EqualError(t, err, "failed to create output file open /non/existent/directory/test.txt: no such file or directory")
 		assert.Nil(t, file)
@@ -523,7 +538,7 @@ func TestAdminRereplicate(t *testing.T) {
 				clitest.StringArgument(FlagRunID, "runID"),
 				clitest.StringArgument(FlagContextTimeout, "10s"),
 			},
-			err: commoncli.Problem("Required flag not found: ", errors.New("option source_cluster is required")),
+			err: commoncli.Problem("Required flag not found: ", requiredOptionError("source_cluster")),
 		},
 		{
 			name: "domain ID is missing",
@@ -535,7 +550,7 @@ func TestAdminRereplicate(t *testing.T) {
 				clitest.StringArgument(FlagRunID, "runID"),
 				clitest.StringArgument(FlagContextTimeout, "10s"),
 			},
-			err: commoncli.Problem("Required flag not found: ", errors.New("option domain_id is required")),
+			err: commoncli.Problem("Required flag not found: ", requiredOptionError("domain_id")),
 		},
 		{
 			name: "workflow ID is missing",
@@ -547,7 +562,7 @@ func TestAdminRereplicate(t *testing.T) {
 				clitest.StringArgument(FlagRunID, "runID"),
 				clitest.StringArgument(FlagContextTimeout, "10s"),
 			},
-			err: commoncli.Problem("Required flag not found: ", errors.New("option workflow_id is required")),
+			err: commoncli.Problem("Required flag not found: ", requiredOptionError("workflow_id")),
 		},
 		{
 			name: "run ID is missing",
@@ -559,7 +574,7 @@ func TestAdminRereplicate(t *testing.T) {
 				clitest.StringArgument(FlagWorkflowID, "workflowID"),
 				clitest.StringArgument(FlagContextTimeout, "10s"),
 			},
-			err: commoncli.Problem("Required flag not found: ", errors.New("option run_id is required")),
+			err: commoncli.Problem("Required flag not found: ", requiredOptionError("run_id")),
 		},
 	}
 
@@ -0,0 +1,149 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pborman/uuid"
+	"github.com/urfave/cli/v2"
+
+	"github.com/uber/cadence/client/frontend"
+	"github.com/uber/cadence/common/reconciliation/fetcher"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/tools/common/commoncli"
+)
+
+// AdminResetWorkflowsInBatch resets the executions written by `admin db unsupported-workflow --output_jsonl`,
+// or any other input of `admin db scan`, directly through the frontend instead of running generated commands.
+func AdminResetWorkflowsInBatch(c *cli.Context) error {
+	reason, err := getRequiredOption(c, FlagReason)
+	if err != nil {
+		return commoncli.Problem("Required flag not found", err)
+	}
+	resetType := c.String(FlagResetType)
+	extraFlag, ok := resetTypesMap[resetType]
+	if !ok {
+		return commoncli.Problem(fmt.Sprintf("unknown reset type %q, valid reset types are %q", resetType, mapKeysToArray(resetTypesMap)), nil)
+	}
+	if extraFlag != "" {
+		return commoncli.Problem(fmt.Sprintf("reset type %v requires --%v, use `workflow reset-batch` for it", resetType, extraFlag), nil)
+	}
+	runner, err := newBatchRunner(c, "Reset", "reset")
+	if err != nil {
+		return err
+	}
+	executions, err := readBatchInput[fetcher.ExecutionRequest](c, nil)
+	if err != nil {
+		return err
+	}
+	if len(executions) == 0 {
+		return commoncli.Problem("Input file contained no executions to reset", nil)
+	}
+
+	client, err := getCadenceClient(c)
+	if err != nil {
+		return err
+	}
+	domainNames, err := resolveDomainNames(c, client, executions)
+	if err != nil {
+		return err
+	}
+
+	params := batchResetParamsType{
+		reason:            reason,
+		dryRun:            c.Bool(FlagDryRun),
+		resetType:         resetType,
+		skipSignalReapply: c.Bool(FlagSkipSignalReapply),
+	}
+	return runner.run(c, len(executions), func(index int) (string, error) {
+		exec := executions[index]
+		newRunID, err := resetExecution(c, client, domainNames[exec.DomainID], exec, params)
+		switch {
+		case err != nil:
+			return fmt.Sprintf("Failed to reset %v %v: %v", exec.WorkflowID, exec.RunID, err), err
+		case params.dryRun:
+			return fmt.Sprintf("Would reset %v %v", exec.WorkflowID, exec.RunID), nil
+		default:
+			return fmt.Sprintf("Reset %v %v, new run %v", exec.WorkflowID, exec.RunID, newRunID), nil
+		}
+	})
+}
+
+// resolveDomainNames maps the domain IDs of executions to their names, using the names of the executions when they have one
+func resolveDomainNames(c *cli.Context, client frontend.Client, executions []fetcher.ExecutionRequest) (map[string]string, error) {
+	names := map[string]string{}
+	for _, exec := range executions {
+		if exec.DomainName != "" {
+			names[exec.DomainID] = exec.DomainName
+		}
+	}
+	for _, exec := range executions {
+		if _, ok := names[exec.DomainID]; ok {
+			continue
+		}
+		ctx, cancel, err := newContext(c)
+		if err != nil {
+			cancel()
+			return nil, commoncli.Problem("Error in creating context: ", err)
+		}
+		resp, err := client.DescribeDomain(ctx, &types.DescribeDomainRequest{UUID: &exec.DomainID})
+		cancel()
+		if err != nil {
+			return nil, commoncli.Problem(fmt.Sprintf("Failed to describe domain %v", exec.DomainID), err)
+		}
+		names[exec.DomainID] = resp.GetDomainInfo().GetName()
+	}
+	return names, nil
+}
+
+// resetExecution resets an execution to the point given by the reset type, and returns the run ID of the new run
+func resetExecution(c *cli.Context, client frontend.Client, domain string, exec fetcher.ExecutionRequest, params batchResetParamsType) (string, error) {
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return "", err
+	}
+	baseRunID, decisionFinishID, err := getResetEventIDByType(ctx, c, params.resetType, params.decisionOffset, domain, exec.WorkflowID, exec.RunID, client)
+	if err != nil {
+		return "", err
+	}
+	if params.dryRun {
+		return "", nil
+	}
+	resp, err := client.ResetWorkflowExecution(ctx, &types.ResetWorkflowExecutionRequest{
+		Domain: domain,
+		WorkflowExecution: &types.WorkflowExecution{
+			WorkflowID: exec.WorkflowID,
+			RunID:      baseRunID,
+		},
+		DecisionFinishEventID: decisionFinishID,
+		RequestID:             uuid.New(),
+		Reason:                fmt.Sprintf("%v:%v", getCurrentUserFromEnv(), params.reason),
+		SkipSignalReapply:     params.skipSignalReapply,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.GetRunID(), nil
+}
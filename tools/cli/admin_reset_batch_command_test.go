@@ -0,0 +1,139 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/yarpc"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/tools/cli/clitest"
+)
+
+const testResetBatchInput = `{"DomainID": "domain-id", "WorkflowID": "wf1", "RunID": "run1"}
+{"DomainID": "other-domain-id", "DomainName": "other-domain", "WorkflowID": "wf2", "RunID": "run2"}
+`
+
+func TestAdminResetWorkflowsInBatch(t *testing.T) {
+	history := &types.GetWorkflowExecutionHistoryResponse{History: &types.History{Events: []*types.HistoryEvent{
+		{ID: 3, EventType: types.EventTypeDecisionTaskCompleted.Ptr()},
+		{ID: 5, EventType: types.EventTypeActivityTaskScheduled.Ptr()},
+	}}}
+
+	tests := []struct {
+		name           string
+		mockSetup      func(td *cliTestData)
+		resetType      string
+		args           []clitest.CliArgument
+		expectedError  string
+		expectedOutput []string
+	}{
+		{
+			name: "resets every execution",
+			mockSetup: func(td *cliTestData) {
+				td.mockFrontendClient.EXPECT().DescribeDomain(gomock.Any(), &types.DescribeDomainRequest{UUID: common.StringPtr("domain-id")}).
+					Return(&types.DescribeDomainResponse{DomainInfo: &types.DomainInfo{Name: "domain"}}, nil)
+				td.mockFrontendClient.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any()).Return(history, nil).Times(2)
+				td.mockFrontendClient.EXPECT().ResetWorkflowExecution(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, req *types.ResetWorkflowExecutionRequest, _ ...yarpc.CallOption) (*types.ResetWorkflowExecutionResponse, error) {
+						assert.Equal(t, int64(3), req.DecisionFinishEventID)
+						if req.WorkflowExecution.WorkflowID == "wf1" {
+							assert.Equal(t, "domain", req.Domain)
+						} else {
+							assert.Equal(t, "other-domain", req.Domain)
+						}
+						return &types.ResetWorkflowExecutionResponse{RunID: "new-" + req.WorkflowExecution.RunID}, nil
+					}).Times(2)
+			},
+			expectedOutput: []string{"Reset wf1 run1, new run new-run1\n", "Reset wf2 run2, new run new-run2\n", "Reset 2 of 2 workflows, 0 failed.\n"},
+		},
+		{
+			name: "dry run",
+			mockSetup: func(td *cliTestData) {
+				td.mockFrontendClient.EXPECT().DescribeDomain(gomock.Any(), gomock.Any()).
+					Return(&types.DescribeDomainResponse{DomainInfo: &types.DomainInfo{Name: "domain"}}, nil)
+				td.mockFrontendClient.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any()).Return(history, nil).Times(2)
+			},
+			args:           []clitest.CliArgument{clitest.BoolArgument(FlagDryRun, true)},
+			expectedOutput: []string{"Would reset wf1 run1\n", "Would reset wf2 run2\n"},
+		},
+		{
+			name: "a reset fails",
+			mockSetup: func(td *cliTestData) {
+				td.mockFrontendClient.EXPECT().DescribeDomain(gomock.Any(), gomock.Any()).
+					Return(&types.DescribeDomainResponse{DomainInfo: &types.DomainInfo{Name: "domain"}}, nil)
+				td.mockFrontendClient.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any()).Return(history, nil).Times(2)
+				td.mockFrontendClient.EXPECT().ResetWorkflowExecution(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, req *types.ResetWorkflowExecutionRequest, _ ...yarpc.CallOption) (*types.ResetWorkflowExecutionResponse, error) {
+						if req.WorkflowExecution.WorkflowID == "wf1" {
+							return nil, &types.BadRequestError{Message: "cannot reset"}
+						}
+						return &types.ResetWorkflowExecutionResponse{RunID: "new-run2"}, nil
+					}).Times(2)
+			},
+			expectedError:  "1 of 2 workflows failed to reset",
+			expectedOutput: []string{"Failed to reset wf1 run1: cannot reset\n", "Reset 1 of 2 workflows, 1 failed.\n"},
+		},
+		{
+			name:          "reset type requiring another flag",
+			resetType:     resetTypeBadBinary,
+			expectedError: "use `workflow reset-batch` for it",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newCLITestData(t)
+			td.ioHandler.input = strings.NewReader(testResetBatchInput)
+			if tt.mockSetup != nil {
+				tt.mockSetup(td)
+			}
+			if tt.resetType == "" {
+				tt.resetType = resetTypeLastDecisionCompleted
+			}
+			args := append([]clitest.CliArgument{
+				clitest.StringArgument(FlagReason, "test"),
+				clitest.StringArgument(FlagResetType, tt.resetType),
+				clitest.IntArgument(FlagConcurrency, 1),
+				clitest.IntArgument(FlagRPS, 100),
+			}, tt.args...)
+
+			err := AdminResetWorkflowsInBatch(clitest.NewCLIContext(t, td.app, args...))
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedError)
+			}
+			for _, line := range tt.expectedOutput {
+				assert.Contains(t, td.consoleOutput(), line)
+			}
+		})
+	}
+}
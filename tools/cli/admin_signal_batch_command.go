@@ -0,0 +1,112 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pborman/uuid"
+	"github.com/urfave/cli/v2"
+
+	"github.com/uber/cadence/client/frontend"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/tools/common/commoncli"
+)
+
+// SignalBatchEntry is a workflow to signal in the input of `admin workflow signal-batch`
+type SignalBatchEntry struct {
+	Domain     string `json:"domain"`
+	WorkflowID string `json:"workflowID"`
+	RunID      string `json:"runID"`
+	SignalName string `json:"signalName"`
+}
+
+// execution is the workflow ID of the entry, followed by its run ID if set
+func (e SignalBatchEntry) execution() string {
+	if e.RunID == "" {
+		return e.WorkflowID
+	}
+	return e.WorkflowID + " " + e.RunID
+}
+
+// AdminSignalWorkflowsInBatch signals the workflows listed in a file, with the same --input for all of them
+func AdminSignalWorkflowsInBatch(c *cli.Context) error {
+	runner, err := newBatchRunner(c, "Signaled", "signal")
+	if err != nil {
+		return err
+	}
+	payload := c.String(FlagInput)
+	if payload != "" {
+		if err := validateJSONs(payload); err != nil {
+			return commoncli.Problem("Input is not valid JSON", err)
+		}
+	}
+
+	entries, err := readBatchInput(c, func(index int, entry *SignalBatchEntry) error {
+		if entry.SignalName == "" {
+			entry.SignalName = c.String(FlagName)
+		}
+		if entry.Domain == "" || entry.WorkflowID == "" || entry.SignalName == "" {
+			return commoncli.Problem(fmt.Sprintf("Entry %d of the input file needs a domain, workflowID and signalName", index+1), nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return commoncli.Problem("Input file contained no workflows to signal", nil)
+	}
+
+	client, err := getCadenceClient(c)
+	if err != nil {
+		return err
+	}
+
+	return runner.run(c, len(entries), func(index int) (string, error) {
+		entry := entries[index]
+		if err := signalExecution(c, client, entry, payload); err != nil {
+			return fmt.Sprintf("Failed to signal %v with %v: %v", entry.execution(), entry.SignalName, err), err
+		}
+		return fmt.Sprintf("Signaled %v with %v", entry.execution(), entry.SignalName), nil
+	})
+}
+
+func signalExecution(c *cli.Context, client frontend.Client, entry SignalBatchEntry, payload string) error {
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+	return client.SignalWorkflowExecution(ctx, &types.SignalWorkflowExecutionRequest{
+		Domain: entry.Domain,
+		WorkflowExecution: &types.WorkflowExecution{
+			WorkflowID: entry.WorkflowID,
+			RunID:      entry.RunID,
+		},
+		SignalName: entry.SignalName,
+		Input:      []byte(payload),
+		Identity:   getCliIdentity(),
+		RequestID:  uuid.New(),
+	})
+}
@@ -0,0 +1,116 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/yarpc"
+
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/tools/cli/clitest"
+)
+
+const testSignalBatchInput = `{"domain": "domain", "workflowID": "wf1", "runID": "run1", "signalName": "retry"}
+{"domain": "other-domain", "workflowID": "wf2"}
+`
+
+func TestAdminSignalWorkflowsInBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		mockSetup      func(td *cliTestData)
+		expectedError  string
+		expectedOutput []string
+	}{
+		{
+			name:  "signals every workflow",
+			input: testSignalBatchInput,
+			mockSetup: func(td *cliTestData) {
+				td.mockFrontendClient.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, req *types.SignalWorkflowExecutionRequest, _ ...yarpc.CallOption) error {
+						assert.Equal(t, `{"force":true}`, string(req.Input))
+						if req.WorkflowExecution.WorkflowID == "wf1" {
+							assert.Equal(t, "domain", req.Domain)
+							assert.Equal(t, "retry", req.SignalName)
+						} else {
+							assert.Equal(t, "other-domain", req.Domain)
+							assert.Equal(t, "default-signal", req.SignalName)
+						}
+						return nil
+					}).Times(2)
+			},
+			expectedOutput: []string{"Signaled wf1 run1 with retry\n", "Signaled wf2 with default-signal\n", "Signaled 2 of 2 workflows, 0 failed.\n"},
+		},
+		{
+			name:  "a signal fails",
+			input: testSignalBatchInput,
+			mockSetup: func(td *cliTestData) {
+				td.mockFrontendClient.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, req *types.SignalWorkflowExecutionRequest, _ ...yarpc.CallOption) error {
+						if req.WorkflowExecution.WorkflowID == "wf1" {
+							return &types.EntityNotExistsError{Message: "workflow not found"}
+						}
+						return nil
+					}).Times(2)
+			},
+			expectedError:  "1 of 2 workflows failed to signal",
+			expectedOutput: []string{"Failed to signal wf1 run1 with retry: workflow not found\n", "Signaled 1 of 2 workflows, 1 failed.\n"},
+		},
+		{
+			name:          "entry without a workflow ID",
+			input:         `{"domain": "domain", "signalName": "retry"}`,
+			expectedError: "Entry 1 of the input file needs a domain, workflowID and signalName",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newCLITestData(t)
+			td.ioHandler.input = strings.NewReader(tt.input)
+			if tt.mockSetup != nil {
+				tt.mockSetup(td)
+			}
+
+			err := AdminSignalWorkflowsInBatch(clitest.NewCLIContext(t, td.app,
+				clitest.StringArgument(FlagName, "default-signal"),
+				clitest.StringArgument(FlagInput, `{"force":true}`),
+				clitest.IntArgument(FlagConcurrency, 1),
+				clitest.IntArgument(FlagRPS, 100),
+			))
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedError)
+			}
+			for _, line := range tt.expectedOutput {
+				assert.Contains(t, td.consoleOutput(), line)
+			}
+		})
+	}
+}
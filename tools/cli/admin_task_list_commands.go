@@ -21,13 +21,20 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/uber/cadence/client/frontend"
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/tools/common/commoncli"
 )
@@ -37,6 +44,7 @@ type (
 		Name        string `header:"Task List Name"`
 		Type        string `header:"Type"`
 		PollerCount int    `header:"Poller Count"`
+		Backlog     int64  `header:"Backlog"`
 	}
 	TaskListStatusRow struct {
 		ReadLevel int64   `header:"Read Level"`
@@ -46,6 +54,13 @@ type (
 		StartID   int64   `header:"Lease Start TaskID"`
 		EndID     int64   `header:"Lease End TaskID"`
 	}
+	TaskListPartitionStatusRow struct {
+		Partition string  `header:"Partition"`
+		ReadLevel int64   `header:"Read Level"`
+		AckLevel  int64   `header:"Ack Level"`
+		Backlog   int64   `header:"Backlog"`
+		RPS       float64 `header:"RPS"`
+	}
 	TaskListPartitionConfigRow struct {
 		Version         int64                            `header:"Version"`
 		ReadPartitions  map[int]*types.TaskListPartition `header:"Read Partitions"`
@@ -67,47 +82,203 @@ func AdminDescribeTaskList(c *cli.Context) error {
 	if err != nil {
 		return commoncli.Problem("Required flag not found: ", err)
 	}
-	taskListType := types.TaskListTypeDecision
-	if strings.ToLower(c.String(FlagTaskListType)) == "activity" {
-		taskListType = types.TaskListTypeActivity
-	}
+	taskListTypes := getTaskListTypes(c)
 
 	ctx, cancel, err := newContext(c)
 	defer cancel()
 	if err != nil {
 		return commoncli.Problem("Error in creating context:", err)
 	}
-	request := &types.DescribeTaskListRequest{
-		Domain:                domain,
-		TaskList:              &types.TaskList{Name: taskList},
-		TaskListType:          &taskListType,
-		IncludeTaskListStatus: true,
+	requests := make([]*types.DescribeTaskListRequest, len(taskListTypes))
+	responses := make([]*types.DescribeTaskListResponse, len(taskListTypes))
+	for i := range taskListTypes {
+		requests[i] = &types.DescribeTaskListRequest{
+			Domain:                domain,
+			TaskList:              &types.TaskList{Name: taskList},
+			TaskListType:          &taskListTypes[i],
+			IncludeTaskListStatus: true,
+		}
+		responses[i], err = frontendClient.DescribeTaskList(ctx, requests[i])
+		if err != nil {
+			return commoncli.Problem("Operation DescribeTaskList failed.", err)
+		}
 	}
 
-	response, err := frontendClient.DescribeTaskList(ctx, request)
-	if err != nil {
-		return commoncli.Problem("Operation DescribeTaskList failed.", err)
+	if promFile := c.String(FlagPrometheusFile); promFile != "" {
+		if err := writeTaskListPrometheusMetrics(promFile, domain, taskList, taskListTypes, responses); err != nil {
+			return commoncli.Problem("Failed to write prometheus metrics file", err)
+		}
 	}
 
-	taskListStatus := response.GetTaskListStatus()
-	if taskListStatus == nil {
-		return commoncli.Problem(colorMagenta("No tasklist status information."), nil)
+	output := getDeps(c).Output()
+	if len(taskListTypes) == 1 {
+		if responses[0].GetTaskListStatus() == nil {
+			return reportEmptyTaskList(c, output, "Task list exists but has no status information: "+taskList)
+		}
+		if err := printTaskListDescription(c, ctx, frontendClient, requests[0], responses[0]); err != nil {
+			return err
+		}
+		if len(responses[0].Pollers) == 0 {
+			warnBacklogWithoutPollers(output, responses[0], taskListTypes[0])
+			return reportEmptyTaskList(c, output, "Task list exists but has no pollers: "+taskList)
+		}
+		return printTaskListPollers(output, responses[0].Pollers, taskListTypes[0])
 	}
-	if err := printTaskListStatus(getDeps(c).Output(), taskListStatus); err != nil {
+
+	// when describing all types a missing status or poller for one of them is expected,
+	// so the task list is only reported as empty when none of the types has pollers
+	hasPollers := false
+	for i, taskListType := range taskListTypes {
+		fmt.Fprintf(output, "%s\n\n", colorGreen(fmt.Sprintf("%v task list:", taskListType)))
+		if responses[i].GetTaskListStatus() == nil {
+			fmt.Fprintf(output, "%s\n\n", colorMagenta("Task list exists but has no status information: "+taskList))
+			continue
+		}
+		if err := printTaskListDescription(c, ctx, frontendClient, requests[i], responses[i]); err != nil {
+			return err
+		}
+		if len(responses[i].Pollers) == 0 {
+			warnBacklogWithoutPollers(output, responses[i], taskListType)
+			fmt.Fprintf(output, "%s\n\n", colorMagenta("Task list exists but has no pollers: "+taskList))
+			continue
+		}
+		hasPollers = true
+		if err := printTaskListPollers(output, responses[i].Pollers, taskListType); err != nil {
+			return err
+		}
+		output.Write([]byte("\n"))
+	}
+	if !hasPollers && c.Bool(FlagFailOnEmpty) {
+		return commoncli.ProblemWithExitCode(exitCodeEmpty, "Task list has no pollers: "+taskList, nil)
+	}
+	return nil
+}
+
+// getTaskListTypes returns the task list types selected by --task_list_type, decision unless activity or all is given
+func getTaskListTypes(c *cli.Context) []types.TaskListType {
+	switch strings.ToLower(c.String(FlagTaskListType)) {
+	case "activity":
+		return []types.TaskListType{types.TaskListTypeActivity}
+	case "all":
+		return []types.TaskListType{types.TaskListTypeDecision, types.TaskListTypeActivity}
+	}
+	return []types.TaskListType{types.TaskListTypeDecision}
+}
+
+// warnBacklogWithoutPollers diagnoses the most common task list outage: tasks piling up while no worker polls
+func warnBacklogWithoutPollers(output io.Writer, response *types.DescribeTaskListResponse, taskListType types.TaskListType) {
+	if backlog := response.GetTaskListStatus().GetBacklogCountHint(); backlog > 0 {
+		fmt.Fprintf(output, "%s backlog of %d %v tasks present but no active pollers, check that the workers are running\n\n",
+			colorRed("Warning:"), backlog, strings.ToLower(taskListType.String()))
+	}
+}
+
+// reportEmptyTaskList prints that the task list has nothing to show, which is not an error unless --fail_on_empty is set
+func reportEmptyTaskList(c *cli.Context, output io.Writer, msg string) error {
+	if c.Bool(FlagFailOnEmpty) {
+		return commoncli.ProblemWithExitCode(exitCodeEmpty, colorMagenta(msg), nil)
+	}
+	fmt.Fprintln(output, colorMagenta(msg))
+	return nil
+}
+
+// printTaskListDescription prints the status and partition information of a single task list type.
+func printTaskListDescription(
+	c *cli.Context,
+	ctx context.Context,
+	frontendClient frontend.Client,
+	request *types.DescribeTaskListRequest,
+	response *types.DescribeTaskListResponse,
+) error {
+	output := getDeps(c).Output()
+	if err := printTaskListStatus(output, response.GetTaskListStatus()); err != nil {
 		return fmt.Errorf("failed to print task list status: %w", err)
 	}
-	getDeps(c).Output().Write([]byte("\n"))
+	output.Write([]byte("\n"))
 	if response.PartitionConfig != nil {
-		if err := printTaskListPartitionConfig(getDeps(c).Output(), response.PartitionConfig); err != nil {
+		if err := printTaskListPartitionConfig(output, response.PartitionConfig); err != nil {
 			return fmt.Errorf("failed to print task list partition config: %w", err)
 		}
-		getDeps(c).Output().Write([]byte("\n"))
+		output.Write([]byte("\n"))
+	}
+	if c.Bool(FlagAllPartitions) {
+		rows, err := describeTaskListPartitions(ctx, frontendClient, request, response)
+		if err != nil {
+			return commoncli.Problem("Failed to describe task list partitions", err)
+		}
+		if err := printTaskListPartitionStatus(output, rows); err != nil {
+			return fmt.Errorf("failed to print task list partition status: %w", err)
+		}
+		output.Write([]byte("\n"))
+	}
+	return nil
+}
+
+// describeTaskListPartitions returns the status of every partition of the task list.
+// Partitions are taken from the partition config of the root partition, non-root partitions
+// are addressed by their internal name (see common.ReservedTaskListPrefix).
+func describeTaskListPartitions(
+	ctx context.Context,
+	frontendClient frontend.Client,
+	request *types.DescribeTaskListRequest,
+	rootResponse *types.DescribeTaskListResponse,
+) ([]TaskListPartitionStatusRow, error) {
+	partitionIDs := map[int]struct{}{0: {}}
+	if config := rootResponse.PartitionConfig; config != nil {
+		for id := range config.ReadPartitions {
+			partitionIDs[id] = struct{}{}
+		}
+		for id := range config.WritePartitions {
+			partitionIDs[id] = struct{}{}
+		}
 	}
-	pollers := response.Pollers
-	if len(pollers) == 0 {
-		return commoncli.Problem(colorMagenta("No poller for tasklist: "+taskList), nil)
+	ids := make([]int, 0, len(partitionIDs))
+	for id := range partitionIDs {
+		ids = append(ids, id)
 	}
-	return printTaskListPollers(getDeps(c).Output(), pollers, taskListType)
+	sort.Ints(ids)
+
+	rootName := request.TaskList.GetName()
+	rows := make([]TaskListPartitionStatusRow, 0, len(ids))
+	for _, id := range ids {
+		name := rootName
+		status := rootResponse.GetTaskListStatus()
+		if id != 0 {
+			name = fmt.Sprintf("%v%v/%v", common.ReservedTaskListPrefix, rootName, id)
+			response, err := frontendClient.DescribeTaskList(ctx, &types.DescribeTaskListRequest{
+				Domain:                request.Domain,
+				TaskList:              &types.TaskList{Name: name, Kind: types.TaskListKindNormal.Ptr()},
+				TaskListType:          request.TaskListType,
+				IncludeTaskListStatus: true,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("describing partition %v: %w", name, err)
+			}
+			status = response.GetTaskListStatus()
+		}
+		rows = append(rows, TaskListPartitionStatusRow{
+			Partition: name,
+			ReadLevel: status.GetReadLevel(),
+			AckLevel:  status.GetAckLevel(),
+			Backlog:   status.GetBacklogCountHint(),
+			RPS:       status.GetRatePerSecond(),
+		})
+	}
+	return rows, nil
+}
+
+// printTaskListPartitionStatus prints the aggregated status of all partitions followed by the per-partition breakdown.
+func printTaskListPartitionStatus(w io.Writer, rows []TaskListPartitionStatusRow) error {
+	total := TaskListPartitionStatusRow{Partition: fmt.Sprintf("Total (%d partitions)", len(rows))}
+	for _, row := range rows {
+		total.Backlog += row.Backlog
+		total.RPS += row.RPS
+	}
+	if err := RenderTable(w, []TaskListPartitionStatusRow{total}, RenderOptions{Color: true}); err != nil {
+		return err
+	}
+	w.Write([]byte("\n"))
+	return RenderTable(w, rows, RenderOptions{Color: true, Border: true})
 }
 
 // AdminListTaskList displays all task lists under a domain.
@@ -120,6 +291,17 @@ func AdminListTaskList(c *cli.Context) error {
 	if err != nil {
 		return commoncli.Problem("Required flag not found: ", err)
 	}
+	withStatus := c.Bool(FlagWithStatus)
+	sortBy := strings.ToLower(c.String(FlagSortBy))
+	switch sortBy {
+	case "", "pollers":
+	case "backlog":
+		if !withStatus {
+			return commoncli.Problem("Sorting by backlog requires --"+FlagWithStatus, nil)
+		}
+	default:
+		return commoncli.Problem("Invalid sort order: valid values are [backlog, pollers]", nil)
+	}
 	ctx, cancel, err := newContext(c)
 	defer cancel()
 	if err != nil {
@@ -137,12 +319,189 @@ func AdminListTaskList(c *cli.Context) error {
 	fmt.Println("Task Lists for domain " + domain + ":")
 	table := []TaskListRow{}
 	for name, taskList := range response.GetDecisionTaskListMap() {
-		table = append(table, TaskListRow{name, "Decision", len(taskList.GetPollers())})
+		table = append(table, TaskListRow{Name: name, Type: "Decision", PollerCount: len(taskList.GetPollers())})
 	}
 	for name, taskList := range response.GetActivityTaskListMap() {
-		table = append(table, TaskListRow{name, "Activity", len(taskList.GetPollers())})
+		table = append(table, TaskListRow{Name: name, Type: "Activity", PollerCount: len(taskList.GetPollers())})
+	}
+	if withStatus {
+		if err := fillTaskListBacklog(c, frontendClient, domain, table, c.Int(FlagConcurrency)); err != nil {
+			return commoncli.Problem("Failed to describe task lists", err)
+		}
+	}
+	switch sortBy {
+	case "backlog":
+		sort.SliceStable(table, func(i, j int) bool { return table[i].Backlog > table[j].Backlog })
+	case "pollers":
+		sort.SliceStable(table, func(i, j int) bool { return table[i].PollerCount > table[j].PollerCount })
+	}
+	opts := RenderOptions{Color: true, Border: true, OptionalColumns: map[string]bool{"Backlog": withStatus}}
+	return RenderTable(os.Stdout, table, opts)
+}
+
+// fillTaskListBacklog describes every task list in rows, with at most concurrency calls in flight, and sets its backlog.
+// Every call gets a context of its own, so that a domain with many task lists is not bounded by a single --context_timeout.
+func fillTaskListBacklog(c *cli.Context, frontendClient frontend.Client, domain string, rows []TaskListRow, concurrency int) error {
+	if concurrency <= 0 {
+		concurrency = 1
 	}
-	return RenderTable(os.Stdout, table, RenderOptions{Color: true, Border: true})
+	sem := make(chan struct{}, concurrency)
+	errCh := make(chan error, len(rows))
+	wg := &sync.WaitGroup{}
+	for i := range rows {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(row *TaskListRow) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			taskListType := types.TaskListTypeDecision
+			if row.Type == "Activity" {
+				taskListType = types.TaskListTypeActivity
+			}
+			ctx, cancel, err := newContext(c)
+			defer cancel()
+			if err != nil {
+				errCh <- fmt.Errorf("creating context: %w", err)
+				return
+			}
+			response, err := frontendClient.DescribeTaskList(ctx, &types.DescribeTaskListRequest{
+				Domain:                domain,
+				TaskList:              &types.TaskList{Name: row.Name},
+				TaskListType:          &taskListType,
+				IncludeTaskListStatus: true,
+			})
+			if err != nil {
+				errCh <- fmt.Errorf("describing task list %v: %w", row.Name, err)
+				return
+			}
+			row.Backlog = response.GetTaskListStatus().GetBacklogCountHint()
+		}(&rows[i])
+	}
+	wg.Wait()
+	close(errCh)
+	return <-errCh
+}
+
+// AdminDrainTaskList waits for the backlog of a task list to drain, reporting the progress every --interval.
+// The server has no API to stop a task list from accepting tasks, so the workflows starting new tasks
+// on it must be stopped or moved to another task list before draining.
+func AdminDrainTaskList(c *cli.Context) error {
+	frontendClient, err := getDeps(c).ServerFrontendClient(c)
+	if err != nil {
+		return err
+	}
+	domain, err := getRequiredOption(c, FlagDomain)
+	if err != nil {
+		return commoncli.Problem("Required flag not found: ", err)
+	}
+	taskList, err := getRequiredOption(c, FlagTaskList)
+	if err != nil {
+		return commoncli.Problem("Required flag not found: ", err)
+	}
+	interval := c.Duration(FlagInterval)
+	if interval <= 0 {
+		return commoncli.Problem(fmt.Sprintf("--%s must be positive", FlagInterval), nil)
+	}
+	var deadline <-chan time.Time
+	if timeout := c.Duration(FlagWaitTimeout); timeout > 0 {
+		deadline = time.After(timeout)
+	}
+	taskListTypes := getTaskListTypes(c)
+	stop := cancelOnInterrupt(c)
+	defer stop()
+
+	output := getDeps(c).Output()
+	start := time.Now()
+	var initialBacklog int64
+	for sample := 0; ; sample++ {
+		backlog, responses, err := getTaskListBacklog(c, frontendClient, domain, taskList, taskListTypes)
+		if err != nil {
+			return err
+		}
+		if sample == 0 {
+			initialBacklog = backlog
+			fmt.Fprintf(output, "Backlog: %d\n", backlog)
+			for i, response := range responses {
+				if len(response.Pollers) == 0 {
+					warnBacklogWithoutPollers(output, response, taskListTypes[i])
+				}
+			}
+		} else {
+			elapsed := time.Since(start)
+			rate := float64(initialBacklog-backlog) / elapsed.Seconds()
+			line := fmt.Sprintf("Backlog: %d (%d drained in %v, %.2f tasks/s", backlog, initialBacklog-backlog, elapsed.Round(time.Second), rate)
+			if rate > 0 && backlog > 0 {
+				line += fmt.Sprintf(", ETA %v", time.Duration(float64(backlog)/rate*float64(time.Second)).Round(time.Second))
+			}
+			fmt.Fprintln(output, line+")")
+		}
+		if backlog == 0 {
+			fmt.Fprintln(output, colorGreen("Task list drained: "+taskList))
+			return nil
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-deadline:
+			return commoncli.Problem(fmt.Sprintf("Task list %v not drained within --%s, %d tasks left", taskList, FlagWaitTimeout, backlog), nil)
+		case <-c.Context.Done():
+			return commoncli.Problem(fmt.Sprintf("Interrupted with %d tasks left", backlog), c.Context.Err())
+		}
+	}
+}
+
+// getTaskListBacklog returns the total backlog of the given types of the task list, and the description of each type
+func getTaskListBacklog(
+	c *cli.Context,
+	frontendClient frontend.Client,
+	domain string,
+	taskList string,
+	taskListTypes []types.TaskListType,
+) (int64, []*types.DescribeTaskListResponse, error) {
+	ctx, cancel, err := newContext(c)
+	defer cancel()
+	if err != nil {
+		return 0, nil, commoncli.Problem("Error in creating context:", err)
+	}
+	var backlog int64
+	responses := make([]*types.DescribeTaskListResponse, len(taskListTypes))
+	for i := range taskListTypes {
+		responses[i], err = frontendClient.DescribeTaskList(ctx, &types.DescribeTaskListRequest{
+			Domain:                domain,
+			TaskList:              &types.TaskList{Name: taskList},
+			TaskListType:          &taskListTypes[i],
+			IncludeTaskListStatus: true,
+		})
+		if err != nil {
+			return 0, nil, commoncli.Problem("Operation DescribeTaskList failed.", err)
+		}
+		backlog += responses[i].GetTaskListStatus().GetBacklogCountHint()
+	}
+	return backlog, responses, nil
+}
+
+// AdminDescribeTaskListPartitions displays the matching host owning each partition of a task list.
+func AdminDescribeTaskListPartitions(c *cli.Context) error {
+	response, err := listTaskListPartitions(c)
+	if err != nil {
+		return err
+	}
+	if len(response.DecisionTaskListPartitions) == 0 && len(response.ActivityTaskListPartitions) == 0 {
+		return commoncli.Problem(colorMagenta("No partitions found for tasklist: "+c.String(FlagTaskList)), nil)
+	}
+
+	output := getDeps(c).Output()
+	if len(response.DecisionTaskListPartitions) > 0 {
+		if err := printTaskListPartitions(output, "Decision", response.DecisionTaskListPartitions); err != nil {
+			return err
+		}
+	}
+	if len(response.ActivityTaskListPartitions) > 0 {
+		return printTaskListPartitions(output, "Activity", response.ActivityTaskListPartitions)
+	}
+	return nil
 }
 
 func printTaskListStatus(w io.Writer, taskListStatus *types.TaskListStatus) error {
@@ -157,6 +516,57 @@ func printTaskListStatus(w io.Writer, taskListStatus *types.TaskListStatus) erro
 	return RenderTable(w, table, RenderOptions{Color: true})
 }
 
+// writeTaskListPrometheusMetrics writes task list health gauges in the Prometheus text exposition format,
+// one sample per task list type.
+// The file is written to a temporary location and renamed so that collectors never observe a partial file.
+func writeTaskListPrometheusMetrics(path, domain, taskList string, taskListTypes []types.TaskListType, responses []*types.DescribeTaskListResponse) error {
+	metrics := []struct {
+		name  string
+		help  string
+		value func(*types.DescribeTaskListResponse) string
+	}{
+		{"cadence_tasklist_backlog", "Approximate number of tasks in the task list backlog.", func(r *types.DescribeTaskListResponse) string {
+			return strconv.FormatInt(r.GetTaskListStatus().GetBacklogCountHint(), 10)
+		}},
+		{"cadence_tasklist_rps", "Task dispatch rate per second of the task list.", func(r *types.DescribeTaskListResponse) string {
+			return strconv.FormatFloat(r.GetTaskListStatus().GetRatePerSecond(), 'g', -1, 64)
+		}},
+		{"cadence_tasklist_ack_level", "Ack level of the task list.", func(r *types.DescribeTaskListResponse) string {
+			return strconv.FormatInt(r.GetTaskListStatus().GetAckLevel(), 10)
+		}},
+		{"cadence_tasklist_poller_count", "Number of pollers recently seen on the task list.", func(r *types.DescribeTaskListResponse) string {
+			return strconv.Itoa(len(r.GetPollers()))
+		}},
+	}
+
+	sb := &strings.Builder{}
+	for _, m := range metrics {
+		fmt.Fprintf(sb, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(sb, "# TYPE %s gauge\n", m.name)
+		for i, taskListType := range taskListTypes {
+			labels := fmt.Sprintf(`domain="%s",tasklist="%s",tasklist_type="%s"`,
+				escapePrometheusLabel(domain),
+				escapePrometheusLabel(taskList),
+				strings.ToLower(taskListType.String()),
+			)
+			fmt.Fprintf(sb, "%s{%s} %s\n", m.name, labels, m.value(responses[i]))
+		}
+	}
+
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("writing %v: %w", tmpFile, err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		return fmt.Errorf("renaming %v to %v: %w", tmpFile, path, err)
+	}
+	return nil
+}
+
+func escapePrometheusLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
 func printTaskListPartitionConfig(w io.Writer, config *types.TaskListPartitionConfig) error {
 	table := TaskListPartitionConfigRow{
 		Version:         config.Version,
@@ -23,15 +23,23 @@
 package cli
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/mock/gomock"
+	"go.uber.org/yarpc"
 
 	"github.com/uber/cadence/client/admin"
 	"github.com/uber/cadence/client/frontend"
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/tools/cli/clitest"
 )
@@ -56,6 +64,122 @@ func TestAdminDescribeTaskList(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestAdminDescribeTaskList_PrometheusFile(t *testing.T) {
+	td := newCLITestData(t)
+
+	expectedResponse := &types.DescribeTaskListResponse{
+		Pollers: []*types.PollerInfo{
+			{Identity: "test-poller-1"},
+			{Identity: "test-poller-2"},
+		},
+		TaskListStatus: &types.TaskListStatus{
+			BacklogCountHint: 10,
+			AckLevel:         42,
+			RatePerSecond:    1.5,
+		},
+	}
+	td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(expectedResponse, nil).Times(1)
+
+	promFile := filepath.Join(t.TempDir(), "tasklist.prom")
+	cliCtx := clitest.NewCLIContext(
+		t,
+		td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.StringArgument(FlagTaskList, testTaskList),
+		clitest.StringArgument(FlagTaskListType, testTaskListType),
+		clitest.StringArgument(FlagPrometheusFile, promFile),
+	)
+	err := AdminDescribeTaskList(cliCtx)
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(promFile)
+	assert.NoError(t, err)
+	labels := `{domain="test-domain",tasklist="test-tasklist",tasklist_type="decision"}`
+	assert.Contains(t, string(content), "# TYPE cadence_tasklist_backlog gauge\n")
+	assert.Contains(t, string(content), "cadence_tasklist_backlog"+labels+" 10\n")
+	assert.Contains(t, string(content), "cadence_tasklist_rps"+labels+" 1.5\n")
+	assert.Contains(t, string(content), "cadence_tasklist_ack_level"+labels+" 42\n")
+	assert.Contains(t, string(content), "cadence_tasklist_poller_count"+labels+" 2\n")
+}
+
+func TestAdminDescribeTaskList_AllPartitions(t *testing.T) {
+	td := newCLITestData(t)
+
+	rootResponse := &types.DescribeTaskListResponse{
+		Pollers: []*types.PollerInfo{{Identity: "test-poller"}},
+		TaskListStatus: &types.TaskListStatus{
+			BacklogCountHint: 10,
+			ReadLevel:        100,
+			AckLevel:         90,
+		},
+		PartitionConfig: &types.TaskListPartitionConfig{
+			ReadPartitions:  createPartitions(2),
+			WritePartitions: createPartitions(2),
+		},
+	}
+	partitionResponse := &types.DescribeTaskListResponse{
+		TaskListStatus: &types.TaskListStatus{
+			BacklogCountHint: 32,
+			ReadLevel:        200,
+			AckLevel:         168,
+		},
+	}
+	gomock.InOrder(
+		td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(rootResponse, nil),
+		td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, request *types.DescribeTaskListRequest, _ ...yarpc.CallOption) (*types.DescribeTaskListResponse, error) {
+				assert.Equal(t, "/__cadence_sys/test-tasklist/1", request.TaskList.GetName())
+				assert.Equal(t, types.TaskListTypeDecision, request.GetTaskListType())
+				return partitionResponse, nil
+			}),
+	)
+
+	cliCtx := clitest.NewCLIContext(
+		t,
+		td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.StringArgument(FlagTaskList, testTaskList),
+		clitest.StringArgument(FlagTaskListType, testTaskListType),
+		clitest.BoolArgument(FlagAllPartitions, true),
+	)
+	err := AdminDescribeTaskList(cliCtx)
+	assert.NoError(t, err)
+
+	output := td.consoleOutput()
+	assert.Contains(t, output, "Total (2 partitions)")
+	assert.Contains(t, output, "42")
+	assert.Contains(t, output, "/__cadence_sys/test-tasklist/1")
+}
+
+func TestAdminDescribeTaskList_AllPartitionsFails(t *testing.T) {
+	td := newCLITestData(t)
+
+	rootResponse := &types.DescribeTaskListResponse{
+		TaskListStatus: &types.TaskListStatus{},
+		PartitionConfig: &types.TaskListPartitionConfig{
+			ReadPartitions: createPartitions(2),
+		},
+	}
+	gomock.InOrder(
+		td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(rootResponse, nil),
+		td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("partition unavailable")),
+	)
+
+	cliCtx := clitest.NewCLIContext(
+		t,
+		td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.StringArgument(FlagTaskList, testTaskList),
+		clitest.BoolArgument(FlagAllPartitions, true),
+	)
+	err := AdminDescribeTaskList(cliCtx)
+	assert.ErrorContains(t, err, "Failed to describe task list partitions")
+}
+
+func TestEscapePrometheusLabel(t *testing.T) {
+	assert.Equal(t, `a\"b\\c\nd`, escapePrometheusLabel("a\"b\\c\nd"))
+}
+
 func TestAdminDescribeTaskList_DescribeTaskListFails(t *testing.T) {
 	td := newCLITestData(t)
 
@@ -84,7 +208,8 @@ func TestAdminDescribeTaskList_NoTaskListStatus(t *testing.T) {
 
 	cliCtx := newTaskListCLIContext(t, td.app)
 	err := AdminDescribeTaskList(cliCtx)
-	assert.ErrorContains(t, err, "No tasklist status information.")
+	assert.NoError(t, err)
+	assert.Contains(t, td.consoleOutput(), "Task list exists but has no status information: test-tasklist")
 }
 
 func TestAdminDescribeTaskList_NoPollers(t *testing.T) {
@@ -103,7 +228,44 @@ func TestAdminDescribeTaskList_NoPollers(t *testing.T) {
 
 	cliCtx := newTaskListCLIContext(t, td.app)
 	err := AdminDescribeTaskList(cliCtx)
-	assert.ErrorContains(t, err, "No poller for tasklist: test-tasklist")
+	assert.NoError(t, err)
+	assert.Contains(t, td.consoleOutput(), "Task list exists but has no pollers: test-tasklist")
+	assert.NotContains(t, td.consoleOutput(), "no active pollers")
+}
+
+func TestAdminDescribeTaskList_BacklogWithoutPollers(t *testing.T) {
+	td := newCLITestData(t)
+	td.mockFrontendClient.EXPECT().
+		DescribeTaskList(gomock.Any(), gomock.Any()).
+		Return(&types.DescribeTaskListResponse{TaskListStatus: &types.TaskListStatus{BacklogCountHint: 42}}, nil).
+		Times(1)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.StringArgument(FlagTaskList, testTaskList),
+		clitest.StringArgument(FlagTaskListType, testTaskListType),
+		clitest.BoolArgument(FlagFailOnEmpty, true),
+	)
+	err := AdminDescribeTaskList(cliCtx)
+	assert.ErrorContains(t, err, "Task list exists but has no pollers: test-tasklist")
+	assert.Contains(t, td.consoleOutput(), "Warning: backlog of 42 decision tasks present but no active pollers")
+}
+
+func TestAdminDescribeTaskList_FailOnEmpty(t *testing.T) {
+	td := newCLITestData(t)
+	td.mockFrontendClient.EXPECT().
+		DescribeTaskList(gomock.Any(), gomock.Any()).
+		Return(&types.DescribeTaskListResponse{TaskListStatus: &types.TaskListStatus{}}, nil).
+		Times(1)
+
+	cliCtx := clitest.NewCLIContext(t, td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.StringArgument(FlagTaskList, testTaskList),
+		clitest.StringArgument(FlagTaskListType, testTaskListType),
+		clitest.BoolArgument(FlagFailOnEmpty, true),
+	)
+	err := AdminDescribeTaskList(cliCtx)
+	assert.ErrorContains(t, err, "Task list exists but has no pollers: test-tasklist")
 }
 
 func TestAdminDescribeTaskList_GetRequiredOptionDomainError(t *testing.T) {
@@ -262,6 +424,75 @@ func TestAdminListTaskList(t *testing.T) {
 	}
 }
 
+func TestAdminListTaskList_WithStatus(t *testing.T) {
+	td := newCLITestData(t)
+
+	td.mockFrontendClient.EXPECT().GetTaskListsByDomain(gomock.Any(), gomock.Any()).
+		Return(&types.GetTaskListsByDomainResponse{
+			DecisionTaskListMap: map[string]*types.DescribeTaskListResponse{"decision-tasklist": {}},
+			ActivityTaskListMap: map[string]*types.DescribeTaskListResponse{"activity-tasklist": {}},
+		}, nil)
+	td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *types.DescribeTaskListRequest, _ ...yarpc.CallOption) (*types.DescribeTaskListResponse, error) {
+			assert.Equal(t, testDomain, request.GetDomain())
+			assert.True(t, request.GetIncludeTaskListStatus())
+			wantType := types.TaskListTypeDecision
+			if request.TaskList.GetName() == "activity-tasklist" {
+				wantType = types.TaskListTypeActivity
+			}
+			assert.Equal(t, wantType, request.GetTaskListType())
+			return &types.DescribeTaskListResponse{TaskListStatus: &types.TaskListStatus{BacklogCountHint: 5}}, nil
+		}).Times(2)
+
+	cliCtx := clitest.NewCLIContext(
+		t,
+		td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.BoolArgument(FlagWithStatus, true),
+		clitest.IntArgument(FlagConcurrency, 1),
+		clitest.StringArgument(FlagSortBy, "backlog"),
+	)
+	assert.NoError(t, AdminListTaskList(cliCtx))
+}
+
+func TestAdminListTaskList_WithStatusDescribeFails(t *testing.T) {
+	td := newCLITestData(t)
+
+	td.mockFrontendClient.EXPECT().GetTaskListsByDomain(gomock.Any(), gomock.Any()).
+		Return(&types.GetTaskListsByDomainResponse{
+			DecisionTaskListMap: map[string]*types.DescribeTaskListResponse{"decision-tasklist": {}},
+		}, nil)
+	td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("describe failed"))
+
+	cliCtx := clitest.NewCLIContext(
+		t,
+		td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.BoolArgument(FlagWithStatus, true),
+	)
+	assert.ErrorContains(t, AdminListTaskList(cliCtx), "Failed to describe task lists")
+}
+
+func TestAdminListTaskList_InvalidSortBy(t *testing.T) {
+	td := newCLITestData(t)
+
+	cliCtx := clitest.NewCLIContext(
+		t,
+		td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.StringArgument(FlagSortBy, "backlog"),
+	)
+	assert.ErrorContains(t, AdminListTaskList(cliCtx), "Sorting by backlog requires --with_status")
+
+	cliCtx = clitest.NewCLIContext(
+		t,
+		td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.StringArgument(FlagSortBy, "name"),
+	)
+	assert.ErrorContains(t, AdminListTaskList(cliCtx), "Invalid sort order")
+}
+
 func TestAdminUpdateTaskListPartitionConfig(t *testing.T) {
 	// Define table of test cases
 	tests := []struct {
@@ -410,3 +641,212 @@ func newTaskListCLIContext(t *testing.T, app *cli.App) *cli.Context {
 		clitest.StringArgument(FlagTaskListType, testTaskListType),
 	)
 }
+
+func TestAdminDescribeTaskList_AllTypes(t *testing.T) {
+	td := newCLITestData(t)
+
+	gomock.InOrder(
+		td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, request *types.DescribeTaskListRequest, _ ...yarpc.CallOption) (*types.DescribeTaskListResponse, error) {
+				assert.Equal(t, types.TaskListTypeDecision, request.GetTaskListType())
+				return &types.DescribeTaskListResponse{
+					Pollers:        []*types.PollerInfo{{Identity: "decision-poller"}},
+					TaskListStatus: &types.TaskListStatus{BacklogCountHint: 3},
+				}, nil
+			}),
+		td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, request *types.DescribeTaskListRequest, _ ...yarpc.CallOption) (*types.DescribeTaskListResponse, error) {
+				assert.Equal(t, types.TaskListTypeActivity, request.GetTaskListType())
+				return &types.DescribeTaskListResponse{
+					TaskListStatus: &types.TaskListStatus{BacklogCountHint: 7},
+				}, nil
+			}),
+	)
+
+	promFile := filepath.Join(t.TempDir(), "tasklist.prom")
+	cliCtx := clitest.NewCLIContext(
+		t,
+		td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.StringArgument(FlagTaskList, testTaskList),
+		clitest.StringArgument(FlagTaskListType, "all"),
+		clitest.StringArgument(FlagPrometheusFile, promFile),
+	)
+	err := AdminDescribeTaskList(cliCtx)
+	assert.NoError(t, err)
+
+	output := td.consoleOutput()
+	assert.Contains(t, output, "Decision task list:")
+	assert.Contains(t, output, "decision-poller")
+	assert.Contains(t, output, "Activity task list:")
+	assert.Contains(t, output, "Task list exists but has no pollers: test-tasklist")
+
+	content, err := os.ReadFile(promFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `cadence_tasklist_backlog{domain="test-domain",tasklist="test-tasklist",tasklist_type="decision"} 3`)
+	assert.Contains(t, string(content), `cadence_tasklist_backlog{domain="test-domain",tasklist="test-tasklist",tasklist_type="activity"} 7`)
+}
+
+func TestPrintTaskListPollers(t *testing.T) {
+	now := time.Now()
+	pollers := []*types.PollerInfo{
+		{Identity: "stale-poller", LastAccessTime: common.Int64Ptr(now.Add(-time.Hour).UnixNano()), RatePerSecond: 100},
+		{Identity: "active-poller", LastAccessTime: common.Int64Ptr(now.UnixNano()), RatePerSecond: 42.5},
+	}
+
+	buf := &bytes.Buffer{}
+	err := printTaskListPollers(buf, pollers, types.TaskListTypeDecision)
+	assert.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "RATE PER SECOND")
+	assert.Contains(t, output, "42.5")
+	assert.Contains(t, output, "1h0m0s ago")
+	assert.Less(t, strings.Index(output, "active-poller"), strings.Index(output, "stale-poller"))
+}
+
+func TestFormatTimeAgo(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, "12s ago", formatTimeAgo(now, now.Add(-12*time.Second-300*time.Millisecond)))
+	assert.Equal(t, "0s ago", formatTimeAgo(now, now.Add(time.Second)))
+}
+
+func TestAdminDescribeTaskListPartitions(t *testing.T) {
+	td := newCLITestData(t)
+
+	td.mockFrontendClient.EXPECT().ListTaskListPartitions(gomock.Any(), &types.ListTaskListPartitionsRequest{
+		Domain:   testDomain,
+		TaskList: &types.TaskList{Name: testTaskList},
+	}).Return(&types.ListTaskListPartitionsResponse{
+		DecisionTaskListPartitions: []*types.TaskListPartitionMetadata{
+			{Key: testTaskList, OwnerHostName: "matching-host-1"},
+			{Key: "/__cadence_sys/test-tasklist/1", OwnerHostName: "matching-host-2"},
+		},
+		ActivityTaskListPartitions: []*types.TaskListPartitionMetadata{
+			{Key: testTaskList, OwnerHostName: "matching-host-3"},
+		},
+	}, nil)
+
+	cliCtx := clitest.NewCLIContext(
+		t,
+		td.app,
+		clitest.StringArgument(FlagDomain, testDomain),
+		clitest.StringArgument(FlagTaskList, testTaskList),
+	)
+	err := AdminDescribeTaskListPartitions(cliCtx)
+	assert.NoError(t, err)
+
+	output := td.consoleOutput()
+	assert.Contains(t, output, "/__cadence_sys/test-tasklist/1")
+	assert.Contains(t, output, "matching-host-2")
+	assert.Contains(t, output, "matching-host-3")
+}
+
+func TestAdminDescribeTaskListPartitions_Errors(t *testing.T) {
+	td := newCLITestData(t)
+	td.mockFrontendClient.EXPECT().ListTaskListPartitions(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("matching unavailable"))
+	cliCtx := clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagDomain, testDomain), clitest.StringArgument(FlagTaskList, testTaskList))
+	assert.ErrorContains(t, AdminDescribeTaskListPartitions(cliCtx), "Operation ListTaskListPartitions failed.")
+
+	td = newCLITestData(t)
+	td.mockFrontendClient.EXPECT().ListTaskListPartitions(gomock.Any(), gomock.Any()).Return(&types.ListTaskListPartitionsResponse{}, nil)
+	cliCtx = clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagDomain, testDomain), clitest.StringArgument(FlagTaskList, testTaskList))
+	assert.ErrorContains(t, AdminDescribeTaskListPartitions(cliCtx), "No partitions found for tasklist: test-tasklist")
+
+	td = newCLITestData(t)
+	cliCtx = clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagDomain, testDomain))
+	assert.ErrorContains(t, AdminDescribeTaskListPartitions(cliCtx), "Required flag not found")
+}
+
+func TestAdminDrainTaskList(t *testing.T) {
+	describeResponse := func(backlog int64) *types.DescribeTaskListResponse {
+		return &types.DescribeTaskListResponse{
+			Pollers:        []*types.PollerInfo{{Identity: "worker"}},
+			TaskListStatus: &types.TaskListStatus{BacklogCountHint: backlog},
+		}
+	}
+	tests := []struct {
+		name           string
+		mockSetup      func(td *cliTestData)
+		interval       string
+		args           []clitest.CliArgument
+		interrupt      bool
+		errContains    string
+		expectedOutput []string
+	}{
+		{
+			name: "drains",
+			mockSetup: func(td *cliTestData) {
+				gomock.InOrder(
+					td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(describeResponse(10), nil),
+					td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(describeResponse(4), nil),
+					td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(describeResponse(0), nil),
+				)
+			},
+			expectedOutput: []string{"Backlog: 10\n", "Backlog: 4 (6 drained in", "Backlog: 0 (10 drained in", "Task list drained: test-tasklist\n"},
+		},
+		{
+			name: "sums the backlog of all types",
+			mockSetup: func(td *cliTestData) {
+				td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(describeResponse(0), nil).Times(2)
+			},
+			args:           []clitest.CliArgument{clitest.StringArgument(FlagTaskListType, "all")},
+			expectedOutput: []string{"Backlog: 0\n", "Task list drained: test-tasklist\n"},
+		},
+		{
+			name: "backlog without pollers",
+			mockSetup: func(td *cliTestData) {
+				td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).
+					Return(&types.DescribeTaskListResponse{TaskListStatus: &types.TaskListStatus{BacklogCountHint: 3}}, nil).AnyTimes()
+			},
+			args:           []clitest.CliArgument{clitest.StringArgument(FlagWaitTimeout, "20ms")},
+			errContains:    "Task list test-tasklist not drained within --wait_timeout, 3 tasks left",
+			expectedOutput: []string{"Warning: backlog of 3 decision tasks present but no active pollers"},
+		},
+		{
+			name: "interrupted",
+			mockSetup: func(td *cliTestData) {
+				td.mockFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(describeResponse(3), nil)
+			},
+			interval:    "1h",
+			interrupt:   true,
+			errContains: "Interrupted with 3 tasks left",
+		},
+		{
+			name:        "invalid interval",
+			interval:    "0s",
+			errContains: "--interval must be positive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newCLITestData(t)
+			if tt.mockSetup != nil {
+				tt.mockSetup(td)
+			}
+			if tt.interval == "" {
+				tt.interval = "1ms"
+			}
+			args := append([]clitest.CliArgument{
+				clitest.StringArgument(FlagDomain, testDomain),
+				clitest.StringArgument(FlagTaskList, testTaskList),
+				clitest.StringArgument(FlagInterval, tt.interval),
+			}, tt.args...)
+			cliCtx := clitest.NewCLIContext(t, td.app, args...)
+			if tt.interrupt {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(10*time.Millisecond, cancel)
+				cliCtx.Context = ctx
+			}
+			err := AdminDrainTaskList(cliCtx)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errContains)
+			}
+			for _, line := range tt.expectedOutput {
+				assert.Contains(t, td.consoleOutput(), line)
+			}
+		})
+	}
+}
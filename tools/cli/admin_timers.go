@@ -243,11 +243,11 @@ func (cl *dbLoadCloser) Load() ([]*persistence.TimerTaskInfo, error) {
 
 	st, err := parseSingleTs(startDate)
 	if err != nil {
-		return nil, fmt.Errorf("wrong date format for "+FlagEndDate+" Error: %v", err)
+		return nil, fmt.Errorf("wrong date format for "+FlagEndDate+" Error: %w", err)
 	}
 	et, err := parseSingleTs(endDate)
 	if err != nil {
-		return nil, fmt.Errorf("wrong date format for "+FlagEndDate+" Error: %v", err)
+		return nil, fmt.Errorf("wrong date format for "+FlagEndDate+" Error: %w", err)
 	}
 
 	var timers []*persistence.TimerTaskInfo
@@ -286,7 +286,7 @@ func (cl *dbLoadCloser) Load() ([]*persistence.TimerTaskInfo, error) {
 		err = throttleRetry.Do(cl.ctx.Context, op)
 
 		if err != nil {
-			return nil, fmt.Errorf("cannot get timer tasks for shard: %v", err)
+			return nil, fmt.Errorf("cannot get timer tasks for shard: %w", err)
 		}
 
 		token = resp.NextPageToken
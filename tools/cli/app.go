@@ -23,9 +23,14 @@ package cli
 import (
 	"fmt"
 	"io"
+	"time"
 
+	"github.com/uber-go/tally"
 	"github.com/urfave/cli/v2"
 
+	"github.com/uber/cadence/client/admin"
+	"github.com/uber/cadence/client/frontend"
+	"github.com/uber/cadence/client/wrappers/metered"
 	"github.com/uber/cadence/common/client"
 	"github.com/uber/cadence/common/metrics"
 )
@@ -104,13 +109,18 @@ func NewCliApp(cf ClientFactory, opts ...CLIAppOptions) *cli.App {
 		},
 		&cli.StringFlag{
 			Name:    FlagJWT,
-			Usage:   "optional JWT for authorization. Either this or --jwt-private-key is needed for jwt authorization",
+			Usage:   "optional JWT for authorization. Either this, --jwt-file or --jwt-private-key is needed for jwt authorization",
 			EnvVars: []string{"CADENCE_CLI_JWT"},
 		},
+		&cli.StringFlag{
+			Name:    FlagJWTFile,
+			Usage:   "optional path of a file containing the JWT for authorization. --jwt flag has priority over this one if both provided",
+			EnvVars: []string{"CADENCE_CLI_JWT_FILE"},
+		},
 		&cli.StringFlag{
 			Name:    FlagJWTPrivateKey,
 			Aliases: []string{"jwt-pk"},
-			Usage:   "optional private key path to create JWT. Either this or --jwt is needed for jwt authorization. --jwt flag has priority over this one if both provided",
+			Usage:   "optional private key path to create JWT. Either this, --jwt or --jwt-file is needed for jwt authorization. --jwt and --jwt-file flags have priority over this one if provided",
 			EnvVars: []string{"CADENCE_CLI_JWT_PRIVATE_KEY"},
 		},
 		&cli.StringFlag{
@@ -125,6 +135,101 @@ func NewCliApp(cf ClientFactory, opts ...CLIAppOptions) *cli.App {
 			Usage:   "optional argument for path to TLS certificate. Defaults to an empty string if not provided",
 			EnvVars: []string{"CADENCE_CLI_TLS_CERT_PATH"},
 		},
+		&cli.StringFlag{
+			Name:    FlagTLSKeyPath,
+			Aliases: []string{"tls-key-path"},
+			Usage:   "optional argument for path to the TLS client key for mutual TLS. When set, --tls_cert_path is the client certificate",
+			EnvVars: []string{"CADENCE_CLI_TLS_KEY_PATH"},
+		},
+		&cli.StringFlag{
+			Name:    FlagTLSCaPath,
+			Aliases: []string{"tls-ca-path"},
+			Usage:   "optional argument for path to the CA bundle used to verify the server certificate",
+			EnvVars: []string{"CADENCE_CLI_TLS_CA_PATH"},
+		},
+		&cli.BoolFlag{
+			Name:    FlagTLSInsecureSkipVerify,
+			Aliases: []string{"insecure-skip-verify"},
+			Usage:   "skip verification of the server TLS certificate. Only for debugging dev clusters with self-signed certificates, never use in production",
+		},
+		&cli.IntFlag{
+			Name:    FlagRPCRetries,
+			Aliases: []string{"rpc-retries"},
+			Usage:   "optional number of times admin RPCs are retried on transient errors (e.g. shard movement), with exponential backoff",
+			EnvVars: []string{"CADENCE_CLI_RPC_RETRIES"},
+		},
+		&cli.DurationFlag{
+			Name:    FlagRPCRetryInterval,
+			Aliases: []string{"rpc-retry-interval"},
+			Value:   time.Second,
+			Usage:   "optional initial interval between admin RPC retries, doubled after every retry",
+		},
+		&cli.BoolFlag{
+			Name:  FlagAppend,
+			Usage: "optional, append to the output file of commands that write one instead of overwriting it. Only for line based output, such as admin workflow show --output_jsonl",
+		},
+		&cli.BoolFlag{
+			Name:    FlagNoColor,
+			Aliases: []string{"no-color"},
+			Usage:   "optional, disable colored output. Color is also disabled when the NO_COLOR environment variable is set or output is not a terminal",
+		},
+		&cli.BoolFlag{
+			Name:  FlagCompact,
+			Usage: "optional, print JSON output on a single line instead of indented",
+		},
+		&cli.StringFlag{
+			Name:    FlagGlobalOutputFormat,
+			Aliases: []string{"output-format"},
+			Usage:   "optional output format [table|json|yaml] for commands that support it. A command's own --format flag takes precedence",
+			EnvVars: []string{"CADENCE_CLI_OUTPUT"},
+		},
+		&cli.StringFlag{
+			Name:    FlagConfig,
+			Usage:   "optional path to a YAML file with named cluster profiles (address, transport, tls_cert_path, tls_key_path, tls_ca_path, jwt, jwt_private_key)",
+			EnvVars: []string{"CADENCE_CLI_CONFIG"},
+		},
+		&cli.StringFlag{
+			Name:    FlagMetricsEndpoint,
+			Aliases: []string{"metrics-endpoint"},
+			Usage:   "optional statsd host:port to emit the latency and success/failure counts of the command and of its RPCs to. Nothing is emitted when unset",
+			EnvVars: []string{"CADENCE_CLI_METRICS_ENDPOINT"},
+		},
+		&cli.StringFlag{
+			Name:    FlagProfileCPU,
+			Aliases: []string{"profile-cpu"},
+			Usage:   "optional file to write a pprof CPU profile of the command to",
+			Hidden:  true,
+		},
+		&cli.StringFlag{
+			Name:    FlagProfileMem,
+			Aliases: []string{"profile-mem"},
+			Usage:   "optional file to write a pprof heap profile to when the command ends",
+			Hidden:  true,
+		},
+		&cli.StringFlag{
+			Name:    FlagProfile,
+			Usage:   "optional profile of --config to use. Defaults to the \"default\" profile if present. Flags and environment variables override profile settings",
+			EnvVars: []string{"CADENCE_CLI_PROFILE"},
+		},
+	}
+	app.Before = func(c *cli.Context) error {
+		if err := loadProfile(c); err != nil {
+			return err
+		}
+		applyOutputStyle(c)
+		if err := validateTransport(c); err != nil {
+			return err
+		}
+		if err := initMetrics(c); err != nil {
+			return err
+		}
+		return startProfiling(c)
+	}
+	app.After = func(c *cli.Context) error {
+		if err := closeMetrics(c); err != nil {
+			return err
+		}
+		return stopProfiling(c)
 	}
 	app.Commands = []*cli.Command{
 		{
@@ -242,6 +347,7 @@ func NewCliApp(cf ClientFactory, opts ...CLIAppOptions) *cli.App {
 			Subcommands: newClusterCommands(),
 		},
 	}
+	instrumentCommands(app.Commands)
 	app.CommandNotFound = func(context *cli.Context, command string) {
 		output := getDeps(context).Output()
 		printMessage(output, "command not found: "+command)
@@ -324,4 +430,36 @@ type deps struct {
 	ClientFactory
 	IOHandler
 	ManagerFactory
+
+	// metricsScope is set when --metrics_endpoint is, to emit metrics of the command and its RPCs
+	metricsScope tally.Scope
+	closeMetrics func()
+	// stopProfiling is set when --profile_cpu or --profile_mem is, to write the profiles when the command ends
+	stopProfiling func() error
+}
+
+// ServerAdminClient wraps the admin client of the ClientFactory to retry transient errors when --rpc_retries is set,
+// and to emit metrics of the calls when --metrics_endpoint is set
+func (d *deps) ServerAdminClient(c *cli.Context) (admin.Client, error) {
+	client, err := d.ClientFactory.ServerAdminClient(c)
+	if err != nil {
+		return nil, err
+	}
+	client = withRPCRetries(c, client)
+	if d.metricsScope != nil {
+		client = metered.NewAdminClient(client, metrics.NewClient(d.metricsScope, metrics.Common))
+	}
+	return client, nil
+}
+
+// ServerFrontendClient wraps the frontend client of the ClientFactory to emit metrics of the calls when --metrics_endpoint is set
+func (d *deps) ServerFrontendClient(c *cli.Context) (frontend.Client, error) {
+	client, err := d.ClientFactory.ServerFrontendClient(c)
+	if err != nil {
+		return nil, err
+	}
+	if d.metricsScope != nil {
+		client = metered.NewFrontendClient(client, metrics.NewClient(d.metricsScope, metrics.Common))
+	}
+	return client, nil
 }
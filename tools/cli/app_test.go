@@ -22,9 +22,11 @@ package cli
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -36,6 +38,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/mock/gomock"
 
@@ -109,10 +112,14 @@ var domainName = "cli-test-domain"
 
 // Implements IOHandler to be used for validation in tests
 type testIOHandler struct {
+	input       io.Reader
 	outputBytes bytes.Buffer
 }
 
 func (t *testIOHandler) Input() io.Reader {
+	if t.input != nil {
+		return t.input
+	}
 	return os.Stdin
 }
 
@@ -487,6 +494,16 @@ func (s *cliAppSuite) TestListWorkflow() {
 	s.Nil(err)
 }
 
+func (s *cliAppSuite) TestListWorkflow_IDsOnly() {
+	resp := listClosedWorkflowExecutionsResponse
+	countWorkflowResp := &types.CountWorkflowExecutionsResponse{}
+	s.serverFrontendClient.EXPECT().CountWorkflowExecutions(gomock.Any(), gomock.Any()).Return(countWorkflowResp, nil)
+	s.serverFrontendClient.EXPECT().ListClosedWorkflowExecutions(gomock.Any(), gomock.Any()).Return(resp, nil)
+	err := s.app.Run([]string{"", "--do", domainName, "workflow", "list", "--ids-only"})
+	s.Nil(err)
+	s.Equal("test-list-workflow-id\n", s.testIOHandler.outputBytes.String())
+}
+
 func (s *cliAppSuite) TestListWorkflow_WithWorkflowID() {
 	resp := &types.ListClosedWorkflowExecutionsResponse{}
 	countWorkflowResp := &types.CountWorkflowExecutionsResponse{}
@@ -589,6 +606,63 @@ func (s *cliAppSuite) TestAdminDescribeWorkflow() {
 	s.Nil(err)
 }
 
+func (s *cliAppSuite) TestAdminDescribeWorkflow_DecodeSearchAttributes() {
+	resp := &types.AdminDescribeWorkflowExecutionResponse{
+		ShardID:                "test-shard-id",
+		HistoryAddr:            "ip:port",
+		MutableStateInDatabase: "{\"ExecutionInfo\":{\"BranchToken\":\"WQsACgAAACQ2MzI5YzEzMi1mMGI0LTQwZmUtYWYxMS1hODVmMDA3MzAzODQLABQAAAAkOWM5OWI1MjItMGEyZi00NTdmLWEyNDgtMWU0OTA0ZDg4YzVhDwAeDAAAAAAA\",\"SearchAttributes\":{\"CustomKeywordField\":\"ImtleXdvcmQi\"}}}",
+	}
+
+	s.serverAdminClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(resp, nil)
+	err := s.app.Run([]string{"", "--do", domainName, "admin", "wf", "describe", "-w", "test-wf-id", "--decode_search_attributes"})
+	s.Nil(err)
+}
+
+func (s *cliAppSuite) TestAdminDescribeWorkflow_ShowTimers() {
+	resp := &types.AdminDescribeWorkflowExecutionResponse{
+		ShardID:                "test-shard-id",
+		HistoryAddr:            "ip:port",
+		MutableStateInDatabase: "{\"ExecutionInfo\":{\"BranchToken\":\"WQsACgAAACQ2MzI5YzEzMi1mMGI0LTQwZmUtYWYxMS1hODVmMDA3MzAzODQLABQAAAAkOWM5OWI1MjItMGEyZi00NTdmLWEyNDgtMWU0OTA0ZDg4YzVhDwAeDAAAAAAA\"},\"TimerInfos\":{\"t1\":{\"TimerID\":\"t1\",\"ExpiryTime\":\"2020-01-01T00:00:00Z\"}}}",
+	}
+
+	s.serverAdminClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(resp, nil)
+	err := s.app.Run([]string{"", "--do", domainName, "admin", "wf", "describe", "-w", "test-wf-id", "--show_timers"})
+	s.Nil(err)
+}
+
+func (s *cliAppSuite) TestAdminDescribeWorkflow_Raw() {
+	resp := &types.AdminDescribeWorkflowExecutionResponse{
+		ShardID:                "test-shard-id",
+		HistoryAddr:            "ip:port",
+		MutableStateInDatabase: "{\"ExecutionInfo\":{\"BranchToken\":\"corrupt",
+	}
+
+	s.serverAdminClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(resp, nil).Times(2)
+	s.Error(s.app.Run([]string{"", "--do", domainName, "admin", "wf", "describe", "-w", "test-wf-id"}))
+	s.Nil(s.app.Run([]string{"", "--do", domainName, "admin", "wf", "describe", "-w", "test-wf-id", "--raw"}))
+	err := s.app.Run([]string{"", "--do", domainName, "admin", "wf", "describe", "-w", "test-wf-id", "--raw", "--show_timers"})
+	s.ErrorContains(err, "--raw cannot be used with --decode_search_attributes or --show_timers")
+}
+
+func (s *cliAppSuite) TestAdminDescribeWorkflow_JSONPath() {
+	resp := &types.AdminDescribeWorkflowExecutionResponse{
+		ShardID:                "test-shard-id",
+		HistoryAddr:            "ip:port",
+		MutableStateInDatabase: "{\"ExecutionInfo\":{\"WorkflowID\":\"test-wf-id\"}}",
+	}
+
+	s.serverAdminClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(resp, nil).Times(2)
+	s.Nil(s.app.Run([]string{"", "--do", domainName, "admin", "wf", "describe", "-w", "test-wf-id", "--json-path", "ExecutionInfo.WorkflowID"}))
+	err := s.app.Run([]string{"", "--do", domainName, "admin", "wf", "describe", "-w", "test-wf-id", "--json-path", "ExecutionInfo.Missing"})
+	s.ErrorContains(err, `no field "Missing" in $.ExecutionInfo`)
+}
+
+func (s *cliAppSuite) TestAdminDecodeBranchToken() {
+	token := "WQsACgAAACQ2MzI5YzEzMi1mMGI0LTQwZmUtYWYxMS1hODVmMDA3MzAzODQLABQAAAAkOWM5OWI1MjItMGEyZi00NTdmLWEyNDgtMWU0OTA0ZDg4YzVhDwAeDAAAAAAA"
+	s.Nil(s.app.Run([]string{"", "admin", "wf", "decode-branch-token", "--branch-token", token}))
+	s.Error(s.app.Run([]string{"", "admin", "wf", "decode-branch-token", "--branch-token", "bm90IGEgdG9rZW4="}))
+}
+
 func (s *cliAppSuite) TestAdminDescribeWorkflow_Failed() {
 	s.serverAdminClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil, &types.BadRequestError{"faked error"})
 	s.Error(s.app.Run(([]string{"", "--do", domainName, "admin", "wf", "describe", "-w", "test-wf-id"})))
@@ -615,6 +689,7 @@ func (s *cliAppSuite) TestAdminFailover() {
 	resp := &types.StartWorkflowExecutionResponse{RunID: uuid.New()}
 	s.serverFrontendClient.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(resp, nil)
 	s.serverFrontendClient.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	expectReplicationClusters(s.serverFrontendClient, "active", "standby")
 	err := s.app.Run([]string{"", "admin", "cl", "fo", "start", "--tc", "standby", "--sc", "active"})
 	s.Nil(err)
 }
@@ -1071,3 +1146,84 @@ func TestWithIOHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsEndpoint(t *testing.T) {
+	t.Run("emits command and RPC metrics", func(t *testing.T) {
+		td := newCLITestData(t)
+		scope := tally.NewTestScope("", nil)
+		td.app.Metadata[depsKey].(*deps).metricsScope = scope
+		td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).Return(&types.DescribeQueueResponse{}, nil)
+		td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).Return(nil, errors.New("critical error"))
+
+		require.NoError(t, td.app.Run([]string{"", "admin", "queue", "describe", "--shard_id", "1", "--cluster", "c", "--queue_type", "2"}))
+		require.Error(t, td.app.Run([]string{"", "admin", "queue", "describe", "--shard_id", "1", "--cluster", "c", "--queue_type", "2"}))
+
+		snapshot := scope.Snapshot()
+		counters := map[string]int64{}
+		for _, counter := range snapshot.Counters() {
+			counters[counter.Name()] += counter.Value()
+		}
+		assert.Equal(t, int64(1), counters["command_successes"])
+		assert.Equal(t, int64(1), counters["command_failures"])
+		assert.Equal(t, int64(2), counters["cadence_client_requests"])
+		assert.Equal(t, int64(1), counters["cadence_client_errors"])
+		timers := map[string]map[string]string{}
+		for _, timer := range snapshot.Timers() {
+			timers[timer.Name()] = timer.Tags()
+		}
+		require.Contains(t, timers, "command_latency")
+		assert.Equal(t, "admin_queue_describe", timers["command_latency"]["command"])
+	})
+
+	t.Run("reports to statsd", func(t *testing.T) {
+		td := newCLITestData(t)
+		td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).Return(&types.DescribeQueueResponse{}, nil)
+
+		require.NoError(t, td.app.Run([]string{"", "--metrics_endpoint", "127.0.0.1:8125", "admin", "queue", "describe", "--shard_id", "1", "--cluster", "c", "--queue_type", "2"}))
+		d := td.app.Metadata[depsKey].(*deps)
+		assert.NotNil(t, d.metricsScope)
+		assert.Nil(t, d.closeMetrics, "metrics are closed after the command")
+	})
+}
+
+func TestProfiling(t *testing.T) {
+	td := newCLITestData(t)
+	td.mockAdminClient.EXPECT().DescribeQueue(gomock.Any(), gomock.Any()).Return(&types.DescribeQueueResponse{}, nil)
+	dir := t.TempDir()
+	cpuProfile := filepath.Join(dir, "cpu.pprof")
+	memProfile := filepath.Join(dir, "mem.pprof")
+
+	require.NoError(t, td.app.Run([]string{"", "--profile-cpu", cpuProfile, "--profile-mem", memProfile,
+		"admin", "queue", "describe", "--shard_id", "1", "--cluster", "c", "--queue_type", "2"}))
+	for _, profile := range []string{cpuProfile, memProfile} {
+		info, err := os.Stat(profile)
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size(), profile)
+	}
+	assert.Nil(t, td.app.Metadata[depsKey].(*deps).stopProfiling)
+}
+
+func TestGlobalOutputFormatNotShadowed(t *testing.T) {
+	app := NewCliApp(&clientFactoryMock{})
+	global := map[string]bool{}
+	for _, flag := range app.Flags {
+		if flag.Names()[0] == FlagGlobalOutputFormat {
+			for _, name := range flag.Names() {
+				global[name] = true
+			}
+		}
+	}
+	require.NotEmpty(t, global)
+	var walk func(path string, commands []*cli.Command)
+	walk = func(path string, commands []*cli.Command) {
+		for _, command := range commands {
+			for _, flag := range command.Flags {
+				for _, name := range flag.Names() {
+					assert.False(t, global[name], "flag --%s of %s%s shadows the global output format flag", name, path, command.Name)
+				}
+			}
+			walk(path+command.Name+" ", command.Subcommands)
+		}
+	}
+	walk("", app.Commands)
+}
@@ -301,8 +301,7 @@ func createDataStore(c *cli.Context) (config.DataStore, error) {
 			return config.DataStore{SQL: &config.SQL{PluginName: dbType}}, nil
 		}
 	}
-	fmt.Errorf("The DB type is not supported. Options are: %s. Error %v", supportedDBs, nil)
-	return config.DataStore{}, nil
+	return config.DataStore{}, fmt.Errorf("The DB type %q is not supported. Options are: %s", dbType, supportedDBs)
 }
 
 func overrideNoSQLDataStore(c *cli.Context, cfg *config.NoSQL) {
@@ -271,6 +271,17 @@ func TestOverrideDataStore(t *testing.T) {
 				Password:    "testpass",
 			},
 		},
+		{
+			name: "OverrideDBType_Unsupported",
+			setupContext: func(app *cli.App) *cli.Context {
+				set := flag.NewFlagSet("test", 0)
+				set.String(FlagDBType, "", "DB type flag")
+				require.NoError(t, set.Set(FlagDBType, "unknown-db"))
+				return cli.NewContext(app, set, nil)
+			},
+			inputDataStore: config.DataStore{},
+			expectedError:  `The DB type "unknown-db" is not supported`,
+		},
 	}
 
 	for _, tt := range tests {
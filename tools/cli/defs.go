@@ -27,6 +27,7 @@ import (
 	"github.com/fatih/color"
 
 	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/tools/common/commoncli"
 )
 
 const (
@@ -72,6 +73,9 @@ const (
 	searchAttrInputSeparator = "|"
 
 	defaultGracefulFailoverTimeoutInSeconds = 60
+
+	exitCodeNotFound = commoncli.ExitCodeNotFound // exit code used when the requested entity does not exist
+	exitCodeEmpty    = commoncli.ExitCodeEmpty    // exit code used with --fail_on_empty when the requested entity exists but has nothing to show
 )
 
 var envKeysForUserName = []string{
@@ -111,6 +115,10 @@ var (
 	colorRed     = color.New(color.FgRed).SprintFunc()
 	colorMagenta = color.New(color.FgMagenta).SprintFunc()
 	colorGreen   = color.New(color.FgGreen).SprintFunc()
+	colorBlue    = color.New(color.FgBlue).SprintFunc()
+	colorYellow  = color.New(color.FgYellow).SprintFunc()
+	colorCyan    = color.New(color.FgCyan).SprintFunc()
+	colorFaint   = color.New(color.Faint).SprintFunc()
 
 	optionErr               = "there is something wrong with your command options"
 	osExit                  = os.Exit
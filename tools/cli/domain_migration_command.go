@@ -296,12 +296,12 @@ func (d *domainMigrationCLIImpl) SearchAttributesChecker(c *cli.Context) (Domain
 	for _, attr := range searchAttributes {
 		parts := strings.SplitN(attr, ":", 2)
 		if len(parts) != 2 {
-			return DomainMigrationRow{}, fmt.Errorf("Invalid search attribute format: %s, Error: %v", attr, nil)
+			return DomainMigrationRow{}, fmt.Errorf("Invalid search attribute format: %s", attr)
 		}
 		key, valueType := parts[0], parts[1]
 		ivt, err := parseIndexedValueType(valueType)
 		if err != nil {
-			return DomainMigrationRow{}, fmt.Errorf("Invalid search attribute type for %s: %s , Error: %v", key, valueType, err)
+			return DomainMigrationRow{}, fmt.Errorf("Invalid search attribute type for %s: %s: %w", key, valueType, err)
 		}
 		requiredAttributes[key] = ivt
 	}
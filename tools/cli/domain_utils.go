@@ -473,7 +473,7 @@ func getZone(c *cli.Context) string {
 func getConfigDir(c *cli.Context) (string, error) {
 	dirPath := c.String(FlagServiceConfigDir)
 	if len(dirPath) == 0 {
-		return "", fmt.Errorf("Must provide service configuration dir path. %v", nil)
+		return "", fmt.Errorf("Must provide service configuration dir path")
 	}
 	return dirPath, nil
 }
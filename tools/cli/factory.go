@@ -28,6 +28,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/olivere/elastic"
@@ -48,8 +49,10 @@ import (
 	"github.com/uber/cadence/client/admin"
 	"github.com/uber/cadence/client/frontend"
 	grpcClient "github.com/uber/cadence/client/wrappers/grpc"
+	"github.com/uber/cadence/client/wrappers/retryable"
 	"github.com/uber/cadence/client/wrappers/thrift"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
 	cc "github.com/uber/cadence/common/client"
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/tools/common/commoncli"
@@ -148,6 +151,29 @@ func (b *clientFactory) ServerAdminClient(c *cli.Context) (admin.Client, error)
 	return thrift.NewAdminClient(serverAdmin.New(clientConfig)), nil
 }
 
+// validateTransport rejects --transport values other than grpc and tchannel, an empty value means tchannel
+func validateTransport(c *cli.Context) error {
+	switch transport := c.String(FlagTransport); transport {
+	case "", grpcTransport, thriftTransport:
+		return nil
+	default:
+		return commoncli.Problem(fmt.Sprintf("Invalid --%s %q, valid values are %q and %q", FlagTransport, transport, grpcTransport, thriftTransport), nil)
+	}
+}
+
+// withRPCRetries retries the calls of client on transient errors, as configured by --rpc_retries and --rpc_retry_interval.
+// Non-transient errors like EntityNotExistsError or BadRequestError are returned without retrying.
+func withRPCRetries(c *cli.Context, client admin.Client) admin.Client {
+	retries := c.Int(FlagRPCRetries)
+	if retries <= 0 {
+		return client
+	}
+	policy := backoff.NewExponentialRetryPolicy(c.Duration(FlagRPCRetryInterval))
+	policy.SetMaximumAttempts(retries)
+	policy.SetExpirationInterval(backoff.NoInterval)
+	return retryable.NewAdminClient(client, policy, common.IsServiceTransientError)
+}
+
 // ServerFrontendClientForMigration builds a frontend client (based on server side thrift interface)
 func (b *clientFactory) ServerFrontendClientForMigration(c *cli.Context) (frontend.Client, error) {
 	err := b.ensureDispatcherForMigration(c)
@@ -234,6 +260,64 @@ func (b *clientFactory) ensureDispatcherForMigration(c *cli.Context) error {
 	return nil
 }
 
+// newTLSConfig builds the TLS config for the grpc transport, or returns nil if TLS is not configured.
+// Without --tls_key_path, --tls_cert_path is the server CA certificate as it always was.
+// With --tls_key_path, --tls_cert_path and --tls_key_path are the client certificate and key for mutual TLS,
+// and the server is verified with --tls_ca_path (or the system roots if not given).
+// With --insecure_skip_verify, the server certificate is not verified at all.
+func newTLSConfig(c *cli.Context) (*tls.Config, error) {
+	certPath := c.String(FlagTLSCertPath)
+	keyPath := c.String(FlagTLSKeyPath)
+	caPath := c.String(FlagTLSCaPath)
+	insecureSkipVerify := c.Bool(FlagTLSInsecureSkipVerify)
+	if certPath == "" && keyPath == "" && caPath == "" && !insecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if insecureSkipVerify {
+		if caPath != "" || (certPath != "" && keyPath == "") {
+			return nil, commoncli.Problem(fmt.Sprintf("--%s cannot be used together with a server CA certificate", FlagTLSInsecureSkipVerify), nil)
+		}
+		fmt.Fprintln(os.Stderr, colorRed("Warning:"), "server TLS certificate verification is disabled, do not use this in production")
+		tlsConfig.InsecureSkipVerify = true
+	}
+	var caPaths []string
+	if caPath != "" {
+		caPaths = append(caPaths, caPath)
+	}
+	if keyPath != "" {
+		if certPath == "" {
+			return nil, commoncli.Problem(fmt.Sprintf("--%s requires --%s with the client certificate", FlagTLSKeyPath, FlagTLSCertPath), nil)
+		}
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, commoncli.Problem(
+				fmt.Sprintf("unable to load client certificate, from --%s %q and --%s %q", FlagTLSCertPath, certPath, FlagTLSKeyPath, keyPath),
+				err,
+			)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	} else if certPath != "" {
+		caPaths = append(caPaths, certPath)
+	}
+
+	if len(caPaths) > 0 {
+		caCertPool := x509.NewCertPool()
+		for _, path := range caPaths {
+			caCert, err := os.ReadFile(path)
+			if err != nil {
+				return nil, commoncli.Problem(fmt.Sprintf("unable to find server CA certificate %q", path), err)
+			}
+			if !caCertPool.AppendCertsFromPEM(caCert) {
+				return nil, commoncli.Problem(fmt.Sprintf("failed to add server CA certificate %q", path), nil)
+			}
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	return tlsConfig, nil
+}
+
 func (b *clientFactory) newClientDispatcher(c *cli.Context, hostPortOverride string) (*yarpc.Dispatcher, error) {
 	shouldUseGrpc := c.String(FlagTransport) == grpcTransport
 
@@ -249,23 +333,12 @@ func (b *clientFactory) newClientDispatcher(c *cli.Context, hostPortOverride str
 		grpcTransport := grpc.NewTransport()
 		outbounds = transport.Outbounds{Unary: grpc.NewTransport().NewSingleOutbound(hostPort)}
 
-		tlsCertificatePath := c.String(FlagTLSCertPath)
-		if tlsCertificatePath != "" {
-			caCert, err := os.ReadFile(tlsCertificatePath)
-			if err != nil {
-				return nil, commoncli.Problem(
-					fmt.Sprintf("unable to find server CA certificate, from --%s %q", FlagTLSCertPath, tlsCertificatePath),
-					err,
-				)
-			}
-			caCertPool := x509.NewCertPool()
-			if !caCertPool.AppendCertsFromPEM(caCert) {
-				b.logger.Fatal("Failed to add server CA certificate", zap.Error(err))
-			}
-			tlsConfig := tls.Config{
-				RootCAs: caCertPool,
-			}
-			tlsCreds := credentials.NewTLS(&tlsConfig)
+		tlsConfig, err := newTLSConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig != nil {
+			tlsCreds := credentials.NewTLS(tlsConfig)
 			tlsChooser := peer.NewSingle(hostport.Identify(hostPort), grpcTransport.NewDialer(grpc.DialerCredentials(tlsCreds)))
 			outbounds = transport.Outbounds{Unary: grpc.NewTransport().NewOutbound(tlsChooser)}
 		}
@@ -312,8 +385,20 @@ func (vm *versionMiddleware) Call(ctx context.Context, request *transport.Reques
 	return out.Call(ctx, request)
 }
 
-func getJWT(c *cli.Context) string {
-	return c.String(FlagJWT)
+// getJWT returns the --jwt token, or else the token read from --jwt-file
+func getJWT(c *cli.Context) (string, error) {
+	if token := c.String(FlagJWT); token != "" {
+		return token, nil
+	}
+	path := c.String(FlagJWTFile)
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading JWT file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
 }
 
 func getJWTPrivateKey(c *cli.Context) string {
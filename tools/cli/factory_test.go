@@ -0,0 +1,180 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/uber/cadence/client/admin"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/tools/cli/clitest"
+)
+
+// writeTestCertificate writes a self-signed certificate and its key and returns their paths
+func writeTestCertificate(t *testing.T) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cadence-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+	return certPath, keyPath
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	app := NewCliApp(nil)
+	certPath, keyPath := writeTestCertificate(t)
+
+	t.Run("not configured", func(t *testing.T) {
+		cfg, err := newTLSConfig(clitest.NewCLIContext(t, app))
+		require.NoError(t, err)
+		assert.Nil(t, cfg)
+	})
+	t.Run("server CA only", func(t *testing.T) {
+		cfg, err := newTLSConfig(clitest.NewCLIContext(t, app, clitest.StringArgument(FlagTLSCertPath, certPath)))
+		require.NoError(t, err)
+		assert.NotNil(t, cfg.RootCAs)
+		assert.Empty(t, cfg.Certificates)
+	})
+	t.Run("mutual TLS with CA", func(t *testing.T) {
+		cfg, err := newTLSConfig(clitest.NewCLIContext(t, app,
+			clitest.StringArgument(FlagTLSCertPath, certPath),
+			clitest.StringArgument(FlagTLSKeyPath, keyPath),
+			clitest.StringArgument(FlagTLSCaPath, certPath),
+		))
+		require.NoError(t, err)
+		assert.NotNil(t, cfg.RootCAs)
+		assert.Len(t, cfg.Certificates, 1)
+	})
+	t.Run("mutual TLS with system roots", func(t *testing.T) {
+		cfg, err := newTLSConfig(clitest.NewCLIContext(t, app,
+			clitest.StringArgument(FlagTLSCertPath, certPath),
+			clitest.StringArgument(FlagTLSKeyPath, keyPath),
+		))
+		require.NoError(t, err)
+		assert.Nil(t, cfg.RootCAs)
+		assert.Len(t, cfg.Certificates, 1)
+	})
+	t.Run("key without certificate", func(t *testing.T) {
+		_, err := newTLSConfig(clitest.NewCLIContext(t, app, clitest.StringArgument(FlagTLSKeyPath, keyPath)))
+		assert.ErrorContains(t, err, "--tls_key_path requires --tls_cert_path")
+	})
+	t.Run("invalid CA", func(t *testing.T) {
+		_, err := newTLSConfig(clitest.NewCLIContext(t, app, clitest.StringArgument(FlagTLSCaPath, keyPath)))
+		assert.ErrorContains(t, err, "failed to add server CA certificate")
+	})
+	t.Run("insecure skip verify", func(t *testing.T) {
+		cfg, err := newTLSConfig(clitest.NewCLIContext(t, app, clitest.BoolArgument(FlagTLSInsecureSkipVerify, true)))
+		require.NoError(t, err)
+		assert.True(t, cfg.InsecureSkipVerify)
+		assert.Nil(t, cfg.RootCAs)
+	})
+	t.Run("insecure skip verify with client certificate", func(t *testing.T) {
+		cfg, err := newTLSConfig(clitest.NewCLIContext(t, app,
+			clitest.StringArgument(FlagTLSCertPath, certPath),
+			clitest.StringArgument(FlagTLSKeyPath, keyPath),
+			clitest.BoolArgument(FlagTLSInsecureSkipVerify, true),
+		))
+		require.NoError(t, err)
+		assert.True(t, cfg.InsecureSkipVerify)
+		assert.Len(t, cfg.Certificates, 1)
+	})
+	t.Run("insecure skip verify with CA", func(t *testing.T) {
+		_, err := newTLSConfig(clitest.NewCLIContext(t, app,
+			clitest.StringArgument(FlagTLSCaPath, certPath),
+			clitest.BoolArgument(FlagTLSInsecureSkipVerify, true),
+		))
+		assert.ErrorContains(t, err, "--insecure_skip_verify cannot be used together with a server CA certificate")
+	})
+}
+
+func TestWithRPCRetries(t *testing.T) {
+	app := NewCliApp(nil)
+	t.Run("no retries by default", func(t *testing.T) {
+		client := admin.NewMockClient(gomock.NewController(t))
+		c := clitest.NewCLIContext(t, app)
+		assert.Same(t, client, withRPCRetries(c, client))
+	})
+
+	t.Run("retries transient errors", func(t *testing.T) {
+		client := admin.NewMockClient(gomock.NewController(t))
+		gomock.InOrder(
+			client.EXPECT().DescribeCluster(gomock.Any()).Return(nil, &types.ServiceBusyError{}),
+			client.EXPECT().DescribeCluster(gomock.Any()).Return(&types.DescribeClusterResponse{}, nil),
+		)
+		c := clitest.NewCLIContext(t, app,
+			clitest.IntArgument(FlagRPCRetries, 1),
+			clitest.StringArgument(FlagRPCRetryInterval, "1ms"),
+		)
+		_, err := withRPCRetries(c, client).DescribeCluster(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("fails fast on non-transient errors", func(t *testing.T) {
+		client := admin.NewMockClient(gomock.NewController(t))
+		client.EXPECT().DescribeCluster(gomock.Any()).Return(nil, &types.EntityNotExistsError{}).Times(1)
+		c := clitest.NewCLIContext(t, app,
+			clitest.IntArgument(FlagRPCRetries, 3),
+			clitest.StringArgument(FlagRPCRetryInterval, "1ms"),
+		)
+		_, err := withRPCRetries(c, client).DescribeCluster(context.Background())
+		assert.IsType(t, &types.EntityNotExistsError{}, err)
+	})
+}
+
+func TestValidateTransport(t *testing.T) {
+	app := NewCliApp(nil)
+	for _, transport := range []string{"", grpcTransport, thriftTransport} {
+		c := clitest.NewCLIContext(t, app, clitest.StringArgument(FlagTransport, transport))
+		assert.NoError(t, validateTransport(c), transport)
+	}
+
+	err := app.Run([]string{"", "--transport", "htcp", "admin", "cluster", "describe"})
+	assert.ErrorContains(t, err, `Invalid --transport "htcp", valid values are "grpc" and "tchannel"`)
+}
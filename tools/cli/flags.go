@@ -46,10 +46,15 @@ const (
 	FlagShardID                        = "shard_id"
 	FlagShards                         = "shards"
 	FlagRangeID                        = "range_id"
+	FlagBump                           = "bump"
 	FlagWorkflowID                     = "workflow_id"
 	FlagRunID                          = "run_id"
 	FlagTreeID                         = "tree_id"
 	FlagBranchID                       = "branch_id"
+	FlagBranchToken                    = "branch_token"
+	FlagOtherTreeID                    = "other_tree_id"
+	FlagOtherBranchID                  = "other_branch_id"
+	FlagOtherBranchToken               = "other_branch_token"
 	FlagNumberOfShards                 = "number_of_shards"
 	FlagTargetCluster                  = "target_cluster"
 	FlagSourceCluster                  = "source_cluster"
@@ -65,6 +70,7 @@ const (
 	FlagExecutionTimeout               = "execution_timeout"
 	FlagDecisionTimeout                = "decision_timeout"
 	FlagContextTimeout                 = "context_timeout"
+	FlagTimeout                        = "timeout"
 	FlagInput                          = "input"
 	FlagInputFile                      = "input_file"
 	FlagInputEncoding                  = "encoding"
@@ -99,9 +105,12 @@ const (
 	FlagDeprecated                     = "deprecated"
 	FlagForce                          = "force"
 	FlagPageID                         = "page_id"
+	FlagImbalanceThreshold             = "imbalance_threshold"
 	FlagPageSize                       = "pagesize"
 	FlagEarliestTime                   = "earliest_time"
 	FlagLatestTime                     = "latest_time"
+	FlagStartTime                      = "start_time"
+	FlagEndTime                        = "end_time"
 	FlagPrintEventVersion              = "print_event_version"
 	FlagPrintFullyDetail               = "print_full"
 	FlagPrintRawTime                   = "print_raw_time"
@@ -110,6 +119,7 @@ const (
 	FlagPrintMemo                      = "print_memo"
 	FlagPrintSearchAttr                = "print_search_attr"
 	FlagPrintJSON                      = "print_json" // Deprecated: use --format json
+	FlagIDsOnly                        = "ids_only"
 	FlagDescription                    = "description"
 	FlagOwnerEmail                     = "owner_email"
 	FlagRetentionDays                  = "retention"
@@ -119,7 +129,15 @@ const (
 	FlagVisibilityArchivalURI          = "visibility_uri"
 	FlagName                           = "name"
 	FlagOutputFilename                 = "output_filename"
+	FlagInputGzip                      = "input_gzip"
+	FlagOutputGzip                     = "output_gzip"
+	FlagAppend                         = "append"
+	FlagNoColor                        = "no_color"
+	FlagCompact                        = "compact"
+	FlagRPCRetries                     = "rpc_retries"
+	FlagRPCRetryInterval               = "rpc_retry_interval"
 	FlagOutputFormat                   = "output"
+	FlagGlobalOutputFormat             = "output_format"
 	FlagQueryType                      = "query_type"
 	FlagQueryRejectCondition           = "query_reject_condition"
 	FlagQueryConsistencyLevel          = "query_consistency_level"
@@ -161,6 +179,9 @@ const (
 	FlagTaskType                       = "task_type"
 	FlagTaskVisibilityTimestamp        = "task_timestamp"
 	FlagQueueType                      = "queue_type"
+	FlagTransferAckLevel               = "transfer_ack_level"
+	FlagTimerAckLevel                  = "timer_ack_level"
+	FlagCurrentCluster                 = "current_cluster"
 	FlagStartingRPS                    = "starting_rps"
 	FlagRPS                            = "rps"
 	FlagRPSScaleUpSeconds              = "rps_scale_up_seconds"
@@ -173,6 +194,7 @@ const (
 	FlagTLSCertPath                    = "tls_cert_path"
 	FlagTLSKeyPath                     = "tls_key_path"
 	FlagTLSCaPath                      = "tls_ca_path"
+	FlagTLSInsecureSkipVerify          = "insecure_skip_verify"
 	FlagTLSEnableHostVerification      = "tls_enable_host_verification"
 	FlagDLQType                        = "dlq_type"
 	FlagMaxMessageCount                = "max_message_count"
@@ -181,14 +203,32 @@ const (
 	FlagReportRate                     = "report_rate"
 	FlagLowerShardBound                = "lower_shard_bound"
 	FlagUpperShardBound                = "upper_shard_bound"
+	FlagResumeFromShard                = "resume_from_shard"
+	FlagMaxShards                      = "max_shards"
+	FlagForceCurrent                   = "force_current"
+	FlagHistoryOnly                    = "history_only"
+	FlagMutableStateOnly               = "mutable_state_only"
+	FlagCurrentRowOnly                 = "current_row_only"
+	FlagShowTask                       = "show_task"
+	FlagDecode                         = "decode"
+	FlagFilterOwner                    = "filter_owner"
 	FlagInputDirectory                 = "input_directory"
 	FlagSkipHistoryChecks              = "skip_history_checks"
 	FlagFailoverType                   = "failover_type"
 	FlagFailoverTimeout                = "failover_timeout_seconds"
 	FlagActivityHeartBeatTimeout       = "heart_beat_timeout_seconds"
 	FlagFailoverWaitTime               = "failover_wait_time_second"
+	FlagFailoverWaitJitter             = "batch_wait_jitter"
 	FlagFailoverBatchSize              = "failover_batch_size"
 	FlagFailoverDomains                = "domains"
+	FlagFailoverDomainsFile            = "domains_file"
+	FlagFailoverAllDomains             = "all_domains"
+	FlagSkipClusterCheck               = "skip_cluster_check"
+	FlagRequestID                      = "request_id"
+	FlagTargetHost                     = "target_host"
+	FlagWaitTimeout                    = "wait_timeout"
+	FlagWait                           = "wait"
+	FlagOutputJSONL                    = "output_jsonl"
 	FlagFailoverDrillWaitTime          = "failover_drill_wait_second"
 	FlagFailoverDrill                  = "failover_drill"
 	FlagRetryInterval                  = "retry_interval"
@@ -211,6 +251,7 @@ const (
 	FlagConnectionAttributes           = "conn_attrs"
 	FlagJWT                            = "jwt"
 	FlagJWTPrivateKey                  = "jwt-private-key"
+	FlagJWTFile                        = "jwt-file"
 	FlagDynamicConfigName              = "name"
 	FlagDynamicConfigFilter            = "filter"
 	FlagDynamicConfigValue             = "value"
@@ -222,6 +263,37 @@ const (
 	FlagSearchAttribute                = "search_attr"
 	FlagNumReadPartitions              = "num_read_partitions"
 	FlagNumWritePartitions             = "num_write_partitions"
+	FlagPrometheusFile                 = "prometheus_file"
+	FlagDLQAware                       = "dlq_aware"
+	FlagOperator                       = "operator"
+	FlagFailOnEmpty                    = "fail_on_empty"
+	FlagAllPartitions                  = "all_partitions"
+	FlagWithStatus                     = "with_status"
+	FlagSortBy                         = "sort_by"
+	FlagSkipInvariant                  = "skip_invariant"
+	FlagOnlyCorrupted                  = "only_corrupted"
+	FlagInvariant                      = "invariant"
+	FlagPlugin                         = "plugin"
+	FlagDecisionChain                  = "decision_chain"
+	FlagDecodePayloads                 = "decode_payloads"
+	FlagTag                            = "tag"
+	FlagRaw                            = "raw"
+	FlagDecodeSearchAttributes         = "decode_search_attributes"
+	FlagShowTimers                     = "show_timers"
+	FlagHistory                        = "history"
+	FlagInterval                       = "interval"
+	FlagWatch                          = "watch"
+	FlagFollow                         = "follow"
+	FlagMetricsEndpoint                = "metrics_endpoint"
+	FlagJSONPath                       = "json_path"
+	FlagWithHost                       = "with_host"
+	FlagProfileCPU                     = "profile_cpu"
+	FlagProfileMem                     = "profile_mem"
+	FlagConfig                         = "config"
+	FlagProfile                        = "profile"
+	FlagEventType                      = "event_type"
+	FlagColor                          = "color"
+	FlagMaxEvents                      = "max_events"
 
 	FlagClustersUsage = "Clusters (example: --clusters clusterA,clusterB or --cl clusterA --cl clusterB)"
 )
@@ -554,10 +626,24 @@ func getCommonFlagsForVisibility() []cli.Flag {
 			Aliases: []string{"pjson"},
 			Usage:   "Print in raw json format (DEPRECATED: instead use --format json)",
 		},
+		idsOnlyFlag,
 		getFormatFlag(),
 	}
 }
 
+// timeoutFlag overrides --context_timeout for the RPC calls of a single slow command
+var timeoutFlag = &cli.DurationFlag{
+	Name:  FlagTimeout,
+	Usage: "optional timeout of the RPC calls of this command, e.g. 5m. Takes precedence over the global --" + FlagContextTimeout,
+}
+
+// idsOnlyFlag prints only the workflow IDs of listed workflows, one per line, so they can be piped into other commands
+var idsOnlyFlag = &cli.BoolFlag{
+	Name:    FlagIDsOnly,
+	Aliases: []string{"ids-only"},
+	Usage:   "Print only the workflow IDs, one per line, to pipe them into other commands. With paging, all pages are printed without prompting",
+}
+
 func getFlagsForList() []cli.Flag {
 	flagsForList := []cli.Flag{
 		&cli.BoolFlag{
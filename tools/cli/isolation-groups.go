@@ -94,7 +94,7 @@ func AdminUpdateGlobalIsolationGroups(c *cli.Context) error {
 		IsolationGroups: *cfg,
 	})
 	if err != nil {
-		return commoncli.Problem("failed to update isolation-groups", fmt.Errorf("used %#v, got %v", cfg, err))
+		return commoncli.Problem("failed to update isolation-groups", fmt.Errorf("used %#v, got %w", cfg, err))
 	}
 	return nil
 }
@@ -170,7 +170,7 @@ func AdminUpdateDomainIsolationGroups(c *cli.Context) error {
 	_, err = adminClient.UpdateDomainIsolationGroups(ctx, req)
 
 	if err != nil {
-		return commoncli.Problem("failed to update isolation-groups", fmt.Errorf("used %#v, got %v", req, err))
+		return commoncli.Problem("failed to update isolation-groups", fmt.Errorf("used %#v, got %w", req, err))
 	}
 	return nil
 }
@@ -0,0 +1,114 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cactus/go-statsd-client/statsd"
+	"github.com/uber-go/tally"
+	tallystatsdreporter "github.com/uber-go/tally/statsd"
+	"github.com/urfave/cli/v2"
+
+	statsdreporter "github.com/uber/cadence/common/metrics/tally/statsd"
+	"github.com/uber/cadence/tools/common/commoncli"
+)
+
+const (
+	// metricsPrefix is the prefix of the metrics emitted to --metrics_endpoint
+	metricsPrefix = "cadence_cli"
+	// metricsReportingInterval is how often the metrics are reported while a command runs
+	metricsReportingInterval = time.Second
+)
+
+// initMetrics starts reporting metrics to the statsd server of --metrics_endpoint, if set.
+// The metrics are the latency and success/failure of the command and of every RPC it makes.
+func initMetrics(c *cli.Context) error {
+	endpoint := c.String(FlagMetricsEndpoint)
+	if endpoint == "" {
+		return nil
+	}
+	d, ok := c.App.Metadata[depsKey].(*deps)
+	if !ok {
+		return nil
+	}
+	statter, err := statsd.NewClientWithConfig(&statsd.ClientConfig{
+		Address:     endpoint,
+		UseBuffered: true,
+	})
+	if err != nil {
+		return commoncli.Problem(fmt.Sprintf("Failed to create statsd client for --%s", FlagMetricsEndpoint), err)
+	}
+	scope, closer := tally.NewRootScope(tally.ScopeOptions{
+		Prefix:   metricsPrefix,
+		Reporter: statsdreporter.NewReporter(statter, tallystatsdreporter.Options{}),
+	}, metricsReportingInterval)
+	d.metricsScope = scope
+	d.closeMetrics = func() {
+		// closing the scope reports the remaining metrics, which the buffered statter sends when closed
+		closer.Close()
+		statter.Close()
+	}
+	return nil
+}
+
+// closeMetrics flushes the metrics of the command, if --metrics_endpoint is set
+func closeMetrics(c *cli.Context) error {
+	if d, ok := c.App.Metadata[depsKey].(*deps); ok && d.closeMetrics != nil {
+		d.closeMetrics()
+		d.closeMetrics = nil
+	}
+	return nil
+}
+
+// instrumentCommands wraps the actions of commands and their subcommands to emit the latency and result of
+// the command when --metrics_endpoint is set, tagged with the full name of the command, e.g. admin_workflow_show
+func instrumentCommands(commands []*cli.Command, parents ...string) {
+	for _, command := range commands {
+		names := append(append([]string{}, parents...), command.Name)
+		instrumentCommands(command.Subcommands, names...)
+		if command.Action == nil {
+			continue
+		}
+		action := command.Action
+		name := strings.Join(names, "_")
+		command.Action = func(c *cli.Context) error {
+			d, ok := c.App.Metadata[depsKey].(*deps)
+			if !ok || d.metricsScope == nil {
+				return action(c)
+			}
+			scope := d.metricsScope.Tagged(map[string]string{"command": name})
+			start := time.Now()
+			err := action(c)
+			scope.Timer("command_latency").Record(time.Since(start))
+			if err != nil {
+				scope.Counter("command_failures").Inc(1)
+			} else {
+				scope.Counter("command_successes").Inc(1)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v2"
+
+	"github.com/uber/cadence/tools/common/commoncli"
+)
+
+const defaultProfileName = "default"
+
+type (
+	// cliConfig is the file passed with --config, e.g.
+	//
+	//	profiles:
+	//	  prod:
+	//	    address: cadence-frontend.prod:7833
+	//	    transport: grpc
+	//	    tls_cert_path: /etc/cadence/ca.pem
+	//	    jwt_private_key: /etc/cadence/jwt.key
+	cliConfig struct {
+		Profiles map[string]cliProfile `yaml:"profiles"`
+	}
+
+	// cliProfile holds connection settings of a single cluster
+	cliProfile struct {
+		Address       string `yaml:"address"`
+		Transport     string `yaml:"transport"`
+		TLSCertPath   string `yaml:"tls_cert_path"`
+		TLSKeyPath    string `yaml:"tls_key_path"`
+		TLSCAPath     string `yaml:"tls_ca_path"`
+		JWT           string `yaml:"jwt"`
+		JWTPrivateKey string `yaml:"jwt_private_key"`
+	}
+)
+
+// loadProfile applies the connection settings of the selected profile to the global flags.
+// Settings given explicitly through flags or environment variables take precedence.
+func loadProfile(c *cli.Context) error {
+	configPath := c.String(FlagConfig)
+	profileName := c.String(FlagProfile)
+	if configPath == "" {
+		if profileName != "" {
+			return commoncli.Problem(fmt.Sprintf("--%s requires --%s", FlagProfile, FlagConfig), nil)
+		}
+		return nil
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return commoncli.Problem("Failed to read config file", err)
+	}
+	var config cliConfig
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return commoncli.Problem("Failed to parse config file "+configPath, err)
+	}
+
+	if profileName == "" {
+		profileName = defaultProfileName
+	}
+	profile, ok := config.Profiles[profileName]
+	if !ok {
+		if c.String(FlagProfile) == "" {
+			// no profile was requested and the config has no default one
+			return nil
+		}
+		return commoncli.Problem(fmt.Sprintf("Profile %q not found in %v", profileName, configPath), nil)
+	}
+
+	settings := map[string]string{
+		FlagAddress:       profile.Address,
+		FlagTransport:     profile.Transport,
+		FlagTLSCertPath:   profile.TLSCertPath,
+		FlagTLSKeyPath:    profile.TLSKeyPath,
+		FlagTLSCaPath:     profile.TLSCAPath,
+		FlagJWT:           profile.JWT,
+		FlagJWTPrivateKey: profile.JWTPrivateKey,
+	}
+	// the JWT flags are alternatives to each other, e.g. --jwt takes precedence over --jwt-file,
+	// so any of them given explicitly replaces all the JWT settings of the profile
+	if c.IsSet(FlagJWT) || c.IsSet(FlagJWTFile) || c.IsSet(FlagJWTPrivateKey) {
+		delete(settings, FlagJWT)
+		delete(settings, FlagJWTPrivateKey)
+	}
+	for flagName, value := range settings {
+		if value == "" || c.IsSet(flagName) {
+			continue
+		}
+		if err := c.Set(flagName, value); err != nil {
+			return commoncli.Problem("Failed to apply profile setting --"+flagName, err)
+		}
+	}
+	return nil
+}
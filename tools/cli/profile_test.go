@@ -0,0 +1,106 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+const testProfileConfig = `
+profiles:
+  default:
+    address: localhost:7933
+  prod:
+    address: cadence-frontend.prod:7833
+    transport: grpc
+    jwt: prod-token
+`
+
+func TestLoadProfile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(testProfileConfig), 0644))
+
+	run := func(t *testing.T, args ...string) (map[string]string, error) {
+		t.Helper()
+		got := map[string]string{}
+		app := NewCliApp(nil)
+		app.Commands = append(app.Commands, &cli.Command{
+			Name: "settings",
+			Action: func(c *cli.Context) error {
+				for _, name := range []string{FlagAddress, FlagTransport, FlagJWT, FlagJWTFile} {
+					got[name] = c.String(name)
+				}
+				return nil
+			},
+		})
+		err := app.Run(append(append([]string{""}, args...), "settings"))
+		return got, err
+	}
+
+	t.Run("default profile", func(t *testing.T) {
+		got, err := run(t, "--config", configPath)
+		require.NoError(t, err)
+		assert.Equal(t, "localhost:7933", got[FlagAddress])
+		assert.Equal(t, "", got[FlagTransport])
+	})
+	t.Run("selected profile", func(t *testing.T) {
+		got, err := run(t, "--config", configPath, "--profile", "prod")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			FlagAddress:   "cadence-frontend.prod:7833",
+			FlagTransport: "grpc",
+			FlagJWT:       "prod-token",
+			FlagJWTFile:   "",
+		}, got)
+	})
+	t.Run("jwt file overrides profile jwt", func(t *testing.T) {
+		got, err := run(t, "--config", configPath, "--profile", "prod", "--jwt-file", "/tok")
+		require.NoError(t, err)
+		assert.Equal(t, "", got[FlagJWT])
+		assert.Equal(t, "/tok", got[FlagJWTFile])
+	})
+	t.Run("flags override profile", func(t *testing.T) {
+		got, err := run(t, "--config", configPath, "--profile", "prod", "--address", "127.0.0.1:7833")
+		require.NoError(t, err)
+		assert.Equal(t, "127.0.0.1:7833", got[FlagAddress])
+		assert.Equal(t, "grpc", got[FlagTransport])
+	})
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := run(t, "--config", configPath, "--profile", "staging")
+		assert.ErrorContains(t, err, `Profile "staging" not found`)
+	})
+	t.Run("profile without config", func(t *testing.T) {
+		_, err := run(t, "--profile", "prod")
+		assert.ErrorContains(t, err, "--profile requires --config")
+	})
+	t.Run("missing config file", func(t *testing.T) {
+		_, err := run(t, "--config", filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.ErrorContains(t, err, "Failed to read config file")
+	})
+}
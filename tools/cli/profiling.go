@@ -0,0 +1,99 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/uber/cadence/tools/common/commoncli"
+)
+
+// startProfiling starts the CPU profiling of the command to --profile_cpu, if set.
+// The profile is written, along with the heap profile of --profile_mem, by stopProfiling when the command ends.
+func startProfiling(c *cli.Context) error {
+	d, ok := c.App.Metadata[depsKey].(*deps)
+	if !ok {
+		return nil
+	}
+	cpuProfile := c.String(FlagProfileCPU)
+	memProfile := c.String(FlagProfileMem)
+	if cpuProfile == "" && memProfile == "" {
+		return nil
+	}
+
+	var cpuFile *os.File
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			return commoncli.Problem(fmt.Sprintf("Failed to create --%s file", FlagProfileCPU), err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return commoncli.Problem("Failed to start CPU profiling", err)
+		}
+		cpuFile = f
+	}
+	d.stopProfiling = func() error {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			if err := cpuFile.Close(); err != nil {
+				return commoncli.Problem("Failed to write CPU profile", err)
+			}
+		}
+		if memProfile != "" {
+			return writeHeapProfile(memProfile)
+		}
+		return nil
+	}
+	return nil
+}
+
+// stopProfiling writes the profiles started by startProfiling
+func stopProfiling(c *cli.Context) error {
+	d, ok := c.App.Metadata[depsKey].(*deps)
+	if !ok || d.stopProfiling == nil {
+		return nil
+	}
+	stop := d.stopProfiling
+	d.stopProfiling = nil
+	return stop()
+}
+
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return commoncli.Problem(fmt.Sprintf("Failed to create --%s file", FlagProfileMem), err)
+	}
+	defer f.Close()
+	// collect garbage first so the profile shows the memory still in use
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		return commoncli.Problem("Failed to write heap profile", err)
+	}
+	return nil
+}
@@ -31,16 +31,20 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v2"
 )
 
 const (
 	formatTable = "table"
 	formatJSON  = "json"
+	formatYAML  = "yaml"
 
 	templateTable = "{{table .}}\n"
 	templateJSON  = "{{json .}}\n"
+	templateYAML  = "{{yaml .}}"
 
 	defaultSliceSeparator   = ", "
 	defaultMapSeparator     = ", "
@@ -72,11 +76,20 @@ type RenderOptions struct {
 	DefaultTemplate string
 }
 
-// Render is an entry point for presentation layer. It uses --format flag to determine output format.
+// getOutputFormat returns the output format requested by the command's --format flag,
+// falling back to the global --output_format flag.
+func getOutputFormat(c *cli.Context) string {
+	if format := c.String(FlagFormat); format != "" {
+		return format
+	}
+	return c.String(FlagGlobalOutputFormat)
+}
+
+// Render is an entry point for presentation layer. It uses --format (or global --output_format) flag to determine output format.
 func Render(c *cli.Context, data interface{}, opts RenderOptions) (err error) {
 	defer func() {
 		if err != nil {
-			fmt.Errorf("failed to render: %w", err)
+			err = fmt.Errorf("failed to render: %w", err)
 		}
 	}()
 
@@ -86,11 +99,13 @@ func Render(c *cli.Context, data interface{}, opts RenderOptions) (err error) {
 	template := opts.DefaultTemplate
 
 	// Handle template shorthands
-	switch format := c.String(FlagFormat); format {
+	switch format := getOutputFormat(c); format {
 	case formatJSON:
 		template = templateJSON
 	case formatTable:
 		template = templateTable
+	case formatYAML:
+		template = templateYAML
 	default:
 		if len(format) > 0 {
 			switch kind := reflect.ValueOf(data).Kind(); kind {
@@ -118,7 +133,11 @@ func RenderTemplate(w io.Writer, data interface{}, tmpl string, opts RenderOptio
 			return sb.String(), nil
 		},
 		"json": func(data interface{}) (string, error) {
-			encoded, err := json.MarshalIndent(data, "", "  ")
+			encoded, err := marshalOutputJSON(data)
+			return string(encoded), err
+		},
+		"yaml": func(data interface{}) (string, error) {
+			encoded, err := marshalYAML(data)
 			return string(encoded), err
 		},
 	}
@@ -131,6 +150,19 @@ func RenderTemplate(w io.Writer, data interface{}, tmpl string, opts RenderOptio
 	return t.Execute(w, data)
 }
 
+// marshalYAML encodes data as YAML. It goes through JSON first so that json tags and custom JSON marshalers are honored.
+func marshalYAML(data interface{}) ([]byte, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := yaml.Unmarshal(encoded, &decoded); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(decoded)
+}
+
 // RenderTable is generic function for rendering a slice of structs as a table
 func RenderTable(w io.Writer, data interface{}, opts RenderOptions) error {
 	value := reflect.ValueOf(data)
@@ -194,7 +226,7 @@ func RenderTable(w io.Writer, data interface{}, opts RenderOptions) error {
 		}
 		if r == 0 {
 			table.SetHeader(headers)
-			if opts.Color {
+			if opts.Color && !color.NoColor {
 				table.SetHeaderColor(colors...)
 			}
 		}
@@ -247,6 +279,12 @@ func formatValue(value interface{}, opts RenderOptions, tag reflect.StructTag) s
 		return formatSlice(value, opts)
 	case reflect.Map:
 		return formatMap(value, opts)
+	case reflect.Ptr:
+		// Nil pointer - value is not known, leave the cell empty
+		if v.IsNil() {
+			return ""
+		}
+		return formatValue(v.Elem().Interface(), opts, tag)
 	}
 
 	return fmt.Sprintf("%v", value)
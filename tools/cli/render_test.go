@@ -25,7 +25,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/tools/cli/clitest"
 )
 
 func Test_RenderTable(t *testing.T) {
@@ -102,6 +107,11 @@ func Test_RenderTable(t *testing.T) {
 	}
 }
 
+func Test_FormatValuePointer(t *testing.T) {
+	assert.Equal(t, "7", formatValue(common.Int64Ptr(7), RenderOptions{}, ""))
+	assert.Equal(t, "", formatValue((*int64)(nil), RenderOptions{}, ""))
+}
+
 func Test_RenderTemplate(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -129,6 +139,12 @@ func Test_RenderTemplate(t *testing.T) {
 			template:     "{{json .MapField}}",
 			expectOutput: "{\n  \"A\": \"AA\",\n  \"B\": \"BB\"\n}",
 		},
+		{
+			name:         "yaml function",
+			data:         testTable[0],
+			template:     "{{yaml .MapField}}",
+			expectOutput: "A: AA\nB: BB\n",
+		},
 		{
 			name:     "table function",
 			data:     testTable,
@@ -187,3 +203,67 @@ var testTable = []testRow{
 		SliceField:  nil,
 	},
 }
+
+func Test_GetOutputFormat(t *testing.T) {
+	app := NewCliApp(nil)
+
+	c := clitest.NewCLIContext(t, app)
+	assert.Equal(t, "", getOutputFormat(c))
+
+	c = clitest.NewCLIContext(t, app, clitest.StringArgument(FlagGlobalOutputFormat, formatYAML))
+	assert.Equal(t, formatYAML, getOutputFormat(c))
+
+	c = clitest.NewCLIContext(t, app, clitest.StringArgument(FlagGlobalOutputFormat, formatYAML), clitest.StringArgument(FlagFormat, formatJSON))
+	assert.Equal(t, formatJSON, getOutputFormat(c))
+}
+
+func Test_PrintObject(t *testing.T) {
+	td := newCLITestData(t)
+	data := struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}{"test", 3}
+
+	printObject(clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagGlobalOutputFormat, formatYAML)), data)
+	assert.Equal(t, "count: 3\nname: test\n", td.consoleOutput())
+
+	td = newCLITestData(t)
+	printObject(clitest.NewCLIContext(t, td.app), data)
+	assert.Equal(t, "{\n  \"name\": \"test\",\n  \"count\": 3\n}\n", td.consoleOutput())
+}
+
+func Test_ApplyOutputStyle(t *testing.T) {
+	oldNoColor := color.NoColor
+	defer func() {
+		color.NoColor = oldNoColor
+		compactJSON = false
+	}()
+
+	td := newCLITestData(t)
+	color.NoColor = false
+	applyOutputStyle(clitest.NewCLIContext(t, td.app, clitest.BoolArgument(FlagNoColor, true), clitest.BoolArgument(FlagCompact, true)))
+	assert.True(t, color.NoColor)
+	assert.True(t, compactJSON)
+
+	data := struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}{"test", 3}
+	printObject(clitest.NewCLIContext(t, td.app), data)
+	assert.Equal(t, "{\"name\":\"test\",\"count\":3}\n", td.consoleOutput())
+
+	td = newCLITestData(t)
+	err := Render(clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagGlobalOutputFormat, formatJSON)), []testRow{testTable[0]}, RenderOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, td.consoleOutput(), "\n  ")
+
+	var sb strings.Builder
+	require.NoError(t, RenderTable(&sb, testTable[0], RenderOptions{Color: true}))
+	assert.NotContains(t, sb.String(), "\x1b[")
+}
+
+func Test_RenderError(t *testing.T) {
+	td := newCLITestData(t)
+	err := Render(clitest.NewCLIContext(t, td.app, clitest.StringArgument(FlagFormat, "{{.Missing")), testTable, RenderOptions{})
+	assert.ErrorContains(t, err, "failed to render: invalid template")
+}
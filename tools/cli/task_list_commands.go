@@ -22,7 +22,7 @@ package cli
 
 import (
 	"io"
-	"os"
+	"sort"
 	"time"
 
 	"github.com/urfave/cli/v2"
@@ -36,6 +36,8 @@ type (
 		ActivityIdentity string    `header:"Activity Poller Identity"`
 		DecisionIdentity string    `header:"Decision Poller Identity"`
 		LastAccessTime   time.Time `header:"Last Access Time"`
+		LastAccess       string    `header:"Last Access"`
+		RatePerSecond    float64   `header:"Rate Per Second"`
 	}
 	TaskListPartitionRow struct {
 		ActivityPartition string `header:"Activity Task List Partition"`
@@ -87,22 +89,37 @@ func DescribeTaskList(c *cli.Context) error {
 
 // ListTaskListPartitions gets all the tasklist partition and host information.
 func ListTaskListPartitions(c *cli.Context) error {
-	frontendClient, err := getDeps(c).ServerFrontendClient(c)
+	response, err := listTaskListPartitions(c)
 	if err != nil {
 		return err
 	}
+	if len(response.DecisionTaskListPartitions) > 0 {
+		return printTaskListPartitions(getDeps(c).Output(), "Decision", response.DecisionTaskListPartitions)
+	}
+	if len(response.ActivityTaskListPartitions) > 0 {
+		return printTaskListPartitions(getDeps(c).Output(), "Activity", response.ActivityTaskListPartitions)
+	}
+	return nil
+}
+
+// listTaskListPartitions fetches the partitions of the tasklist given by the flags, along with the host owning each of them.
+func listTaskListPartitions(c *cli.Context) (*types.ListTaskListPartitionsResponse, error) {
+	frontendClient, err := getDeps(c).ServerFrontendClient(c)
+	if err != nil {
+		return nil, err
+	}
 	domain, err := getRequiredOption(c, FlagDomain)
 	if err != nil {
-		return commoncli.Problem("Required flag not found: ", err)
+		return nil, commoncli.Problem("Required flag not found: ", err)
 	}
 	taskList, err := getRequiredOption(c, FlagTaskList)
 	if err != nil {
-		return commoncli.Problem("Required flag not found: ", err)
+		return nil, commoncli.Problem("Required flag not found: ", err)
 	}
 	ctx, cancel, err := newContext(c)
 	defer cancel()
 	if err != nil {
-		return commoncli.Problem("Error in creating context:", err)
+		return nil, commoncli.Problem("Error in creating context:", err)
 	}
 	request := &types.ListTaskListPartitionsRequest{
 		Domain:   domain,
@@ -111,32 +128,35 @@ func ListTaskListPartitions(c *cli.Context) error {
 
 	response, err := frontendClient.ListTaskListPartitions(ctx, request)
 	if err != nil {
-		return commoncli.Problem("Operation ListTaskListPartitions failed.", err)
-	}
-	if len(response.DecisionTaskListPartitions) > 0 {
-		return printTaskListPartitions("Decision", response.DecisionTaskListPartitions)
-	}
-	if len(response.ActivityTaskListPartitions) > 0 {
-		return printTaskListPartitions("Activity", response.ActivityTaskListPartitions)
+		return nil, commoncli.Problem("Operation ListTaskListPartitions failed.", err)
 	}
-	return nil
+	return response, nil
 }
 
+// printTaskListPollers prints pollers, most recently seen first, so that pollers which stopped polling sink to the bottom.
 func printTaskListPollers(w io.Writer, pollers []*types.PollerInfo, taskListType types.TaskListType) error {
+	now := time.Now()
 	table := []TaskListPollerRow{}
 	for _, poller := range pollers {
+		lastAccessTime := time.Unix(0, poller.GetLastAccessTime())
 		table = append(table, TaskListPollerRow{
 			ActivityIdentity: poller.GetIdentity(),
 			DecisionIdentity: poller.GetIdentity(),
-			LastAccessTime:   time.Unix(0, poller.GetLastAccessTime())})
+			LastAccessTime:   lastAccessTime,
+			LastAccess:       formatTimeAgo(now, lastAccessTime),
+			RatePerSecond:    poller.GetRatePerSecond(),
+		})
 	}
+	sort.SliceStable(table, func(i, j int) bool {
+		return table[i].LastAccessTime.After(table[j].LastAccessTime)
+	})
 	return RenderTable(w, table, RenderOptions{Color: true, PrintDateTime: true, OptionalColumns: map[string]bool{
 		"Activity Poller Identity": taskListType == types.TaskListTypeActivity,
 		"Decision Poller Identity": taskListType == types.TaskListTypeDecision,
 	}})
 }
 
-func printTaskListPartitions(taskListType string, partitions []*types.TaskListPartitionMetadata) error {
+func printTaskListPartitions(w io.Writer, taskListType string, partitions []*types.TaskListPartitionMetadata) error {
 	table := []TaskListPartitionRow{}
 	for _, partition := range partitions {
 		table = append(table, TaskListPartitionRow{
@@ -145,8 +165,17 @@ func printTaskListPartitions(taskListType string, partitions []*types.TaskListPa
 			Host:              partition.GetOwnerHostName(),
 		})
 	}
-	return RenderTable(os.Stdout, table, RenderOptions{Color: true, OptionalColumns: map[string]bool{
+	return RenderTable(w, table, RenderOptions{Color: true, OptionalColumns: map[string]bool{
 		"Activity Task List Partition": taskListType == "Activity",
 		"Decision Task List Partition": taskListType == "Decision",
 	}})
 }
+
+// formatTimeAgo formats t relative to now, e.g. "12s ago".
+func formatTimeAgo(now, t time.Time) string {
+	d := now.Sub(t)
+	if d < 0 {
+		d = 0
+	}
+	return d.Round(time.Second).String() + " ago"
+}
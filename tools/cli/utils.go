@@ -48,6 +48,7 @@ import (
 	"github.com/uber/cadence/common/authorization"
 	"github.com/uber/cadence/common/pagination"
 	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/tools/common/commoncli"
 )
 
 // JSONHistorySerializer is used to encode history event in JSON
@@ -479,8 +480,41 @@ func getCurrentUserFromEnv() string {
 	return "unknown"
 }
 
+// printObject prints o as YAML when requested by --format/--output_format and as indented JSON otherwise.
+func printObject(c *cli.Context, o interface{}) {
+	w := getDeps(c).Output()
+	if getOutputFormat(c) == formatYAML {
+		b, err := marshalYAML(o)
+		if err == nil {
+			w.Write(b)
+			return
+		}
+		w.Write([]byte(fmt.Sprintf("Error when try to print yaml: %v\n", err)))
+	}
+	prettyPrintJSONObject(w, o)
+}
+
+// compactJSON is set from the global --compact flag and makes JSON output single-line.
+var compactJSON bool
+
+// applyOutputStyle reads the global --no_color and --compact flags once, before any command runs.
+func applyOutputStyle(c *cli.Context) {
+	if c.Bool(FlagNoColor) {
+		color.NoColor = true
+	}
+	compactJSON = c.Bool(FlagCompact)
+}
+
+// marshalOutputJSON encodes o as indented JSON, or as single-line JSON when --compact is set.
+func marshalOutputJSON(o interface{}) ([]byte, error) {
+	if compactJSON {
+		return json.Marshal(o)
+	}
+	return json.MarshalIndent(o, "", "  ")
+}
+
 func prettyPrintJSONObject(writer io.Writer, o interface{}) {
-	b, err := json.MarshalIndent(o, "", "  ")
+	b, err := marshalOutputJSON(o)
 	if err != nil {
 		writer.Write([]byte(fmt.Sprintf("Error when try to print pretty: %v\n", err)))
 		writer.Write([]byte(fmt.Sprintf("%+v\n", o)))
@@ -527,24 +561,29 @@ func getWorkflowClient(c *cli.Context) (frontend.Client, error) {
 	return getDeps(c).ServerFrontendClient(c)
 }
 
+// requiredOptionError is the error of a missing required option, which exits with commoncli.ExitCodeInvalidInput
+func requiredOptionError(optionName string) error {
+	return commoncli.ProblemWithExitCode(commoncli.ExitCodeInvalidInput, fmt.Sprintf("option %s is required", optionName), nil)
+}
+
 func getRequiredOption(c *cli.Context, optionName string) (string, error) {
 	value := c.String(optionName)
 	if len(value) == 0 {
-		return "", fmt.Errorf("option %s is required", optionName)
+		return "", requiredOptionError(optionName)
 	}
 	return value, nil
 }
 
 func getRequiredInt64Option(c *cli.Context, optionName string) (int64, error) {
 	if !c.IsSet(optionName) {
-		return 0, fmt.Errorf("option %s is required", optionName)
+		return 0, requiredOptionError(optionName)
 	}
 	return c.Int64(optionName), nil
 }
 
 func getRequiredIntOption(c *cli.Context, optionName string) (int, error) {
 	if !c.IsSet(optionName) {
-		return 0, fmt.Errorf("option %s is required", optionName)
+		return 0, requiredOptionError(optionName)
 	}
 	return c.Int(optionName), nil
 }
@@ -714,9 +753,11 @@ func getHostName() string {
 
 func processJWTFlags(ctx context.Context, cliCtx *cli.Context) (context.Context, error) {
 	path := getJWTPrivateKey(cliCtx)
-	t := getJWT(cliCtx)
+	t, err := getJWT(cliCtx)
+	if err != nil {
+		return nil, err
+	}
 	var token string
-	var err error
 
 	if t != "" {
 		token = t
@@ -765,10 +806,15 @@ func newIndefiniteContext(c *cli.Context) (context.Context, context.CancelFunc,
 	return ctx, cancel, nil
 }
 
+// newTimedContext creates a context with the --timeout of the command, or else the global --context_timeout,
+// or else the given timeout.
 func newTimedContext(c *cli.Context, timeout time.Duration) (context.Context, context.CancelFunc, error) {
 	if overrideTimeout := c.Int(FlagContextTimeout); overrideTimeout > 0 {
 		timeout = time.Duration(overrideTimeout) * time.Second
 	}
+	if commandTimeout := c.Duration(FlagTimeout); commandTimeout > 0 {
+		timeout = commandTimeout
+	}
 
 	ctx, err := populateContextFromCLIContext(c.Context, c)
 	if err != nil {
@@ -871,6 +917,70 @@ func validateJSONs(str string) error {
 	}
 }
 
+// jsonPathSegmentRegex matches a segment of a dotted JSON path, a field name with optional array indexes, e.g. Histories[0]
+var (
+	jsonPathSegmentRegex = regexp.MustCompile(`^([^\[\]]*)((?:\[\d+\])*)$`)
+	jsonPathIndexRegex   = regexp.MustCompile(`\d+`)
+)
+
+// lookupJSONPath returns the value at a dotted path such as ExecutionInfo.CloseStatus or VersionHistories.Histories[0].BranchToken
+// in the JSON encoding of o. A leading "$." is allowed, and array elements can also be selected with a numeric segment, e.g. Histories.0
+func lookupJSONPath(o interface{}, path string) (interface{}, error) {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return value, nil
+	}
+	var keys []string
+	for _, segment := range strings.Split(path, ".") {
+		match := jsonPathSegmentRegex.FindStringSubmatch(segment)
+		if match == nil {
+			return nil, fmt.Errorf("invalid path segment %q", segment)
+		}
+		if match[1] != "" {
+			keys = append(keys, match[1])
+		}
+		for _, index := range jsonPathIndexRegex.FindAllString(match[2], -1) {
+			keys = append(keys, index)
+		}
+	}
+
+	visited := "$"
+	for _, key := range keys {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			field, ok := v[key]
+			if !ok {
+				return nil, fmt.Errorf("no field %q in %v", key, visited)
+			}
+			value = field
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil {
+				return nil, fmt.Errorf("%v is an array, %q is not an index", visited, key)
+			}
+			if index >= len(v) {
+				return nil, fmt.Errorf("index %d out of range of %v with %d elements", index, visited, len(v))
+			}
+			value = v[index]
+		default:
+			return nil, fmt.Errorf("no field %q in %v, which is not an object", key, visited)
+		}
+		visited += "." + key
+	}
+	return value, nil
+}
+
 // use parseBool to ensure all BOOL search attributes only be "true" or "false"
 func parseBool(str string) (bool, error) {
 	switch str {
@@ -972,6 +1082,15 @@ func prompt(msg string) {
 		os.Exit(0)
 	}
 }
+
+// confirm shows msg, then reads the answer from the command input and reports whether it is y/yes
+func confirm(c *cli.Context, msg string) bool {
+	fmt.Fprintf(getDeps(c).Progress(), "%s y/N: ", msg)
+	text, _ := bufio.NewReader(getDeps(c).Input()).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(text))
+	return answer == "y" || answer == "yes"
+}
+
 func getInputFile(inputFile string) (*os.File, error) {
 	if len(inputFile) == 0 {
 		info, err := os.Stdin.Stat()
@@ -1037,7 +1156,7 @@ func serializeSearchAttributes(input map[string]interface{}) (*types.SearchAttri
 	for k, v := range input {
 		attrBytes, err := json.Marshal(v)
 		if err != nil {
-			return nil, fmt.Errorf("encode search attribute [%s] error: %v", k, err)
+			return nil, fmt.Errorf("encode search attribute [%s] error: %w", k, err)
 		}
 		attr[k] = attrBytes
 	}
@@ -1061,17 +1180,17 @@ func parseIntMultiRange(s string) ([]int, error) {
 		case 1:
 			i, err := strconv.Atoi(strings.TrimSpace(parts[0]))
 			if err != nil {
-				return nil, fmt.Errorf("single number %q: %v", r, err)
+				return nil, fmt.Errorf("single number %q: %w", r, err)
 			}
 			set[i] = struct{}{}
 		case 2:
 			lower, err := strconv.Atoi(strings.TrimSpace(parts[0]))
 			if err != nil {
-				return nil, fmt.Errorf("lower range of %q: %v", r, err)
+				return nil, fmt.Errorf("lower range of %q: %w", r, err)
 			}
 			upper, err := strconv.Atoi(strings.TrimSpace(parts[1]))
 			if err != nil {
-				return nil, fmt.Errorf("upper range of %q: %v", r, err)
+				return nil, fmt.Errorf("upper range of %q: %w", r, err)
 			}
 			for i := lower; i <= upper; i++ {
 				set[i] = struct{}{}
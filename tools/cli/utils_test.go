@@ -22,6 +22,7 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -30,6 +31,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -42,6 +44,7 @@ import (
 	"github.com/uber/cadence/common/authorization"
 	"github.com/uber/cadence/common/testing/testdatagen/idlfuzzedtestdata"
 	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/tools/cli/clitest"
 )
 
 func Test_JSONHistorySerializer(t *testing.T) {
@@ -673,6 +676,40 @@ func TestGetRequiredInt64Option(t *testing.T) {
 	}
 }
 
+func TestNewTimedContext(t *testing.T) {
+	tests := map[string]struct {
+		args     []clitest.CliArgument
+		expected time.Duration
+	}{
+		"default": {
+			expected: time.Minute,
+		},
+		"global context timeout": {
+			args:     []clitest.CliArgument{clitest.IntArgument(FlagContextTimeout, 10)},
+			expected: 10 * time.Second,
+		},
+		"command timeout takes precedence": {
+			args: []clitest.CliArgument{
+				clitest.IntArgument(FlagContextTimeout, 10),
+				clitest.StringArgument(FlagTimeout, "5m"),
+			},
+			expected: 5 * time.Minute,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := clitest.NewCLIContext(t, newCLITestData(t).app, tt.args...)
+			ctx, cancel, err := newTimedContext(c, time.Minute)
+			require.NoError(t, err)
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(tt.expected), deadline, 5*time.Second)
+		})
+	}
+}
+
 func TestParseSingleTs(t *testing.T) {
 	// Test with a valid timestamp format
 	validInput := "2023-10-31T14:45:30"
@@ -860,3 +897,83 @@ func TestCreateJWT(t *testing.T) {
 	assert.WithinDuration(t, time.Now(), claims.IssuedAt.Time, time.Second)
 	assert.WithinDuration(t, time.Now().Add(10*time.Minute), claims.ExpiresAt.Time, time.Second)
 }
+
+func TestProcessJWTFlags(t *testing.T) {
+	tokenFile := createTempFileWithContent(t, "  file-token\n")
+	tests := []struct {
+		name          string
+		args          []clitest.CliArgument
+		expectedToken string
+		errContains   string
+	}{
+		{
+			name:          "inline token",
+			args:          []clitest.CliArgument{clitest.StringArgument(FlagJWT, "inline-token")},
+			expectedToken: "inline-token",
+		},
+		{
+			name:          "token file",
+			args:          []clitest.CliArgument{clitest.StringArgument(FlagJWTFile, tokenFile)},
+			expectedToken: "file-token",
+		},
+		{
+			name: "inline token has priority over the token file",
+			args: []clitest.CliArgument{
+				clitest.StringArgument(FlagJWT, "inline-token"),
+				clitest.StringArgument(FlagJWTFile, tokenFile),
+			},
+			expectedToken: "inline-token",
+		},
+		{
+			name:        "missing token file",
+			args:        []clitest.CliArgument{clitest.StringArgument(FlagJWTFile, filepath.Join(t.TempDir(), "missing"))},
+			errContains: "error reading JWT file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newCLITestData(t)
+			ctx, err := processJWTFlags(context.Background(), clitest.NewCLIContext(t, td.app, tt.args...))
+			if tt.errContains != "" {
+				assert.ErrorContains(t, err, tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedToken, ctx.Value(CtxKeyJWT))
+		})
+	}
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	o := map[string]interface{}{
+		"ExecutionInfo": map[string]interface{}{"CloseStatus": 2, "WorkflowID": "wid"},
+		"VersionHistories": map[string]interface{}{
+			"Histories": []map[string]interface{}{{"BranchToken": "token-0"}, {"BranchToken": "token-1"}},
+		},
+	}
+	tests := []struct {
+		path        string
+		expected    interface{}
+		errContains string
+	}{
+		{path: "ExecutionInfo.WorkflowID", expected: "wid"},
+		{path: "$.ExecutionInfo.CloseStatus", expected: json.Number("2")},
+		{path: "VersionHistories.Histories[1].BranchToken", expected: "token-1"},
+		{path: "VersionHistories.Histories.0.BranchToken", expected: "token-0"},
+		{path: "ExecutionInfo.RunID", errContains: `no field "RunID" in $.ExecutionInfo`},
+		{path: "VersionHistories.Histories[2]", errContains: "index 2 out of range of $.VersionHistories.Histories with 2 elements"},
+		{path: "ExecutionInfo.WorkflowID.Length", errContains: "which is not an object"},
+		{path: "VersionHistories.Histories[x]", errContains: `invalid path segment "Histories[x]"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, err := lookupJSONPath(o, tt.path)
+			if tt.errContains != "" {
+				assert.ErrorContains(t, err, tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
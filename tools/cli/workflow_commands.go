@@ -172,6 +172,9 @@ func showHistoryHelper(c *cli.Context, wid, rid string) error {
 	printFully := c.Bool(FlagPrintFullyDetail)
 	printVersion := c.Bool(FlagPrintEventVersion)
 	outputFileName := c.String(FlagOutputFilename)
+	if c.Bool(FlagAppend) && outputFileName != "" {
+		return commoncli.Problem(fmt.Sprintf("--%s is not supported, appending a JSON array to the output file makes it invalid JSON", FlagAppend), nil)
+	}
 	var maxFieldLength int
 	if c.IsSet(FlagMaxFieldLength) || !printFully {
 		maxFieldLength = c.Int(FlagMaxFieldLength)
@@ -244,7 +247,7 @@ func showHistoryHelper(c *cli.Context, wid, rid string) error {
 		if err != nil {
 			return commoncli.Problem("Failed to serialize history data.", err)
 		}
-		if err := os.WriteFile(outputFileName, data, 0666); err != nil {
+		if err := writeOutputFile(outputFileName, data, false); err != nil {
 			return commoncli.Problem("Failed to export history data file.", err)
 		}
 	}
@@ -493,14 +496,14 @@ func processSearchAttr(c *cli.Context) (map[string][]byte, error) {
 	}
 
 	if len(searchAttrKeys) != len(searchAttrVals) {
-		return nil, fmt.Errorf("Number of search attributes keys and values are not equal. %v", nil)
+		return nil, errors.New("Number of search attributes keys and values are not equal")
 	}
 
 	fields := map[string][]byte{}
 	for i, key := range searchAttrKeys {
 		val, err := json.Marshal(searchAttrVals[i])
 		if err != nil {
-			return nil, fmt.Errorf("Encode value %v error. Error %v", val, err)
+			return nil, fmt.Errorf("Encode value %v error: %w", searchAttrVals[i], err)
 		}
 		fields[key] = val
 	}
@@ -1454,7 +1457,8 @@ func displayPagedWorkflows(c *cli.Context, getWorkflowPage getWorkflowPageFn, fi
 		if len(nextPageToken) == 0 {
 			break
 		}
-		if !showNextPage(output) {
+		// there is nobody to answer the prompt when the IDs are piped into another command
+		if !c.Bool(FlagIDsOnly) && !showNextPage(output) {
 			break
 		}
 	}
@@ -1470,6 +1474,13 @@ func displayAllWorkflows(c *cli.Context, getWorkflowsPage getWorkflowPageFn) err
 }
 
 func displayWorkflows(c *cli.Context, workflows []*types.WorkflowExecutionInfo) error {
+	if c.Bool(FlagIDsOnly) {
+		output := getDeps(c).Output()
+		for _, workflow := range workflows {
+			fmt.Fprintln(output, workflow.GetExecution().GetWorkflowID())
+		}
+		return nil
+	}
 	printJSON := c.Bool(FlagPrintJSON)
 	printDecodedRaw := c.Bool(FlagPrintFullyDetail)
 
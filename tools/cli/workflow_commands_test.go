@@ -2204,6 +2204,14 @@ func Test_ObserveHistory_MissingFlags(t *testing.T) {
 	assert.ErrorContains(t, err, fmt.Sprintf("%s is required", FlagWorkflowID))
 }
 
+func Test_ShowHistoryHelper_Append(t *testing.T) {
+	app := NewCliApp(&clientFactoryMock{})
+	ctx := clitest.NewCLIContext(t, app, clitest.StringArgument(FlagDomain, "test-domain"),
+		clitest.StringArgument(FlagOutputFilename, "test-file"), clitest.BoolArgument(FlagAppend, true))
+	err := showHistoryHelper(ctx, "test-workflow-id", "test-run-id")
+	assert.ErrorContains(t, err, "--append is not supported")
+}
+
 func Test_ShowHistoryHelper(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	serverFrontendClient := frontend.NewMockClient(mockCtrl)
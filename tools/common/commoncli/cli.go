@@ -23,6 +23,7 @@
 package commoncli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -30,6 +31,10 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+	"go.uber.org/yarpc/yarpcerrors"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/types"
 )
 
 var (
@@ -56,7 +61,38 @@ func ExitHandler(err error) {
 
 	// all errors are "fatal", unlike default behavior which only fails if you
 	// return an ExitCoder error.
-	os.Exit(1)
+	os.Exit(exitCode(err))
+}
+
+// Exit codes used by ExitHandler, so scripts can tell failure categories apart.
+// Codes requested with ProblemWithExitCode take precedence.
+const (
+	ExitCodeFailure      = 1 // any failure not covered below
+	ExitCodeInvalidInput = 2 // the request was rejected as invalid, by the CLI or by the server
+	ExitCodeNotFound     = 3 // the requested entity does not exist
+	ExitCodeEmpty        = 4 // the requested entity exists but has nothing to show; only requested with ProblemWithExitCode, e.g. by --fail_on_empty
+	ExitCodeTransient    = 5 // the server was busy, unavailable, timed out or failed internally; retrying may succeed
+)
+
+// exitCode returns the exit code requested by the outermost Problem in err that requests one,
+// or else a code for the category of the underlying error.
+func exitCode(err error) int {
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		if perr, ok := current.(*printableErr); ok && perr.exitCode != 0 {
+			return perr.exitCode
+		}
+	}
+	switch {
+	case errors.As(err, new(*types.BadRequestError)):
+		return ExitCodeInvalidInput
+	case errors.As(err, new(*types.EntityNotExistsError)):
+		return ExitCodeNotFound
+	case common.IsServiceTransientError(err),
+		errors.Is(err, context.DeadlineExceeded),
+		yarpcerrors.IsDeadlineExceeded(err):
+		return ExitCodeTransient
+	}
+	return ExitCodeFailure
 }
 
 // prints this (possibly printable) error to the given io.Writer.
@@ -182,12 +218,19 @@ func printErr(err error, to io.Writer) (writeErr error) {
 //	  ErrorDetails:
 //	    more nested errors
 func Problem(msg string, err error) error {
-	return &printableErr{msg, err}
+	return &printableErr{display: msg, cause: err}
+}
+
+// ProblemWithExitCode is like Problem, but makes ExitHandler exit with the given
+// code rather than 1, so scripts can tell e.g. "not found" apart from other failures.
+func ProblemWithExitCode(code int, msg string, err error) error {
+	return &printableErr{display: msg, cause: err, exitCode: code}
 }
 
 type printableErr struct {
-	display string
-	cause   error
+	display  string
+	cause    error
+	exitCode int
 }
 
 func (p *printableErr) Error() string {
@@ -23,12 +23,16 @@
 package commoncli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/yarpc/yarpcerrors"
+
+	"github.com/uber/cadence/common/types"
 )
 
 func TestPrintErr(t *testing.T) {
@@ -147,3 +151,16 @@ Error details:
 `, str)
 	})
 }
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 1, exitCode(errors.New("plain")))
+	assert.Equal(t, 1, exitCode(Problem("a problem", nil)))
+	assert.Equal(t, 3, exitCode(ProblemWithExitCode(3, "not found", nil)))
+	assert.Equal(t, 3, exitCode(fmt.Errorf("wrapper: %w", ProblemWithExitCode(3, "not found", nil))))
+	assert.Equal(t, 4, exitCode(Problem("outer", ProblemWithExitCode(4, "inner", nil))))
+	assert.Equal(t, ExitCodeInvalidInput, exitCode(Problem("rejected", &types.BadRequestError{Message: "bad"})))
+	assert.Equal(t, ExitCodeNotFound, exitCode(Problem("missing", &types.EntityNotExistsError{Message: "gone"})))
+	assert.Equal(t, ExitCodeTransient, exitCode(Problem("busy", &types.ServiceBusyError{Message: "busy"})))
+	assert.Equal(t, ExitCodeTransient, exitCode(Problem("unavailable", yarpcerrors.UnavailableErrorf("down"))))
+	assert.Equal(t, ExitCodeTransient, exitCode(fmt.Errorf("slow: %w", context.DeadlineExceeded)))
+}